@@ -0,0 +1,279 @@
+// Package dnsmigrate migrates Config DNS zones from the v1 zone model to v2
+//
+// A v1 dns.Zone holds a slice of records per type; v2 holds recordsets of
+// fully qualified names. Convert() maps one to the other, Migrate() creates
+// or updates the zone in v2 and VerifyParity() compares the result
+// record-for-record.
+package dnsmigrate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v1"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+)
+
+// Init sets the Config DNS v1 and v2 edgegrid Config
+func Init(config edgegrid.Config) {
+	dns.Init(config)
+	dnsv2.Init(config)
+}
+
+// Conversion is a v1 zone converted to v2 recordsets
+type Conversion struct {
+	Zone       string
+	Recordsets []dnsv2.Recordset
+	// Unmapped are the v1 records that could not be converted
+	Unmapped []*UnmappedRecord
+	// Warnings describe records that were converted with changes
+	Warnings []string
+}
+
+// UnmappedRecord is a v1 record that has no v2 equivalent
+type UnmappedRecord struct {
+	Type   string
+	Name   string
+	Reason string
+}
+
+// v1Record is the common form of the typed v1 records
+type v1Record struct {
+	recordType string
+	name       string
+	ttl        int
+	active     bool
+	rdata      string
+}
+
+// Convert maps a v1 zone to v2 recordsets
+//
+// Record names and the domain names within rdata are made fully qualified.
+// Records of the same name and type are merged into a single recordset using
+// the TTL of the first record. Inactive records, DNSSEC signature records and
+// records whose rdata fails validation are reported in Conversion.Unmapped.
+func Convert(zone *dns.Zone) *Conversion {
+	origin := strings.TrimSuffix(zone.Zone.Name, ".")
+	conversion := &Conversion{Zone: origin}
+
+	index := map[string]int{}
+	for _, record := range v1Records(zone, origin) {
+		name := qualify(record.name, origin)
+
+		if !record.active && record.recordType != "SOA" {
+			conversion.unmapped(record.recordType, name, "record is inactive")
+			continue
+		}
+
+		switch record.recordType {
+		case "RRSIG", "NSEC3":
+			conversion.unmapped(record.recordType, name, "signature records are generated by sign-and-serve")
+			continue
+		}
+
+		typed, err := dnsv2.ParseRdata(record.recordType, record.rdata)
+		if err == nil {
+			err = typed.Validate()
+		}
+		if err != nil {
+			conversion.unmapped(record.recordType, name, err.Error())
+			continue
+		}
+
+		key := strings.ToLower(name) + " " + record.recordType
+		if i, ok := index[key]; ok {
+			recordset := &conversion.Recordsets[i]
+			if recordset.TTL != record.ttl {
+				conversion.Warnings = append(conversion.Warnings, fmt.Sprintf("%s %s: TTL %d changed to %d, the TTL of the recordset", name, record.recordType, record.ttl, recordset.TTL))
+			}
+			recordset.Rdata = append(recordset.Rdata, typed.String())
+			continue
+		}
+
+		index[key] = len(conversion.Recordsets)
+		conversion.Recordsets = append(conversion.Recordsets, dnsv2.Recordset{
+			Name:  name,
+			Type:  record.recordType,
+			TTL:   record.ttl,
+			Rdata: []string{typed.String()},
+		})
+	}
+
+	return conversion
+}
+
+// Migrate converts a v1 zone and saves it as a v2 primary zone
+//
+// The zone is created in the contract and group of queryString if it does
+// not exist in v2, and its recordsets are then replaced by the converted
+// recordsets, except for the SOA record which is left to Edge DNS. Zones of
+// more than dnsv2.DefaultRecordsetChunkSize recordsets are replaced
+// atomically through a changelist, see dnsv2.ReplaceRecordsets().
+// Unmapped records are not migrated, see Conversion.Unmapped.
+func Migrate(zone *dns.Zone, queryString dnsv2.ZoneQueryString, comment string) (*Conversion, error) {
+	conversion := Convert(zone)
+
+	_, err := dnsv2.GetZone(conversion.Zone)
+	if zoneErr, ok := err.(*dnsv2.ZoneError); ok && zoneErr.NotFound() {
+		v2Zone := dnsv2.NewZone(dnsv2.ZoneCreate{Zone: conversion.Zone, Type: "PRIMARY", Comment: comment})
+		if err := v2Zone.Save(queryString); err != nil {
+			return conversion, err
+		}
+	} else if err != nil {
+		return conversion, err
+	}
+
+	if err := dnsv2.ReplaceRecordsets(conversion.Zone, conversion.Recordsets, 0); err != nil {
+		return conversion, err
+	}
+
+	return conversion, nil
+}
+
+// VerifyParity compares the converted recordsets with the v2 zone
+//
+// The returned plan lists every difference; an empty plan means the v2 zone
+// matches the conversion record-for-record. SOA records are not compared as
+// v2 manages the serial.
+func VerifyParity(conversion *Conversion) (*dnsv2.ZoneSyncPlan, error) {
+	return dnsv2.PlanZoneSync(conversion.Zone, conversion.Recordsets, dnsv2.ZoneSyncOptions{Prune: true})
+}
+
+// Report formats the unmapped records and warnings of a conversion, one per line
+func (conversion *Conversion) Report() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s: %d recordsets converted, %d records not mapped\n", conversion.Zone, len(conversion.Recordsets), len(conversion.Unmapped))
+	for _, record := range conversion.Unmapped {
+		fmt.Fprintf(&builder, "not mapped: %s %s: %s\n", record.Name, record.Type, record.Reason)
+	}
+	for _, warning := range conversion.Warnings {
+		fmt.Fprintf(&builder, "warning: %s\n", warning)
+	}
+
+	return builder.String()
+}
+
+func (conversion *Conversion) unmapped(recordType string, name string, reason string) {
+	conversion.Unmapped = append(conversion.Unmapped, &UnmappedRecord{Type: recordType, Name: name, Reason: reason})
+}
+
+// qualify makes a v1 name fully qualified, without a trailing dot
+func qualify(name string, origin string) string {
+	switch {
+	case name == "" || name == "@":
+		return origin
+	case strings.HasSuffix(name, "."):
+		return strings.TrimSuffix(name, ".")
+	}
+
+	return name + "." + origin
+}
+
+// quote formats a v1 text value as a quoted character string
+//
+// Values longer than 255 characters are split into several strings.
+func quote(value string) string {
+	if strings.HasPrefix(value, "\"") {
+		return value
+	}
+
+	var quoted []string
+	for len(value) > 255 {
+		quoted = append(quoted, quote(value[:255]))
+		value = value[255:]
+	}
+
+	escaped := strings.Replace(strings.Replace(value, "\\", "\\\\", -1), "\"", "\\\"", -1)
+
+	return strings.Join(append(quoted, "\""+escaped+"\""), " ")
+}
+
+// replacement qualifies a NAPTR replacement, "." meaning none
+func replacement(name string, fqdn func(string) string) string {
+	if name == "." || name == "" {
+		return "."
+	}
+
+	return fqdn(name)
+}
+
+// v1Records flattens the per-type slices of a v1 zone, qualifying the domain
+// names within rdata
+func v1Records(zone *dns.Zone, origin string) []*v1Record {
+	var records []*v1Record
+	add := func(recordType string, name string, ttl int, active bool, rdata string) {
+		records = append(records, &v1Record{recordType: recordType, name: name, ttl: ttl, active: active, rdata: rdata})
+	}
+	fqdn := func(name string) string {
+		return qualify(name, origin) + "."
+	}
+	itoa := strconv.Itoa
+
+	if soa := zone.Zone.Soa; soa != nil {
+		add("SOA", "", soa.TTL, true, fmt.Sprintf("%s %s %d %d %d %d %d", fqdn(soa.Originserver), fqdn(soa.Contact), soa.Serial, soa.Refresh, soa.Retry, soa.Expire, soa.Minimum))
+	}
+	for _, r := range zone.Zone.Ns {
+		add("NS", r.Name, r.TTL, r.Active, fqdn(r.Target))
+	}
+	for _, r := range zone.Zone.A {
+		add("A", r.Name, r.TTL, r.Active, r.Target)
+	}
+	for _, r := range zone.Zone.Aaaa {
+		add("AAAA", r.Name, r.TTL, r.Active, r.Target)
+	}
+	for _, r := range zone.Zone.Afsdb {
+		add("AFSDB", r.Name, r.TTL, r.Active, itoa(r.Subtype)+" "+fqdn(r.Target))
+	}
+	for _, r := range zone.Zone.Cname {
+		add("CNAME", r.Name, r.TTL, r.Active, fqdn(r.Target))
+	}
+	for _, r := range zone.Zone.Dnskey {
+		add("DNSKEY", r.Name, r.TTL, r.Active, fmt.Sprintf("%d %d %d %s", r.Flags, r.Protocol, r.Algorithm, r.Key))
+	}
+	for _, r := range zone.Zone.Ds {
+		add("DS", r.Name, r.TTL, r.Active, fmt.Sprintf("%d %d %d %s", r.Keytag, r.Algorithm, r.DigestType, r.Digest))
+	}
+	for _, r := range zone.Zone.Hinfo {
+		add("HINFO", r.Name, r.TTL, r.Active, quote(r.Hardware)+" "+quote(r.Software))
+	}
+	for _, r := range zone.Zone.Loc {
+		add("LOC", r.Name, r.TTL, r.Active, r.Target)
+	}
+	for _, r := range zone.Zone.Mx {
+		add("MX", r.Name, r.TTL, r.Active, itoa(r.Priority)+" "+fqdn(r.Target))
+	}
+	for _, r := range zone.Zone.Naptr {
+		add("NAPTR", r.Name, r.TTL, r.Active, fmt.Sprintf("%d %d %s %s %s %s", r.Order, r.Preference, quote(r.Flags), quote(r.Service), quote(r.Regexp), replacement(r.Replacement, fqdn)))
+	}
+	for _, r := range zone.Zone.Nsec3 {
+		add("NSEC3", r.Name, r.TTL, r.Active, fmt.Sprintf("%d %d %d %s %s %s", r.Algorithm, r.Flags, r.Iterations, r.Salt, r.NextHashedOwnerName, r.TypeBitmaps))
+	}
+	for _, r := range zone.Zone.Nsec3param {
+		add("NSEC3PARAM", r.Name, r.TTL, r.Active, fmt.Sprintf("%d %d %d %s", r.Algorithm, r.Flags, r.Iterations, r.Salt))
+	}
+	for _, r := range zone.Zone.Ptr {
+		add("PTR", r.Name, r.TTL, r.Active, fqdn(r.Target))
+	}
+	for _, r := range zone.Zone.Rp {
+		add("RP", r.Name, r.TTL, r.Active, fqdn(r.Mailbox)+" "+fqdn(r.Txt))
+	}
+	for _, r := range zone.Zone.Rrsig {
+		add("RRSIG", r.Name, r.TTL, r.Active, fmt.Sprintf("%s %d %d %d %s %s %d %s %s", r.TypeCovered, r.Algorithm, r.Labels, r.OriginalTTL, r.Expiration, r.Inception, r.Keytag, r.Signer, r.Signature))
+	}
+	for _, r := range zone.Zone.Spf {
+		add("SPF", r.Name, r.TTL, r.Active, quote(r.Target))
+	}
+	for _, r := range zone.Zone.Srv {
+		add("SRV", r.Name, r.TTL, r.Active, fmt.Sprintf("%d %d %d %s", r.Priority, r.Weight, r.Port, fqdn(r.Target)))
+	}
+	for _, r := range zone.Zone.Sshfp {
+		add("SSHFP", r.Name, r.TTL, r.Active, fmt.Sprintf("%d %d %s", r.Algorithm, r.FingerprintType, r.Fingerprint))
+	}
+	for _, r := range zone.Zone.Txt {
+		add("TXT", r.Name, r.TTL, r.Active, quote(r.Target))
+	}
+
+	return records
+}
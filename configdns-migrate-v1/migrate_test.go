@@ -0,0 +1,137 @@
+package dnsmigrate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v1"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+var (
+	config = edgegrid.Config{
+		Host:         "akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net/",
+		AccessToken:  "akab-access-token-xxx-xxxxxxxxxxxxxxxx",
+		ClientToken:  "akab-client-token-xxx-xxxxxxxxxxxxxxxx",
+		ClientSecret: "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx=",
+		MaxBody:      2048,
+		Debug:        false,
+	}
+)
+
+func testZone() *dns.Zone {
+	zone := dns.NewZone("example.com")
+	zone.Zone.Soa.TTL = 86400
+	zone.Zone.Soa.Originserver = "ns1.example.com."
+	zone.Zone.Soa.Contact = "hostmaster"
+	zone.Zone.Soa.Serial = 2019010101
+	zone.Zone.Soa.Refresh = 3600
+	zone.Zone.Soa.Retry = 600
+	zone.Zone.Soa.Expire = 604800
+	zone.Zone.Soa.Minimum = 300
+	zone.Zone.Ns = []*dns.NsRecord{
+		{Name: "", TTL: 86400, Active: true, Target: "ns1.example.com."},
+		{Name: "@", TTL: 86400, Active: true, Target: "ns2"},
+	}
+	zone.Zone.A = []*dns.ARecord{
+		{Name: "www", TTL: 300, Active: true, Target: "192.0.2.1"},
+		{Name: "www", TTL: 600, Active: true, Target: "192.0.2.2"},
+		{Name: "old", TTL: 300, Active: false, Target: "192.0.2.3"},
+		{Name: "bad", TTL: 300, Active: true, Target: "192.0.2"},
+	}
+	zone.Zone.Cname = []*dns.CnameRecord{
+		{Name: "ftp", TTL: 300, Active: true, Target: "www"},
+	}
+	zone.Zone.Mx = []*dns.MxRecord{
+		{Name: "", TTL: 300, Active: true, Priority: 10, Target: "mail.example.net."},
+	}
+	zone.Zone.Txt = []*dns.TxtRecord{
+		{Name: "", TTL: 300, Active: true, Target: `v=spf1 include:"example.net" -all`},
+	}
+	zone.Zone.Rrsig = []*dns.RrsigRecord{
+		{Name: "www", TTL: 300, Active: true, TypeCovered: "A", Algorithm: 8, Keytag: 12345, Signer: "example.com.", Signature: "abc="},
+	}
+
+	return zone
+}
+
+func TestConvert(t *testing.T) {
+	conversion := Convert(testZone())
+
+	assert.Equal(t, "example.com", conversion.Zone)
+	assert.Equal(t, []dnsv2.Recordset{
+		{Name: "example.com", Type: "SOA", TTL: 86400, Rdata: []string{"ns1.example.com. hostmaster.example.com. 2019010101 3600 600 604800 300"}},
+		{Name: "example.com", Type: "NS", TTL: 86400, Rdata: []string{"ns1.example.com.", "ns2.example.com."}},
+		{Name: "www.example.com", Type: "A", TTL: 300, Rdata: []string{"192.0.2.1", "192.0.2.2"}},
+		{Name: "ftp.example.com", Type: "CNAME", TTL: 300, Rdata: []string{"www.example.com."}},
+		{Name: "example.com", Type: "MX", TTL: 300, Rdata: []string{"10 mail.example.net."}},
+		{Name: "example.com", Type: "TXT", TTL: 300, Rdata: []string{`"v=spf1 include:\"example.net\" -all"`}},
+	}, conversion.Recordsets)
+
+	if assert.Len(t, conversion.Unmapped, 3) {
+		assert.Equal(t, &UnmappedRecord{Type: "A", Name: "old.example.com", Reason: "record is inactive"}, conversion.Unmapped[0])
+		assert.Equal(t, "bad.example.com", conversion.Unmapped[1].Name)
+		assert.Equal(t, "RRSIG", conversion.Unmapped[2].Type)
+	}
+	assert.Equal(t, []string{"www.example.com A: TTL 600 changed to 300, the TTL of the recordset"}, conversion.Warnings)
+
+	report := conversion.Report()
+	assert.Contains(t, report, "example.com: 6 recordsets converted, 3 records not mapped\n")
+	assert.Contains(t, report, "not mapped: old.example.com A: record is inactive\n")
+	assert.Contains(t, report, "warning: www.example.com A: TTL 600 changed to 300")
+}
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `"hello world"`, quote("hello world"))
+	assert.Equal(t, `"already"`, quote(`"already"`))
+
+	long := quote(strings.Repeat("a", 300))
+	assert.Len(t, long, 255+2+1+45+2)
+}
+
+func TestMigrate(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones/example.com").
+		HeaderPresent("Authorization").
+		Reply(404).
+		SetHeader("Content-Type", "application/problem+json").
+		BodyString(`{"type": "https://problems.luna.akamaiapis.net/authoritative-dns/zone-not-found", "title": "Not Found", "status": 404}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/zones/").
+		MatchParam("contractId", "C-1").
+		MatchParam("gid", "1234").
+		HeaderPresent("Authorization").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"zone": "example.com", "type": "PRIMARY", "comment": "migrated from v1"}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones/example.com/recordsets").
+		MatchParam("types", "SOA").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"recordsets": [{"name": "example.com", "type": "SOA", "ttl": 86400, "rdata": ["a1-1.akam.net. hostmaster.example.com. 1 3600 600 604800 300"]}]}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Put("/config-dns/v2/zones/example.com/recordsets").
+		HeaderPresent("Authorization").
+		Reply(204)
+
+	Init(config)
+
+	conversion, err := Migrate(testZone(), dnsv2.ZoneQueryString{Contract: "C-1", Group: "1234"}, "migrated from v1")
+	assert.NoError(t, err)
+	assert.Len(t, conversion.Recordsets, 6)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,269 @@
+package dnsv2
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// ChangelistChange is a change to a recordset within a changelist
+type ChangelistChange struct {
+	Name  string            `json:"name"`
+	Type  string            `json:"type"`
+	Op    ChangelistOpValue `json:"op"`
+	TTL   int               `json:"ttl,omitempty"`
+	Rdata []string          `json:"rdata,omitempty"`
+}
+
+// ChangelistListResponse is a list of changelists
+type ChangelistListResponse struct {
+	ChangeLists []ChangeListResponse `json:"changeLists"`
+}
+
+// NewChangelistChange creates a new ChangelistChange for a recordset
+//
+// Only the name and type of the recordset are used by ChangelistOpDelete.
+func NewChangelistChange(op ChangelistOpValue, recordset Recordset) *ChangelistChange {
+	change := &ChangelistChange{Name: recordset.Name, Type: recordset.Type, Op: op}
+	if op != ChangelistOpDelete {
+		change.TTL = recordset.TTL
+		change.Rdata = recordset.Rdata
+	}
+
+	return change
+}
+
+// CreateChangelist creates a changelist from the current version of a zone
+//
+// A zone has at most one changelist; if overwrite is true an existing
+// changelist is replaced, otherwise creating a second changelist fails.
+//
+// Endpoint: POST /config-dns/v2/changelists{?zone,overwrite}
+func CreateChangelist(zone string, overwrite bool) (*ChangeListResponse, error) {
+	req, err := client.NewRequest(
+		Config,
+		"POST",
+		"/config-dns/v2/changelists?zone="+zone+"&overwrite="+strconv.FormatBool(overwrite),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+
+	// Network error
+	if err != nil {
+		return nil, &ZoneError{
+			zoneName:         zone,
+			httpErrorMessage: err.Error(),
+			err:              err,
+		}
+	}
+
+	// API error
+	if client.IsError(res) {
+		err := client.NewAPIError(res)
+		return nil, &ZoneError{zoneName: zone, apiErrorMessage: err.Detail, err: err}
+	}
+
+	return GetChangeList(zone)
+}
+
+// ListChangelists retrieves the changelists of every zone
+//
+// Endpoint: GET /config-dns/v2/changelists
+func ListChangelists() (*ChangelistListResponse, error) {
+	changelists := &ChangelistListResponse{}
+
+	req, err := client.NewRequest(Config, "GET", "/config-dns/v2/changelists", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	if err = client.BodyJSON(res, changelists); err != nil {
+		return nil, err
+	}
+
+	return changelists, nil
+}
+
+// GetChangelistRecordsets retrieves the recordsets of a changelist
+//
+// If types are given only recordsets of those types are returned.
+//
+// Endpoint: GET /config-dns/v2/changelists/{zone}/recordsets{?types,showAll}
+func GetChangelistRecordsets(zone string, types ...string) (*RecordSetResponse, error) {
+	records := NewRecordSetResponse(zone)
+
+	path := "/config-dns/v2/changelists/" + zone + "/recordsets?showAll=true"
+	if len(types) != 0 {
+		path += "&types=" + strings.Join(types, ",")
+	}
+
+	req, err := client.NewRequest(Config, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) && res.StatusCode != 404 {
+		return nil, client.NewAPIError(res)
+	} else if res.StatusCode == 404 {
+		return nil, &ZoneError{zoneName: zone}
+	}
+
+	if err = client.BodyJSON(res, records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// GetChangelistRecordset retrieves a single recordset of a changelist
+//
+// nil is returned if the changelist has no recordset of that name and type.
+func GetChangelistRecordset(zone string, name string, recordType string) (*Recordset, error) {
+	records, err := GetChangelistRecordsets(zone, recordType)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSuffix(name, ".")
+	for _, recordset := range records.Recordsets {
+		if strings.EqualFold(recordset.Name, name) && strings.EqualFold(recordset.Type, recordType) {
+			recordset := recordset
+			return &recordset, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// AddChangelistChange adds, edits or deletes a recordset within the changelist of a zone
+//
+// Endpoint: POST /config-dns/v2/changelists/{zone}/recordsets/add-change
+func AddChangelistChange(zone string, change *ChangelistChange) error {
+	req, err := client.NewJSONRequest(
+		Config,
+		"POST",
+		"/config-dns/v2/changelists/"+zone+"/recordsets/add-change",
+		change,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+
+	// Network error
+	if err != nil {
+		return &ZoneError{
+			zoneName:         zone,
+			httpErrorMessage: err.Error(),
+			err:              err,
+		}
+	}
+
+	// API error
+	if client.IsError(res) {
+		err := client.NewAPIError(res)
+		return &ZoneError{zoneName: zone, apiErrorMessage: change.Name + " " + change.Type + ": " + err.Detail, err: err}
+	}
+
+	return nil
+}
+
+// GetChangelistDiff compares the recordsets of a changelist with the live zone
+//
+// The plan lists the changes that submitting the changelist would make.
+func GetChangelistDiff(zone string) (*ZoneSyncPlan, error) {
+	pending, err := GetChangelistRecordsets(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := getZoneRecordsets(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return ComputeZoneSyncPlan(zone, pending.Recordsets, live, ZoneSyncOptions{Prune: true}), nil
+}
+
+// SubmitChangelist applies the changelist of a zone
+//
+// A stale changelist, created from a zone version that has since been
+// replaced, is not submitted unless force is true; a StaleChangelistError is
+// returned instead.
+//
+// Endpoint: POST /config-dns/v2/changelists/{zone}/submit
+func SubmitChangelist(zone string, force bool) error {
+	if !force {
+		changelist, err := GetChangeList(zone)
+		if err != nil {
+			return err
+		}
+
+		if changelist.Stale {
+			return &StaleChangelistError{zoneName: zone, zoneVersionId: changelist.ZoneVersionId}
+		}
+	}
+
+	return NewZone(ZoneCreate{Zone: zone}).SubmitChangelist()
+}
+
+// DiscardChangelist deletes the changelist of a zone without applying it
+//
+// Endpoint: DELETE /config-dns/v2/changelists/{zone}
+func DiscardChangelist(zone string) error {
+	req, err := client.NewRequest(Config, "DELETE", "/config-dns/v2/changelists/"+zone, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+
+	// Network error
+	if err != nil {
+		return &ZoneError{
+			zoneName:         zone,
+			httpErrorMessage: err.Error(),
+			err:              err,
+		}
+	}
+
+	// API error
+	if client.IsError(res) && res.StatusCode != 404 {
+		err := client.NewAPIError(res)
+		return &ZoneError{zoneName: zone, apiErrorMessage: err.Detail, err: err}
+	}
+
+	return nil
+}
+
+// ChangelistOpValue is used to create an "enum" of possible ChangelistChange.Op values
+type ChangelistOpValue string
+
+const (
+	// ChangelistOpAdd ChangelistChange.Op value ADD
+	ChangelistOpAdd ChangelistOpValue = "ADD"
+	// ChangelistOpEdit ChangelistChange.Op value EDIT
+	ChangelistOpEdit ChangelistOpValue = "EDIT"
+	// ChangelistOpDelete ChangelistChange.Op value DELETE
+	ChangelistOpDelete ChangelistOpValue = "DELETE"
+)
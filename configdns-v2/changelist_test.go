@@ -0,0 +1,62 @@
+package dnsv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestGetChangelistRecordset(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/changelists/example.com/recordsets").
+		MatchParam("types", "A").
+		MatchParam("showAll", "true").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"metadata": {"showAll": true, "totalElements": 2},
+			"recordsets": [
+				{"name": "api.example.com", "type": "A", "ttl": 300, "rdata": ["192.0.2.2"]},
+				{"name": "www.example.com", "type": "A", "ttl": 300, "rdata": ["192.0.2.1"]}
+			]
+		}`)
+
+	Init(config)
+
+	recordset, err := GetChangelistRecordset("example.com", "WWW.example.com.", "A")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Recordset{Name: "www.example.com", Type: "A", TTL: 300, Rdata: []string{"192.0.2.1"}}, recordset)
+}
+
+func TestSubmitChangelist_Stale(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/changelists/example.com").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"zone": "example.com", "changeTag": "tag", "zoneVersionId": "v1", "stale": true}`)
+
+	Init(config)
+
+	err := SubmitChangelist("example.com", false)
+
+	assert.IsType(t, &StaleChangelistError{}, err)
+	assert.True(t, IsConfigDNSError(err))
+	assert.EqualError(t, err, `Changelist for zone "example.com" is stale: zone version v1 has been replaced`)
+}
+
+func TestNewChangelistChange(t *testing.T) {
+	recordset := Recordset{Name: "www.example.com", Type: "A", TTL: 300, Rdata: []string{"192.0.2.1"}}
+
+	assert.Equal(t, &ChangelistChange{Name: "www.example.com", Type: "A", Op: ChangelistOpEdit, TTL: 300, Rdata: []string{"192.0.2.1"}}, NewChangelistChange(ChangelistOpEdit, recordset))
+	assert.Equal(t, &ChangelistChange{Name: "www.example.com", Type: "A", Op: ChangelistOpDelete}, NewChangelistChange(ChangelistOpDelete, recordset))
+}
@@ -0,0 +1,434 @@
+package dnsv2
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// DNSSECRecords are the DNSKEY and DS records of a signed zone, in zone file format
+type DNSSECRecords struct {
+	DNSKEYRecord     string `json:"dnskeyRecord"`
+	DSRecord         string `json:"dsRecord"`
+	ExpectedTTL      int    `json:"expectedTtl,omitempty"`
+	LastModifiedDate string `json:"lastModifiedDate,omitempty"`
+}
+
+// DNSSECStatus is the sign-and-serve state of a zone
+//
+// NewRecords is set while a key rotation is in progress; its DS records
+// should be added to the parent zone before the rotation completes.
+type DNSSECStatus struct {
+	Zone           string         `json:"zone"`
+	Alerts         []string       `json:"alerts,omitempty"`
+	CurrentRecords DNSSECRecords  `json:"currentRecords"`
+	NewRecords     *DNSSECRecords `json:"newRecords,omitempty"`
+}
+
+// DNSSECStatusResponse is the sign-and-serve state of several zones
+type DNSSECStatusResponse struct {
+	DNSSECStatuses []*DNSSECStatus `json:"dnsSecStatuses"`
+}
+
+// dnskeyFlagSEP is the secure entry point flag of a key signing key
+const dnskeyFlagSEP = 0x0001
+
+// DSCheck is the result of comparing parent zone DS records with the keys of a zone
+type DSCheck struct {
+	Zone string
+	// Matched are the parent DS records of a current or new key
+	Matched []*DSRdata
+	// Unmatched are the parent DS records that match no key
+	Unmatched []*DSRdata
+	// Missing are the current key signing keys without a parent DS record;
+	// if no key has the SEP flag every current key is considered
+	Missing []*DNSKEYRdata
+}
+
+// EnableSignAndServe turns on DNSSEC signing of a zone with the given algorithm
+func EnableSignAndServe(zone string, algorithm SignAndServeAlgorithmValue) error {
+	return updateSignAndServe(zone, true, algorithm)
+}
+
+// DisableSignAndServe turns off DNSSEC signing of a zone
+//
+// The DS records of the zone must be removed from the parent zone first, or
+// the zone will fail validation.
+func DisableSignAndServe(zone string) error {
+	return updateSignAndServe(zone, false, "")
+}
+
+func updateSignAndServe(zone string, signAndServe bool, algorithm SignAndServeAlgorithmValue) error {
+	current, err := GetZone(zone)
+	if err != nil {
+		return err
+	}
+
+	update := NewZone(ZoneCreate{
+		Zone:                  current.Zone,
+		Type:                  current.Type,
+		Masters:               current.Masters,
+		Comment:               current.Comment,
+		SignAndServe:          signAndServe,
+		SignAndServeAlgorithm: algorithm,
+		TsigKey:               current.TsigKey,
+	})
+
+	return update.Update(ZoneQueryString{})
+}
+
+// GetDNSSECStatus retrieves the sign-and-serve state of zones
+//
+// Endpoint: POST /config-dns/v2/zones/dns-sec-status
+func GetDNSSECStatus(zones ...string) ([]*DNSSECStatus, error) {
+	status := &DNSSECStatusResponse{}
+
+	req, err := client.NewJSONRequest(Config, "POST", "/config-dns/v2/zones/dns-sec-status", &ZoneNames{Zones: zones})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	if err = client.BodyJSON(res, status); err != nil {
+		return nil, err
+	}
+
+	return status.DNSSECStatuses, nil
+}
+
+// GetZoneDNSSECStatus retrieves the sign-and-serve state of a single zone
+func GetZoneDNSSECStatus(zone string) (*DNSSECStatus, error) {
+	statuses, err := GetDNSSECStatus(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, status := range statuses {
+		if strings.EqualFold(status.Zone, zone) {
+			return status, nil
+		}
+	}
+
+	return nil, &ZoneError{zoneName: zone}
+}
+
+// RotateDNSSECKeys starts a rotation of the signing keys of a zone
+//
+// The new keys are reported in DNSSECStatus.NewRecords until the rotation
+// completes.
+//
+// Endpoint: POST /config-dns/v2/zones/{zone}/dns-sec-key-rotation
+func RotateDNSSECKeys(zone string) error {
+	req, err := client.NewRequest(Config, "POST", "/config-dns/v2/zones/"+zone+"/dns-sec-key-rotation", nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+
+	// Network error
+	if err != nil {
+		return &ZoneError{
+			zoneName:         zone,
+			httpErrorMessage: err.Error(),
+			err:              err,
+		}
+	}
+
+	// API error
+	if client.IsError(res) && res.StatusCode != 404 {
+		err := client.NewAPIError(res)
+		return &ZoneError{zoneName: zone, apiErrorMessage: err.Detail, err: err}
+	} else if res.StatusCode == 404 {
+		return &ZoneError{zoneName: zone}
+	}
+
+	return nil
+}
+
+// DSRecords parses the DS records, the records to upload to the registrar
+func (records *DNSSECRecords) DSRecords() ([]*DSRdata, error) {
+	parsed, err := parseDNSSECRecords("DS", records.DSRecord)
+	if err != nil {
+		return nil, err
+	}
+
+	ds := make([]*DSRdata, len(parsed))
+	for i, rdata := range parsed {
+		ds[i] = rdata.(*DSRdata)
+	}
+
+	return ds, nil
+}
+
+// DNSKEYs parses the DNSKEY records
+func (records *DNSSECRecords) DNSKEYs() ([]*DNSKEYRdata, error) {
+	parsed, err := parseDNSSECRecords("DNSKEY", records.DNSKEYRecord)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*DNSKEYRdata, len(parsed))
+	for i, rdata := range parsed {
+		keys[i] = rdata.(*DNSKEYRdata)
+	}
+
+	return keys, nil
+}
+
+// String formats the status for review, including the DS records to upload
+func (status *DNSSECStatus) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "zone %s\n", status.Zone)
+	for _, alert := range status.Alerts {
+		fmt.Fprintf(&builder, "alert: %s\n", alert)
+	}
+
+	write := func(label string, records *DNSSECRecords) {
+		ds, err := records.DSRecords()
+		if err != nil {
+			fmt.Fprintf(&builder, "%s DS: %s\n", label, err)
+			return
+		}
+		for _, rdata := range ds {
+			fmt.Fprintf(&builder, "%s DS: %s\n", label, rdata)
+		}
+	}
+
+	write("current", &status.CurrentRecords)
+	if status.NewRecords != nil {
+		write("new", status.NewRecords)
+	}
+
+	return builder.String()
+}
+
+// CheckParentDS compares the DS records published in the parent zone with the
+// keys of a zone, see CheckDSRecords()
+func CheckParentDS(zone string, parent []string) (*DSCheck, error) {
+	status, err := GetZoneDNSSECStatus(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return CheckDSRecords(status, parent)
+}
+
+// CheckDSRecords compares parent zone DS records with the keys of a zone
+//
+// Each parent record may be DS rdata or a DS record in zone file format. A DS
+// record matches a key if it has the key tag and algorithm of the key and
+// its digest is the digest of the key, whatever the digest type.
+func CheckDSRecords(status *DNSSECStatus, parent []string) (*DSCheck, error) {
+	check := &DSCheck{Zone: status.Zone}
+
+	current, err := status.CurrentRecords.DNSKEYs()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := current
+	if status.NewRecords != nil {
+		next, err := status.NewRecords.DNSKEYs()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(append([]*DNSKEYRdata{}, current...), next...)
+	}
+
+	parsed, err := parseDNSSECRecords("DS", strings.Join(parent, "\n"))
+	if err != nil {
+		return nil, err
+	}
+
+	matched := map[*DNSKEYRdata]bool{}
+	for _, rdata := range parsed {
+		ds := rdata.(*DSRdata)
+
+		key := matchDSKey(status.Zone, ds, keys)
+		if key == nil {
+			check.Unmatched = append(check.Unmatched, ds)
+			continue
+		}
+
+		matched[key] = true
+		check.Matched = append(check.Matched, ds)
+	}
+
+	signing := false
+	for _, key := range current {
+		signing = signing || key.Flags&dnskeyFlagSEP != 0
+	}
+	for _, key := range current {
+		if signing && key.Flags&dnskeyFlagSEP == 0 {
+			continue
+		}
+		if !matched[key] {
+			check.Missing = append(check.Missing, key)
+		}
+	}
+
+	return check, nil
+}
+
+// OK reports whether every current key has a parent DS record and every
+// parent DS record matches a key
+func (check *DSCheck) OK() bool {
+	return len(check.Missing) == 0 && len(check.Unmatched) == 0
+}
+
+// KeyTag computes the key tag of the key, RFC 4034 appendix B
+func (rdata *DNSKEYRdata) KeyTag() (uint16, error) {
+	wire, err := rdata.wire()
+	if err != nil {
+		return 0, err
+	}
+
+	var sum uint32
+	for i, b := range wire {
+		if i%2 == 0 {
+			sum += uint32(b) << 8
+		} else {
+			sum += uint32(b)
+		}
+	}
+	sum += sum >> 16
+
+	return uint16(sum), nil
+}
+
+// DS computes the DS record of the key for the owner name of the key
+//
+// Digest types 1 (SHA-1), 2 (SHA-256) and 4 (SHA-384) are supported.
+func (rdata *DNSKEYRdata) DS(owner string, digestType uint8) (*DSRdata, error) {
+	var digest hash.Hash
+	switch digestType {
+	case 1:
+		digest = sha1.New()
+	case 2:
+		digest = sha256.New()
+	case 4:
+		digest = sha512.New384()
+	default:
+		return nil, fmt.Errorf("unsupported DS digest type %d", digestType)
+	}
+
+	keyTag, err := rdata.KeyTag()
+	if err != nil {
+		return nil, err
+	}
+
+	wire, _ := rdata.wire()
+	digest.Write(canonicalWireName(owner))
+	digest.Write(wire)
+
+	return &DSRdata{KeyTag: keyTag, Algorithm: rdata.Algorithm, DigestType: digestType, Digest: strings.ToUpper(hex.EncodeToString(digest.Sum(nil)))}, nil
+}
+
+// wire encodes the key in DNS wire format
+func (rdata *DNSKEYRdata) wire() ([]byte, error) {
+	publicKey, err := base64.StdEncoding.DecodeString(rdata.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DNSKEY public key, expected base64")
+	}
+
+	wire := make([]byte, 4, 4+len(publicKey))
+	binary.BigEndian.PutUint16(wire, rdata.Flags)
+	wire[2] = rdata.Protocol
+	wire[3] = rdata.Algorithm
+
+	return append(wire, publicKey...), nil
+}
+
+// canonicalWireName encodes a domain name in lower case DNS wire format
+func canonicalWireName(name string) []byte {
+	var wire []byte
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			wire = append(wire, byte(len(label)))
+			wire = append(wire, label...)
+		}
+	}
+
+	return append(wire, 0)
+}
+
+// matchDSKey finds the key a DS record was computed from
+func matchDSKey(zone string, ds *DSRdata, keys []*DNSKEYRdata) *DNSKEYRdata {
+	for _, key := range keys {
+		if key.Algorithm != ds.Algorithm {
+			continue
+		}
+
+		computed, err := key.DS(zone, ds.DigestType)
+		if err != nil {
+			continue
+		}
+
+		if computed.KeyTag == ds.KeyTag && strings.EqualFold(computed.Digest, ds.Digest) {
+			return key
+		}
+	}
+
+	return nil
+}
+
+// parseDNSSECRecords parses one record per line, either rdata alone or a
+// record in zone file format such as "example.com. 3600 IN DS 60485 5 1 ..."
+func parseDNSSECRecords(recordType string, records string) ([]Rdata, error) {
+	var parsed []Rdata
+	for _, line := range strings.Split(records, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		for i, field := range fields {
+			if strings.EqualFold(field, recordType) {
+				fields = fields[i+1:]
+				break
+			}
+		}
+
+		rdata, err := ParseRdata(recordType, strings.Join(fields, " "))
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, rdata)
+	}
+
+	return parsed, nil
+}
+
+// SignAndServeAlgorithmValue is used to create an "enum" of possible ZoneCreate.SignAndServeAlgorithm values
+type SignAndServeAlgorithmValue string
+
+const (
+	// SignAndServeAlgorithmRSASHA1 ZoneCreate.SignAndServeAlgorithm value RSA_SHA1
+	SignAndServeAlgorithmRSASHA1 SignAndServeAlgorithmValue = "RSA_SHA1"
+	// SignAndServeAlgorithmRSASHA256 ZoneCreate.SignAndServeAlgorithm value RSA_SHA256
+	SignAndServeAlgorithmRSASHA256 SignAndServeAlgorithmValue = "RSA_SHA256"
+	// SignAndServeAlgorithmRSASHA512 ZoneCreate.SignAndServeAlgorithm value RSA_SHA512
+	SignAndServeAlgorithmRSASHA512 SignAndServeAlgorithmValue = "RSA_SHA512"
+	// SignAndServeAlgorithmECDSAP256SHA256 ZoneCreate.SignAndServeAlgorithm value ECDSA_P256_SHA256
+	SignAndServeAlgorithmECDSAP256SHA256 SignAndServeAlgorithmValue = "ECDSA_P256_SHA256"
+	// SignAndServeAlgorithmECDSAP384SHA384 ZoneCreate.SignAndServeAlgorithm value ECDSA_P384_SHA384
+	SignAndServeAlgorithmECDSAP384SHA384 SignAndServeAlgorithmValue = "ECDSA_P384_SHA384"
+)
@@ -0,0 +1,128 @@
+package dnsv2
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// testPublicKey is the example key of RFC 4034 section 5.4
+const testPublicKey = "AQOeiiR0GOMYkDshWoSKz9XzfwJr1AYtsmx3TGkJaNXVbfi/2pHm822aJ5iI9BMzNXxeYCmZDRD99WYwYqUSdjMmmAphXdvxegXd/M5+X7OrzKBaMbCVdFLUUh6DhweJBjEVv5f2wwjM9XzcnOf+EPbtG9DMBmADjFDc2w/rljwvFw=="
+
+func TestDNSKEYRdata_DS(t *testing.T) {
+	key := &DNSKEYRdata{Flags: 256, Protocol: 3, Algorithm: 5, PublicKey: testPublicKey}
+
+	keyTag, err := key.KeyTag()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(60485), keyTag)
+
+	ds, err := key.DS("dskey.example.com.", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "60485 5 1 2BB183AF5F22588179A53B0A98631FAD1A292118", ds.String())
+
+	_, err = key.DS("dskey.example.com", 3)
+	assert.EqualError(t, err, "unsupported DS digest type 3")
+}
+
+func testDNSSECStatus() *DNSSECStatus {
+	return &DNSSECStatus{
+		Zone: "example.com",
+		CurrentRecords: DNSSECRecords{
+			DNSKEYRecord: "example.com. 7200 IN DNSKEY 257 3 5 " + testPublicKey + "\nexample.com. 7200 IN DNSKEY 256 3 5 " + testPublicKey,
+			DSRecord:     "example.com. 86400 IN DS 60486 5 2 9410E7ECCF6A6E9629C551045F1995CB6BC3005974FD0A800ADBE0D1EBE1A875",
+		},
+	}
+}
+
+func TestCheckDSRecords(t *testing.T) {
+	check, err := CheckDSRecords(testDNSSECStatus(), []string{
+		"example.com. 86400 IN DS 60486 5 1 e260e84f907052f02d754c1c7c4aba51adc87a7b",
+		"12345 5 2 " + strings.Repeat("0", 64),
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, check.OK())
+	if assert.Len(t, check.Matched, 1) {
+		assert.Equal(t, uint16(60486), check.Matched[0].KeyTag)
+	}
+	if assert.Len(t, check.Unmatched, 1) {
+		assert.Equal(t, uint16(12345), check.Unmatched[0].KeyTag)
+	}
+	assert.Empty(t, check.Missing)
+
+	check, err = CheckDSRecords(testDNSSECStatus(), nil)
+
+	assert.NoError(t, err)
+	if assert.Len(t, check.Missing, 1) {
+		assert.Equal(t, uint16(257), check.Missing[0].Flags)
+	}
+}
+
+func TestCheckParentDS(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/zones/dns-sec-status").
+		JSON(`{"zones": ["example.com"]}`).
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"dnsSecStatuses": [{
+			"zone": "example.com",
+			"alerts": ["DS record not found in parent zone"],
+			"currentRecords": {
+				"dnskeyRecord": "example.com. 7200 IN DNSKEY 257 3 5 ` + testPublicKey + `",
+				"dsRecord": "example.com. 86400 IN DS 60486 5 2 9410E7ECCF6A6E9629C551045F1995CB6BC3005974FD0A800ADBE0D1EBE1A875",
+				"expectedTtl": 86400
+			}
+		}]}`)
+
+	Init(config)
+
+	check, err := CheckParentDS("example.com", []string{"60486 5 2 9410e7eccf6a6e9629c551045f1995cb6bc3005974fd0a800adbe0d1ebe1a875"})
+
+	assert.NoError(t, err)
+	assert.True(t, check.OK())
+}
+
+func TestDNSSECStatus_String(t *testing.T) {
+	status := testDNSSECStatus()
+	status.Alerts = []string{"key rotation in progress"}
+	status.NewRecords = &DNSSECRecords{DSRecord: "11111 5 1 " + strings.Repeat("a", 40)}
+
+	assert.Equal(t, "zone example.com\n"+
+		"alert: key rotation in progress\n"+
+		"current DS: 60486 5 2 9410E7ECCF6A6E9629C551045F1995CB6BC3005974FD0A800ADBE0D1EBE1A875\n"+
+		"new DS: 11111 5 1 "+strings.Repeat("A", 40)+"\n", status.String())
+}
+
+func TestEnableSignAndServe(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones/example.com").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"zone": "example.com", "type": "PRIMARY", "comment": "main zone", "signAndServe": false, "activationState": "ACTIVE"}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Put("/config-dns/v2/zones/example.com").
+		JSON(`{"zone": "example.com", "type": "PRIMARY", "comment": "main zone", "signAndServe": true, "signAndServeAlgorithm": "ECDSA_P256_SHA256"}`).
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"zone": "example.com", "type": "PRIMARY", "signAndServe": true, "signAndServeAlgorithm": "ECDSA_P256_SHA256"}`)
+
+	Init(config)
+
+	err := EnableSignAndServe("example.com", SignAndServeAlgorithmECDSAP256SHA256)
+
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
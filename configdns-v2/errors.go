@@ -123,3 +123,28 @@ func (e *RecordError) Error() string {
 
 	return "<nil>"
 }
+
+type StaleChangelistError struct {
+	zoneName      string
+	zoneVersionId string
+}
+
+func (e *StaleChangelistError) Network() bool {
+	return false
+}
+
+func (e *StaleChangelistError) NotFound() bool {
+	return false
+}
+
+func (e *StaleChangelistError) FailedToSave() bool {
+	return true
+}
+
+func (e *StaleChangelistError) ValidationFailed() bool {
+	return false
+}
+
+func (e *StaleChangelistError) Error() string {
+	return fmt.Sprintf("Changelist for zone \"%s\" is stale: zone version %s has been replaced", e.zoneName, e.zoneVersionId)
+}
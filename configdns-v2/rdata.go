@@ -0,0 +1,734 @@
+package dnsv2
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Rdata is the typed data of a single record
+//
+// String() formats the data as used in the rdata of a Recordset and in zone
+// files, see ParseRdata() for the reverse.
+type Rdata interface {
+	Type() string
+	String() string
+	Validate() error
+}
+
+// ARdata is the data of an A record
+type ARdata struct {
+	Address net.IP
+}
+
+// AAAARdata is the data of an AAAA record
+type AAAARdata struct {
+	Address net.IP
+}
+
+// CNAMERdata is the data of a CNAME record
+type CNAMERdata struct {
+	Target string
+}
+
+// NSRdata is the data of an NS record
+type NSRdata struct {
+	Target string
+}
+
+// PTRRdata is the data of a PTR record
+type PTRRdata struct {
+	Target string
+}
+
+// MXRdata is the data of an MX record
+type MXRdata struct {
+	Priority uint16
+	Exchange string
+}
+
+// SRVRdata is the data of an SRV record
+type SRVRdata struct {
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	Target   string
+}
+
+// TXTRdata is the data of a TXT record, made of one or more strings
+type TXTRdata struct {
+	Strings []string
+}
+
+// CAARdata is the data of a CAA record
+type CAARdata struct {
+	Flags uint8
+	Tag   string
+	Value string
+}
+
+// TLSARdata is the data of a TLSA record
+type TLSARdata struct {
+	Usage        uint8
+	Selector     uint8
+	MatchingType uint8
+	// Certificate is the hex encoded certificate association data
+	Certificate string
+}
+
+// SVCBParam is a key=value service parameter of an SVCB or HTTPS record
+type SVCBParam struct {
+	Key   string
+	Value string
+}
+
+// SVCBRdata is the data of an SVCB record
+//
+// A Priority of 0 is alias mode, which has no parameters.
+type SVCBRdata struct {
+	Priority uint16
+	Target   string
+	Params   []SVCBParam
+}
+
+// HTTPSRdata is the data of an HTTPS record
+type HTTPSRdata struct {
+	SVCBRdata
+}
+
+// NAPTRRdata is the data of a NAPTR record
+type NAPTRRdata struct {
+	Order       uint16
+	Preference  uint16
+	Flags       string
+	Service     string
+	Regexp      string
+	Replacement string
+}
+
+// DSRdata is the data of a DS record
+type DSRdata struct {
+	KeyTag     uint16
+	Algorithm  uint8
+	DigestType uint8
+	// Digest is the hex encoded digest
+	Digest string
+}
+
+// DNSKEYRdata is the data of a DNSKEY record
+type DNSKEYRdata struct {
+	Flags     uint16
+	Protocol  uint8
+	Algorithm uint8
+	// PublicKey is the base64 encoded public key
+	PublicKey string
+}
+
+// SOARdata is the data of an SOA record
+type SOARdata struct {
+	MName   string
+	RName   string
+	Serial  uint32
+	Refresh uint32
+	Retry   uint32
+	Expire  uint32
+	Minimum uint32
+}
+
+// UnknownRdata is the data of a record type without a typed model
+type UnknownRdata struct {
+	RecordType string
+	Data       string
+}
+
+// ParseRdata parses the rdata of a record of the given type
+//
+// Types without a typed model are returned as *UnknownRdata. The parsed
+// data is not validated, see Rdata.Validate().
+func ParseRdata(recordType string, rdata string) (Rdata, error) {
+	recordType = strings.ToUpper(recordType)
+
+	fields, err := splitRdata(rdata)
+	if err != nil {
+		return nil, fmt.Errorf("%s rdata \"%s\": %s", recordType, rdata, err)
+	}
+
+	parsed, err := parseRdataFields(recordType, fields)
+	if err != nil {
+		return nil, fmt.Errorf("%s rdata \"%s\": %s", recordType, rdata, err)
+	}
+
+	if parsed == nil {
+		return &UnknownRdata{RecordType: recordType, Data: strings.TrimSpace(rdata)}, nil
+	}
+
+	return parsed, nil
+}
+
+// ParseRecordsetRdata parses every rdata of a recordset
+func ParseRecordsetRdata(recordset Recordset) ([]Rdata, error) {
+	parsed := make([]Rdata, 0, len(recordset.Rdata))
+	for _, rdata := range recordset.Rdata {
+		typed, err := ParseRdata(recordset.Type, rdata)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, typed)
+	}
+
+	return parsed, nil
+}
+
+// NewRecordset creates a Recordset from validated typed data of a single type
+func NewRecordset(name string, ttl int, rdata ...Rdata) (Recordset, error) {
+	recordset := Recordset{Name: name, TTL: ttl}
+	if len(rdata) == 0 {
+		return recordset, fmt.Errorf("recordset %s has no rdata", name)
+	}
+
+	recordset.Type = rdata[0].Type()
+	for _, data := range rdata {
+		if data.Type() != recordset.Type {
+			return recordset, fmt.Errorf("recordset %s mixes %s and %s rdata", name, recordset.Type, data.Type())
+		}
+
+		if err := data.Validate(); err != nil {
+			return recordset, err
+		}
+
+		recordset.Rdata = append(recordset.Rdata, data.String())
+	}
+
+	return recordset, nil
+}
+
+// SetRdata sets the type and targets of a record from validated typed data
+func (record *RecordBody) SetRdata(rdata ...Rdata) error {
+	recordset, err := NewRecordset(record.Name, record.TTL, rdata...)
+	if err != nil {
+		return err
+	}
+
+	record.RecordType = recordset.Type
+	record.Target = recordset.Rdata
+
+	return nil
+}
+
+// splitRdata splits rdata into fields, unquoting and unescaping quoted strings
+func splitRdata(rdata string) ([]string, error) {
+	var fields []string
+	var current strings.Builder
+	inField, inQuote := false, false
+
+	for i := 0; i < len(rdata); i++ {
+		c := rdata[i]
+
+		switch {
+		case c == '\\' && i+3 < len(rdata) && isDigits(rdata[i+1:i+4]):
+			value, _ := strconv.Atoi(rdata[i+1 : i+4])
+			current.WriteByte(byte(value))
+			i += 3
+			inField = true
+		case c == '\\' && i+1 < len(rdata):
+			i++
+			current.WriteByte(rdata[i])
+			inField = true
+		case c == '"':
+			if inQuote {
+				inQuote = false
+				continue
+			}
+			inQuote, inField = true, true
+		case !inQuote && (c == ' ' || c == '\t'):
+			if inField {
+				fields = append(fields, current.String())
+				current.Reset()
+				inField = false
+			}
+		default:
+			current.WriteByte(c)
+			inField = true
+		}
+	}
+
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quoted string")
+	}
+	if inField {
+		fields = append(fields, current.String())
+	}
+
+	return fields, nil
+}
+
+func isDigits(value string) bool {
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseRdataFields returns nil for types without a typed model
+func parseRdataFields(recordType string, values []string) (Rdata, error) {
+	expect := func(count int) error {
+		if len(values) != count {
+			return fmt.Errorf("expected %d fields, got %d", count, len(values))
+		}
+		return nil
+	}
+
+	switch recordType {
+	case "A", "AAAA":
+		if err := expect(1); err != nil {
+			return nil, err
+		}
+		ip := net.ParseIP(values[0])
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address")
+		}
+		if recordType == "A" {
+			return &ARdata{Address: ip}, nil
+		}
+		return &AAAARdata{Address: ip}, nil
+
+	case "CNAME", "NS", "PTR":
+		if err := expect(1); err != nil {
+			return nil, err
+		}
+		switch recordType {
+		case "CNAME":
+			return &CNAMERdata{Target: values[0]}, nil
+		case "NS":
+			return &NSRdata{Target: values[0]}, nil
+		}
+		return &PTRRdata{Target: values[0]}, nil
+
+	case "MX":
+		if err := expect(2); err != nil {
+			return nil, err
+		}
+		priority, err := parseRdataUint(values[0], 16)
+		if err != nil {
+			return nil, err
+		}
+		return &MXRdata{Priority: uint16(priority), Exchange: values[1]}, nil
+
+	case "SRV":
+		if err := expect(4); err != nil {
+			return nil, err
+		}
+		numbers, err := parseRdataUints(values[:3], 16)
+		if err != nil {
+			return nil, err
+		}
+		return &SRVRdata{Priority: uint16(numbers[0]), Weight: uint16(numbers[1]), Port: uint16(numbers[2]), Target: values[3]}, nil
+
+	case "TXT", "SPF":
+		if len(values) == 0 {
+			return nil, fmt.Errorf("expected at least 1 string")
+		}
+		if recordType == "SPF" {
+			return &UnknownRdata{RecordType: recordType, Data: formatCharacterStrings(values)}, nil
+		}
+		return &TXTRdata{Strings: values}, nil
+
+	case "CAA":
+		if err := expect(3); err != nil {
+			return nil, err
+		}
+		flags, err := parseRdataUint(values[0], 8)
+		if err != nil {
+			return nil, err
+		}
+		return &CAARdata{Flags: uint8(flags), Tag: values[1], Value: values[2]}, nil
+
+	case "TLSA":
+		if len(values) < 4 {
+			return nil, fmt.Errorf("expected 4 fields, got %d", len(values))
+		}
+		numbers, err := parseRdataUints(values[:3], 8)
+		if err != nil {
+			return nil, err
+		}
+		return &TLSARdata{Usage: uint8(numbers[0]), Selector: uint8(numbers[1]), MatchingType: uint8(numbers[2]), Certificate: strings.Join(values[3:], "")}, nil
+
+	case "SVCB", "HTTPS":
+		if len(values) < 2 {
+			return nil, fmt.Errorf("expected at least 2 fields, got %d", len(values))
+		}
+		priority, err := parseRdataUint(values[0], 16)
+		if err != nil {
+			return nil, err
+		}
+		svcb := SVCBRdata{Priority: uint16(priority), Target: values[1]}
+		for _, param := range values[2:] {
+			parts := strings.SplitN(param, "=", 2)
+			svcbParam := SVCBParam{Key: strings.ToLower(parts[0])}
+			if len(parts) == 2 {
+				svcbParam.Value = parts[1]
+			}
+			svcb.Params = append(svcb.Params, svcbParam)
+		}
+		if recordType == "HTTPS" {
+			return &HTTPSRdata{SVCBRdata: svcb}, nil
+		}
+		return &svcb, nil
+
+	case "NAPTR":
+		if err := expect(6); err != nil {
+			return nil, err
+		}
+		numbers, err := parseRdataUints(values[:2], 16)
+		if err != nil {
+			return nil, err
+		}
+		return &NAPTRRdata{Order: uint16(numbers[0]), Preference: uint16(numbers[1]), Flags: values[2], Service: values[3], Regexp: values[4], Replacement: values[5]}, nil
+
+	case "DS":
+		if len(values) < 4 {
+			return nil, fmt.Errorf("expected 4 fields, got %d", len(values))
+		}
+		keyTag, err := parseRdataUint(values[0], 16)
+		if err != nil {
+			return nil, err
+		}
+		numbers, err := parseRdataUints(values[1:3], 8)
+		if err != nil {
+			return nil, err
+		}
+		return &DSRdata{KeyTag: uint16(keyTag), Algorithm: uint8(numbers[0]), DigestType: uint8(numbers[1]), Digest: strings.Join(values[3:], "")}, nil
+
+	case "DNSKEY":
+		if len(values) < 4 {
+			return nil, fmt.Errorf("expected 4 fields, got %d", len(values))
+		}
+		flags, err := parseRdataUint(values[0], 16)
+		if err != nil {
+			return nil, err
+		}
+		numbers, err := parseRdataUints(values[1:3], 8)
+		if err != nil {
+			return nil, err
+		}
+		return &DNSKEYRdata{Flags: uint16(flags), Protocol: uint8(numbers[0]), Algorithm: uint8(numbers[1]), PublicKey: strings.Join(values[3:], "")}, nil
+
+	case "SOA":
+		if err := expect(7); err != nil {
+			return nil, err
+		}
+		numbers, err := parseRdataUints(values[2:], 32)
+		if err != nil {
+			return nil, err
+		}
+		return &SOARdata{MName: values[0], RName: values[1], Serial: uint32(numbers[0]), Refresh: uint32(numbers[1]), Retry: uint32(numbers[2]), Expire: uint32(numbers[3]), Minimum: uint32(numbers[4])}, nil
+	}
+
+	return nil, nil
+}
+
+func parseRdataUint(value string, bits int) (uint64, error) {
+	number, err := strconv.ParseUint(value, 10, bits)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %d bit number \"%s\"", bits, value)
+	}
+
+	return number, nil
+}
+
+func parseRdataUints(values []string, bits int) ([]uint64, error) {
+	numbers := make([]uint64, len(values))
+	for i, value := range values {
+		number, err := parseRdataUint(value, bits)
+		if err != nil {
+			return nil, err
+		}
+		numbers[i] = number
+	}
+
+	return numbers, nil
+}
+
+// formatCharacterStrings quotes and escapes each string
+func formatCharacterStrings(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quoteCharacterString(value)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// quoteCharacterString quotes a string, escaping quotes and backslashes
+func quoteCharacterString(value string) string {
+	return "\"" + strings.Replace(strings.Replace(value, "\\", "\\\\", -1), "\"", "\\\"", -1) + "\""
+}
+
+// hostnameLabel matches a single label of a domain name, allowing the
+// underscore used by service names and a leading wildcard
+var hostnameLabel = regexp.MustCompile(`^(\*|[A-Za-z0-9_]([A-Za-z0-9_-]{0,61}[A-Za-z0-9_])?)$`)
+
+// validateDomainName checks a domain name, with or without a trailing dot
+func validateDomainName(field string, name string) error {
+	if name == "." {
+		return nil
+	}
+
+	trimmed := strings.TrimSuffix(name, ".")
+	if trimmed == "" || len(trimmed) > 253 {
+		return fmt.Errorf("invalid %s \"%s\"", field, name)
+	}
+
+	for _, label := range strings.Split(trimmed, ".") {
+		if !hostnameLabel.MatchString(label) {
+			return fmt.Errorf("invalid %s \"%s\"", field, name)
+		}
+	}
+
+	return nil
+}
+
+func validateHex(field string, value string) error {
+	if _, err := hex.DecodeString(value); err != nil || value == "" {
+		return fmt.Errorf("invalid %s, expected hex", field)
+	}
+
+	return nil
+}
+
+func (rdata *ARdata) Type() string { return "A" }
+
+func (rdata *ARdata) String() string { return rdata.Address.String() }
+
+func (rdata *ARdata) Validate() error {
+	if rdata.Address.To4() == nil {
+		return fmt.Errorf("invalid IPv4 address \"%s\"", rdata.Address)
+	}
+	return nil
+}
+
+func (rdata *AAAARdata) Type() string { return "AAAA" }
+
+func (rdata *AAAARdata) String() string { return rdata.Address.String() }
+
+func (rdata *AAAARdata) Validate() error {
+	if rdata.Address.To16() == nil || rdata.Address.To4() != nil {
+		return fmt.Errorf("invalid IPv6 address \"%s\"", rdata.Address)
+	}
+	return nil
+}
+
+func (rdata *CNAMERdata) Type() string { return "CNAME" }
+
+func (rdata *CNAMERdata) String() string { return rdata.Target }
+
+func (rdata *CNAMERdata) Validate() error { return validateDomainName("target", rdata.Target) }
+
+func (rdata *NSRdata) Type() string { return "NS" }
+
+func (rdata *NSRdata) String() string { return rdata.Target }
+
+func (rdata *NSRdata) Validate() error { return validateDomainName("target", rdata.Target) }
+
+func (rdata *PTRRdata) Type() string { return "PTR" }
+
+func (rdata *PTRRdata) String() string { return rdata.Target }
+
+func (rdata *PTRRdata) Validate() error { return validateDomainName("target", rdata.Target) }
+
+func (rdata *MXRdata) Type() string { return "MX" }
+
+func (rdata *MXRdata) String() string { return fmt.Sprintf("%d %s", rdata.Priority, rdata.Exchange) }
+
+func (rdata *MXRdata) Validate() error { return validateDomainName("exchange", rdata.Exchange) }
+
+func (rdata *SRVRdata) Type() string { return "SRV" }
+
+func (rdata *SRVRdata) String() string {
+	return fmt.Sprintf("%d %d %d %s", rdata.Priority, rdata.Weight, rdata.Port, rdata.Target)
+}
+
+func (rdata *SRVRdata) Validate() error { return validateDomainName("target", rdata.Target) }
+
+func (rdata *TXTRdata) Type() string { return "TXT" }
+
+func (rdata *TXTRdata) String() string { return formatCharacterStrings(rdata.Strings) }
+
+func (rdata *TXTRdata) Validate() error {
+	if len(rdata.Strings) == 0 {
+		return fmt.Errorf("TXT record has no strings")
+	}
+	for _, value := range rdata.Strings {
+		if len(value) > 255 {
+			return fmt.Errorf("TXT string longer than 255 characters, split it into several strings")
+		}
+	}
+	return nil
+}
+
+func (rdata *CAARdata) Type() string { return "CAA" }
+
+func (rdata *CAARdata) String() string {
+	return fmt.Sprintf("%d %s %s", rdata.Flags, rdata.Tag, quoteCharacterString(rdata.Value))
+}
+
+func (rdata *CAARdata) Validate() error {
+	if rdata.Flags != 0 && rdata.Flags != 128 {
+		return fmt.Errorf("invalid CAA flags %d, expected 0 or 128", rdata.Flags)
+	}
+	switch rdata.Tag {
+	case "issue", "issuewild", "iodef":
+		return nil
+	}
+	return fmt.Errorf("invalid CAA tag \"%s\", expected issue, issuewild or iodef", rdata.Tag)
+}
+
+func (rdata *TLSARdata) Type() string { return "TLSA" }
+
+func (rdata *TLSARdata) String() string {
+	return fmt.Sprintf("%d %d %d %s", rdata.Usage, rdata.Selector, rdata.MatchingType, rdata.Certificate)
+}
+
+func (rdata *TLSARdata) Validate() error {
+	if rdata.Usage > 3 {
+		return fmt.Errorf("invalid TLSA usage %d", rdata.Usage)
+	}
+	if rdata.Selector > 1 {
+		return fmt.Errorf("invalid TLSA selector %d", rdata.Selector)
+	}
+	if rdata.MatchingType > 2 {
+		return fmt.Errorf("invalid TLSA matching type %d", rdata.MatchingType)
+	}
+	return validateHex("TLSA certificate", rdata.Certificate)
+}
+
+func (rdata *SVCBRdata) Type() string { return "SVCB" }
+
+func (rdata *SVCBRdata) String() string {
+	parts := []string{strconv.Itoa(int(rdata.Priority)), rdata.Target}
+	for _, param := range rdata.Params {
+		if param.Value == "" {
+			parts = append(parts, param.Key)
+			continue
+		}
+		value := param.Value
+		if strings.ContainsAny(value, " \t\"") {
+			value = quoteCharacterString(value)
+		}
+		parts = append(parts, param.Key+"="+value)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// svcbParamKey matches the key of a service parameter
+var svcbParamKey = regexp.MustCompile(`^(mandatory|alpn|no-default-alpn|port|ipv4hint|ech|ipv6hint|key\d{1,5})$`)
+
+func (rdata *SVCBRdata) Validate() error { return validateSVCB("SVCB", rdata) }
+
+func (rdata *HTTPSRdata) Type() string { return "HTTPS" }
+
+func (rdata *HTTPSRdata) Validate() error { return validateSVCB("HTTPS", &rdata.SVCBRdata) }
+
+func validateSVCB(recordType string, rdata *SVCBRdata) error {
+	if err := validateDomainName("target", rdata.Target); err != nil {
+		return err
+	}
+	if rdata.Priority == 0 && len(rdata.Params) != 0 {
+		return fmt.Errorf("alias mode %s record has parameters", recordType)
+	}
+	for _, param := range rdata.Params {
+		if !svcbParamKey.MatchString(param.Key) {
+			return fmt.Errorf("invalid %s parameter \"%s\"", recordType, param.Key)
+		}
+		if param.Key == "port" {
+			if _, err := parseRdataUint(param.Value, 16); err != nil {
+				return fmt.Errorf("invalid %s port \"%s\"", recordType, param.Value)
+			}
+		}
+	}
+	return nil
+}
+
+func (rdata *NAPTRRdata) Type() string { return "NAPTR" }
+
+func (rdata *NAPTRRdata) String() string {
+	return fmt.Sprintf("%d %d %s %s %s %s", rdata.Order, rdata.Preference, quoteCharacterString(rdata.Flags), quoteCharacterString(rdata.Service), quoteCharacterString(rdata.Regexp), rdata.Replacement)
+}
+
+func (rdata *NAPTRRdata) Validate() error {
+	for _, flag := range rdata.Flags {
+		if !strings.ContainsRune("SsAaUuPp", flag) {
+			return fmt.Errorf("invalid NAPTR flag \"%c\"", flag)
+		}
+	}
+	if rdata.Regexp != "" && rdata.Replacement != "." {
+		return fmt.Errorf("NAPTR record has both a regexp and a replacement")
+	}
+	return validateDomainName("replacement", rdata.Replacement)
+}
+
+func (rdata *DSRdata) Type() string { return "DS" }
+
+func (rdata *DSRdata) String() string {
+	return fmt.Sprintf("%d %d %d %s", rdata.KeyTag, rdata.Algorithm, rdata.DigestType, strings.ToUpper(rdata.Digest))
+}
+
+// dsDigestLengths are the hex digest lengths of each DS digest type
+var dsDigestLengths = map[uint8]int{1: 40, 2: 64, 4: 96}
+
+func (rdata *DSRdata) Validate() error {
+	if err := validateHex("DS digest", rdata.Digest); err != nil {
+		return err
+	}
+	length, ok := dsDigestLengths[rdata.DigestType]
+	if !ok {
+		return fmt.Errorf("unsupported DS digest type %d", rdata.DigestType)
+	}
+	if len(rdata.Digest) != length {
+		return fmt.Errorf("DS digest type %d must be %d hex characters", rdata.DigestType, length)
+	}
+	return nil
+}
+
+func (rdata *DNSKEYRdata) Type() string { return "DNSKEY" }
+
+func (rdata *DNSKEYRdata) String() string {
+	return fmt.Sprintf("%d %d %d %s", rdata.Flags, rdata.Protocol, rdata.Algorithm, rdata.PublicKey)
+}
+
+func (rdata *DNSKEYRdata) Validate() error {
+	if rdata.Protocol != 3 {
+		return fmt.Errorf("invalid DNSKEY protocol %d", rdata.Protocol)
+	}
+	if _, err := base64.StdEncoding.DecodeString(rdata.PublicKey); err != nil || rdata.PublicKey == "" {
+		return fmt.Errorf("invalid DNSKEY public key, expected base64")
+	}
+	return nil
+}
+
+func (rdata *SOARdata) Type() string { return "SOA" }
+
+func (rdata *SOARdata) String() string {
+	return fmt.Sprintf("%s %s %d %d %d %d %d", rdata.MName, rdata.RName, rdata.Serial, rdata.Refresh, rdata.Retry, rdata.Expire, rdata.Minimum)
+}
+
+func (rdata *SOARdata) Validate() error {
+	if err := validateDomainName("primary name server", rdata.MName); err != nil {
+		return err
+	}
+	return validateDomainName("responsible mailbox", rdata.RName)
+}
+
+func (rdata *UnknownRdata) Type() string { return rdata.RecordType }
+
+func (rdata *UnknownRdata) String() string { return rdata.Data }
+
+func (rdata *UnknownRdata) Validate() error { return nil }
@@ -0,0 +1,63 @@
+package dnsv2
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRdata(t *testing.T) {
+	tests := []struct {
+		recordType string
+		rdata      string
+		expected   Rdata
+		formatted  string
+	}{
+		{"A", "192.0.2.1", &ARdata{Address: net.ParseIP("192.0.2.1")}, "192.0.2.1"},
+		{"AAAA", "2001:0db8:0000:0000:0000:0000:0000:0001", &AAAARdata{Address: net.ParseIP("2001:db8::1")}, "2001:db8::1"},
+		{"MX", "10 mx.example.com.", &MXRdata{Priority: 10, Exchange: "mx.example.com."}, "10 mx.example.com."},
+		{"SRV", "10 60 5060 sip.example.com.", &SRVRdata{Priority: 10, Weight: 60, Port: 5060, Target: "sip.example.com."}, "10 60 5060 sip.example.com."},
+		{"TXT", `"v=spf1 -all" "say \"hi\""`, &TXTRdata{Strings: []string{"v=spf1 -all", `say "hi"`}}, `"v=spf1 -all" "say \"hi\""`},
+		{"CAA", `0 issue "letsencrypt.org"`, &CAARdata{Flags: 0, Tag: "issue", Value: "letsencrypt.org"}, `0 issue "letsencrypt.org"`},
+		{"TLSA", "3 1 1 abcdef01", &TLSARdata{Usage: 3, Selector: 1, MatchingType: 1, Certificate: "abcdef01"}, "3 1 1 abcdef01"},
+		{"HTTPS", "1 . alpn=h2,h3 port=8443", &HTTPSRdata{SVCBRdata{Priority: 1, Target: ".", Params: []SVCBParam{{"alpn", "h2,h3"}, {"port", "8443"}}}}, "1 . alpn=h2,h3 port=8443"},
+		{"NAPTR", `100 10 "U" "E2U+sip" "!^.*$!sip:info@example.com!" .`, &NAPTRRdata{Order: 100, Preference: 10, Flags: "U", Service: "E2U+sip", Regexp: "!^.*$!sip:info@example.com!", Replacement: "."}, `100 10 "U" "E2U+sip" "!^.*$!sip:info@example.com!" .`},
+		{"DS", "60485 5 1 2bb183af5f22588179a53b0a98631fad1a292118", &DSRdata{KeyTag: 60485, Algorithm: 5, DigestType: 1, Digest: "2bb183af5f22588179a53b0a98631fad1a292118"}, "60485 5 1 2BB183AF5F22588179A53B0A98631FAD1A292118"},
+		{"DNSKEY", "257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0d xCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==", &DNSKEYRdata{Flags: 257, Protocol: 3, Algorithm: 13, PublicKey: "mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ=="}, "257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ=="},
+		{"HINFO", `"PC" "Linux"`, &UnknownRdata{RecordType: "HINFO", Data: `"PC" "Linux"`}, `"PC" "Linux"`},
+	}
+
+	for _, test := range tests {
+		parsed, err := ParseRdata(test.recordType, test.rdata)
+		if assert.NoError(t, err, test.recordType) {
+			assert.Equal(t, test.expected, parsed, test.recordType)
+			assert.Equal(t, test.formatted, parsed.String(), test.recordType)
+			assert.NoError(t, parsed.Validate(), test.recordType)
+		}
+	}
+
+	_, err := ParseRdata("MX", "mx.example.com.")
+	assert.EqualError(t, err, `MX rdata "mx.example.com.": expected 2 fields, got 1`)
+}
+
+func TestRdata_Validate(t *testing.T) {
+	assert.EqualError(t, (&ARdata{Address: net.ParseIP("2001:db8::1")}).Validate(), `invalid IPv4 address "2001:db8::1"`)
+	assert.EqualError(t, (&CAARdata{Tag: "issuer", Value: "ca.example"}).Validate(), `invalid CAA tag "issuer", expected issue, issuewild or iodef`)
+	assert.EqualError(t, (&DSRdata{KeyTag: 1, Algorithm: 13, DigestType: 2, Digest: "abcd"}).Validate(), "DS digest type 2 must be 64 hex characters")
+	assert.EqualError(t, (&HTTPSRdata{SVCBRdata{Priority: 0, Target: "svc.example.com.", Params: []SVCBParam{{"alpn", "h2"}}}}).Validate(), "alias mode HTTPS record has parameters")
+	assert.EqualError(t, (&CNAMERdata{Target: "bad name.example.com."}).Validate(), `invalid target "bad name.example.com."`)
+}
+
+func TestNewRecordset(t *testing.T) {
+	recordset, err := NewRecordset("example.com", 300,
+		&MXRdata{Priority: 10, Exchange: "mx1.example.com."},
+		&MXRdata{Priority: 20, Exchange: "mx2.example.com."},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, Recordset{Name: "example.com", Type: "MX", TTL: 300, Rdata: []string{"10 mx1.example.com.", "20 mx2.example.com."}}, recordset)
+
+	record := &RecordBody{Name: "www.example.com", TTL: 300}
+	err = record.SetRdata(&ARdata{Address: net.ParseIP("192.0.2.1")}, &CNAMERdata{Target: "www.example.net."})
+	assert.EqualError(t, err, "recordset www.example.com mixes A and CNAME rdata")
+}
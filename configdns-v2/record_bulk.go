@@ -0,0 +1,260 @@
+package dnsv2
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// DefaultRecordsetChunkSize is the number of recordsets sent per request when
+// no chunk size is given
+const DefaultRecordsetChunkSize = 1000
+
+// Recordsets is the request body of the multi-recordset endpoints
+type Recordsets struct {
+	Recordsets []Recordset `json:"recordsets"`
+}
+
+// RecordsetError is a recordset rejected by a multi-recordset request
+type RecordsetError struct {
+	Recordset Recordset
+	Detail    string
+}
+
+// BulkRecordsetError lists the recordsets rejected by multi-recordset requests
+//
+// Recordsets in chunks that were accepted were saved. When the API does not
+// identify the offending recordset, every recordset of the chunk is listed.
+type BulkRecordsetError struct {
+	zoneName         string
+	httpErrorMessage string
+	Failed           []*RecordsetError
+}
+
+func (e *BulkRecordsetError) Network() bool {
+	return e.httpErrorMessage != ""
+}
+
+func (e *BulkRecordsetError) NotFound() bool {
+	return false
+}
+
+func (e *BulkRecordsetError) FailedToSave() bool {
+	return true
+}
+
+func (e *BulkRecordsetError) ValidationFailed() bool {
+	return e.httpErrorMessage == ""
+}
+
+func (e *BulkRecordsetError) Error() string {
+	if e.Network() {
+		return fmt.Sprintf("Zone \"%s\" network error: [%s]", e.zoneName, e.httpErrorMessage)
+	}
+
+	failures := make([]string, 0, len(e.Failed))
+	for _, failed := range e.Failed {
+		failures = append(failures, fmt.Sprintf("%s %s: %s", failed.Recordset.Name, failed.Recordset.Type, failed.Detail))
+	}
+
+	return fmt.Sprintf("Zone \"%s\" failed to save %d recordsets: [%s]", e.zoneName, len(e.Failed), strings.Join(failures, "; "))
+}
+
+// recordsetErrorIndex finds the recordset index in error fields such as "recordsets[3].rdata"
+var recordsetErrorIndex = regexp.MustCompile(`recordsets\[(\d+)\]`)
+
+// CreateRecordsets creates many recordsets in a zone
+//
+// Recordsets are sent chunkSize at a time, DefaultRecordsetChunkSize if
+// chunkSize is 0. Every chunk is attempted; a *BulkRecordsetError lists the
+// recordsets of chunks that were rejected.
+//
+// Endpoint: POST /config-dns/v2/zones/{zone}/recordsets
+func CreateRecordsets(zone string, recordsets []Recordset, chunkSize int) error {
+	bulkErr := &BulkRecordsetError{zoneName: zone}
+
+	for _, chunk := range chunkRecordsets(recordsets, chunkSize) {
+		if err := saveRecordsets(zone, "POST", chunk, bulkErr); err != nil {
+			return err
+		}
+	}
+
+	if len(bulkErr.Failed) != 0 {
+		return bulkErr
+	}
+
+	return nil
+}
+
+// ReplaceRecordsets replaces every recordset of a zone
+//
+// Once replaced, the zone has exactly the given recordsets, whatever their
+// type, except for the SOA record: it is managed by Edge DNS and kept as is,
+// any SOA in recordsets is ignored. recordsets must include the NS records of
+// the zone.
+//
+// If every recordset fits in one chunk of chunkSize, DefaultRecordsetChunkSize
+// if chunkSize is 0, the zone is replaced in a single request along with its
+// current SOA, and a *BulkRecordsetError lists the rejected recordsets. Larger
+// zones are replaced atomically through a changelist instead, see
+// PlanZoneSync() and ZoneSyncPlan.Apply(). If any change is rejected the
+// changelist is discarded. Either way the zone is left unchanged on error.
+//
+// Endpoint: PUT /config-dns/v2/zones/{zone}/recordsets
+func ReplaceRecordsets(zone string, recordsets []Recordset, chunkSize int) error {
+	if chunkSize <= 0 {
+		chunkSize = DefaultRecordsetChunkSize
+	}
+
+	if len(recordsets) > chunkSize {
+		plan, err := PlanZoneSync(zone, recordsets, ZoneSyncOptions{Prune: true})
+		if err != nil {
+			return err
+		}
+
+		return plan.Apply()
+	}
+
+	soa, err := GetRecordList(zone, zone, "SOA")
+	if err != nil {
+		return err
+	}
+
+	replaced := make([]Recordset, 0, len(recordsets)+1)
+	for _, recordset := range soa.Recordsets {
+		if strings.EqualFold(recordset.Type, "SOA") {
+			replaced = append(replaced, recordset)
+		}
+	}
+	for _, recordset := range recordsets {
+		if !strings.EqualFold(recordset.Type, "SOA") {
+			replaced = append(replaced, recordset)
+		}
+	}
+
+	sort.SliceStable(replaced, func(i, j int) bool {
+		return zoneApexRank(replaced[i].Type) < zoneApexRank(replaced[j].Type)
+	})
+
+	bulkErr := &BulkRecordsetError{zoneName: zone}
+	if err := saveRecordsets(zone, "PUT", replaced, bulkErr); err != nil {
+		return err
+	}
+
+	if len(bulkErr.Failed) != 0 {
+		return bulkErr
+	}
+
+	return nil
+}
+
+// zoneApexRank orders SOA then NS recordsets before any other type
+func zoneApexRank(recordType string) int {
+	switch strings.ToUpper(recordType) {
+	case "SOA":
+		return 0
+	case "NS":
+		return 1
+	}
+
+	return 2
+}
+
+// saveRecordsets sends a single chunk, adding rejected recordsets to bulkErr
+func saveRecordsets(zone string, method string, chunk []Recordset, bulkErr *BulkRecordsetError) error {
+	// See RecordBody.Save(), saves are serialized to keep the SOA serial consistent
+	zoneRecordWriteLock.Lock()
+	defer zoneRecordWriteLock.Unlock()
+
+	req, err := client.NewJSONRequest(
+		Config,
+		method,
+		"/config-dns/v2/zones/"+zone+"/recordsets",
+		&Recordsets{Recordsets: chunk},
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+
+	// Network error
+	if err != nil {
+		bulkErr.httpErrorMessage = err.Error()
+		return bulkErr
+	}
+
+	// API error
+	if client.IsError(res) {
+		apiErr := client.NewAPIError(res)
+		bulkErr.Failed = append(bulkErr.Failed, recordsetErrors(chunk, apiErr)...)
+	}
+
+	return nil
+}
+
+// recordsetErrors attributes the errors of a rejected chunk to its recordsets
+func recordsetErrors(chunk []Recordset, apiErr client.APIError) []*RecordsetError {
+	body := &struct {
+		Errors []struct {
+			Field  string `json:"field"`
+			Detail string `json:"detail"`
+			Title  string `json:"title"`
+		} `json:"errors"`
+	}{}
+	json.Unmarshal([]byte(apiErr.RawBody), body)
+
+	var failed []*RecordsetError
+	for _, cause := range body.Errors {
+		detail := cause.Detail
+		if detail == "" {
+			detail = cause.Title
+		}
+
+		match := recordsetErrorIndex.FindStringSubmatch(cause.Field + " " + detail)
+		if match == nil {
+			continue
+		}
+
+		index, _ := strconv.Atoi(match[1])
+		if index < len(chunk) {
+			failed = append(failed, &RecordsetError{Recordset: chunk[index], Detail: detail})
+		}
+	}
+
+	if len(failed) != 0 {
+		return failed
+	}
+
+	detail := apiErr.Detail
+	if detail == "" {
+		detail = apiErr.Title
+	}
+	for _, recordset := range chunk {
+		failed = append(failed, &RecordsetError{Recordset: recordset, Detail: detail})
+	}
+
+	return failed
+}
+
+func chunkRecordsets(recordsets []Recordset, chunkSize int) [][]Recordset {
+	if chunkSize <= 0 {
+		chunkSize = DefaultRecordsetChunkSize
+	}
+
+	var chunks [][]Recordset
+	for start := 0; start < len(recordsets); start += chunkSize {
+		end := start + chunkSize
+		if end > len(recordsets) {
+			end = len(recordsets)
+		}
+		chunks = append(chunks, recordsets[start:end])
+	}
+
+	return chunks
+}
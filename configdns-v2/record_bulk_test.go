@@ -0,0 +1,181 @@
+package dnsv2
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestCreateRecordsets(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/zones/example.com/recordsets").
+		JSON(`{"recordsets": [
+			{"name": "a.example.com", "type": "A", "ttl": 300, "rdata": ["192.0.2.1"]},
+			{"name": "b.example.com", "type": "A", "ttl": 300, "rdata": ["192.0.2.2"]}
+		]}`).
+		HeaderPresent("Authorization").
+		Reply(201)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/zones/example.com/recordsets").
+		JSON(`{"recordsets": [
+			{"name": "c.example.com", "type": "A", "ttl": 300, "rdata": ["192.0.2.3"]},
+			{"name": "d.example.com", "type": "A", "ttl": 300, "rdata": ["not-an-ip"]}
+		]}`).
+		HeaderPresent("Authorization").
+		Reply(400).
+		SetHeader("Content-Type", "application/problem+json").
+		BodyString(`{
+			"type": "https://problems.luna.akamaiapis.net/config-dns/v2/validation",
+			"title": "Invalid Data",
+			"status": 400,
+			"detail": "Validation failed",
+			"errors": [{"field": "recordsets[1].rdata[0]", "detail": "Invalid IPv4 address"}]
+		}`)
+
+	Init(config)
+
+	err := CreateRecordsets("example.com", []Recordset{
+		{Name: "a.example.com", Type: "A", TTL: 300, Rdata: []string{"192.0.2.1"}},
+		{Name: "b.example.com", Type: "A", TTL: 300, Rdata: []string{"192.0.2.2"}},
+		{Name: "c.example.com", Type: "A", TTL: 300, Rdata: []string{"192.0.2.3"}},
+		{Name: "d.example.com", Type: "A", TTL: 300, Rdata: []string{"not-an-ip"}},
+	}, 2)
+
+	if assert.IsType(t, &BulkRecordsetError{}, err) {
+		bulkErr := err.(*BulkRecordsetError)
+		assert.True(t, bulkErr.ValidationFailed())
+		if assert.Len(t, bulkErr.Failed, 1) {
+			assert.Equal(t, "d.example.com", bulkErr.Failed[0].Recordset.Name)
+			assert.Equal(t, "Invalid IPv4 address", bulkErr.Failed[0].Detail)
+		}
+	}
+	assert.True(t, gock.IsDone())
+}
+
+func TestReplaceRecordsets(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones/example.com/recordsets").
+		MatchParam("types", "SOA").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"recordsets": [
+			{"name": "example.com", "type": "SOA", "ttl": 86400, "rdata": ["a1-1.akam.net. hostmaster.example.com. 7 3600 600 604800 300"]}
+		]}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Put("/config-dns/v2/zones/example.com/recordsets").
+		JSON(`{"recordsets": [
+			{"name": "example.com", "type": "SOA", "ttl": 86400, "rdata": ["a1-1.akam.net. hostmaster.example.com. 7 3600 600 604800 300"]},
+			{"name": "example.com", "type": "NS", "ttl": 86400, "rdata": ["a1-1.akam.net."]},
+			{"name": "a.example.com", "type": "A", "ttl": 300, "rdata": ["192.0.2.1"]},
+			{"name": "b.example.com", "type": "A", "ttl": 300, "rdata": ["192.0.2.2"]}
+		]}`).
+		HeaderPresent("Authorization").
+		Reply(400).
+		SetHeader("Content-Type", "application/problem+json").
+		BodyString(`{"title": "Invalid Data", "status": 400, "errors": [{"field": "recordsets[3].rdata", "detail": "Duplicate address"}]}`)
+
+	Init(config)
+
+	err := ReplaceRecordsets("example.com", []Recordset{
+		{Name: "a.example.com", Type: "A", TTL: 300, Rdata: []string{"192.0.2.1"}},
+		{Name: "example.com", Type: "NS", TTL: 86400, Rdata: []string{"a1-1.akam.net."}},
+		{Name: "b.example.com", Type: "A", TTL: 300, Rdata: []string{"192.0.2.2"}},
+		{Name: "example.com", Type: "SOA", TTL: 86400, Rdata: []string{"a1-1.akam.net. hostmaster.example.com. 1 3600 600 604800 300"}},
+	}, 0)
+
+	assert.EqualError(t, err, `Zone "example.com" failed to save 1 recordsets: [b.example.com A: Duplicate address]`)
+	assert.True(t, gock.IsDone())
+}
+
+func TestReplaceRecordsets_Changelist(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones/example.com/recordsets").
+		MatchParam("showAll", "true").
+		AddMatcher(func(req *http.Request, ereq *gock.Request) (bool, error) {
+			// Every type is retrieved, so that any type can be pruned
+			_, filtered := req.URL.Query()["types"]
+			return !filtered, nil
+		}).
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"recordsets": [
+			{"name": "example.com", "type": "SOA", "ttl": 86400, "rdata": ["a1-1.akam.net. hostmaster.example.com. 1 3600 600 604800 300"]},
+			{"name": "example.com", "type": "NS", "ttl": 86400, "rdata": ["a1-1.akam.net."]},
+			{"name": "www.example.com", "type": "AKAMAICDN", "ttl": 20, "rdata": ["www.example.com.edgesuite.net"]},
+			{"name": "old.example.com", "type": "A", "ttl": 300, "rdata": ["192.0.2.9"]}
+		]}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/changelists").
+		MatchParam("zone", "example.com").
+		MatchParam("overwrite", "false").
+		HeaderPresent("Authorization").
+		Reply(201)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/changelists/example.com").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"zone": "example.com", "changeTag": "tag", "zoneVersionId": "v1", "stale": false}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/changelists/example.com/recordsets/add-change").
+		JSON(`{"name": "a.example.com", "type": "A", "op": "ADD", "ttl": 300, "rdata": ["192.0.2.1"]}`).
+		HeaderPresent("Authorization").
+		Reply(204)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/changelists/example.com/recordsets/add-change").
+		JSON(`{"name": "example.com", "type": "NS", "op": "EDIT", "ttl": 86400, "rdata": ["a1-1.akam.net.", "a2-2.akam.net."]}`).
+		HeaderPresent("Authorization").
+		Reply(204)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/changelists/example.com/recordsets/add-change").
+		JSON(`{"name": "old.example.com", "type": "A", "op": "DELETE"}`).
+		HeaderPresent("Authorization").
+		Reply(400).
+		SetHeader("Content-Type", "application/problem+json").
+		BodyString(`{"title": "Invalid", "detail": "record is locked", "status": 400}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Delete("/config-dns/v2/changelists/example.com").
+		HeaderPresent("Authorization").
+		Reply(204)
+
+	Init(config)
+
+	err := ReplaceRecordsets("example.com", []Recordset{
+		{Name: "example.com", Type: "SOA", TTL: 86400, Rdata: []string{"a1-1.akam.net. hostmaster.example.com. 1 3600 600 604800 300"}},
+		{Name: "example.com", Type: "NS", TTL: 86400, Rdata: []string{"a1-1.akam.net.", "a2-2.akam.net."}},
+		{Name: "a.example.com", Type: "A", TTL: 300, Rdata: []string{"192.0.2.1"}},
+		{Name: "www.example.com", Type: "AKAMAICDN", TTL: 20, Rdata: []string{"www.example.com.edgesuite.net"}},
+	}, 2)
+
+	assert.EqualError(t, err, `Zone "example.com" validation failed: [old.example.com A: record is locked]`)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,328 @@
+package dnsv2
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// ZoneSyncOptions controls how a zone is reconciled with its desired recordsets
+//
+// SOA records are never changed, as Edge DNS manages the serial.
+type ZoneSyncOptions struct {
+	// Prune deletes live recordsets that are not desired
+	Prune bool
+	// IgnoreTypes are record types left untouched
+	IgnoreTypes []string
+}
+
+// ZoneSyncChange is a recordset to create, update or delete
+type ZoneSyncChange struct {
+	Action  ZoneSyncActionValue `json:"action"`
+	Current *Recordset          `json:"current,omitempty"`
+	Desired *Recordset          `json:"desired,omitempty"`
+}
+
+// ZoneSyncPlan is the set of changes that bring a zone to its desired recordsets
+type ZoneSyncPlan struct {
+	Zone    string            `json:"zone"`
+	Changes []*ZoneSyncChange `json:"changes"`
+}
+
+// PlanZoneSync compares desired recordsets with the live zone
+//
+// The desired recordsets may come from code or from ParseZoneFile(). Every
+// live recordset is compared, whatever its type. The plan is not applied, see
+// ZoneSyncPlan.Apply().
+func PlanZoneSync(zone string, desired []Recordset, options ZoneSyncOptions) (*ZoneSyncPlan, error) {
+	live, err := getZoneRecordsets(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return ComputeZoneSyncPlan(zone, desired, live, options), nil
+}
+
+// getZoneRecordsets retrieves every recordset of a zone, of any type
+//
+// Endpoint: GET /config-dns/v2/zones/{zone}/recordsets
+func getZoneRecordsets(zone string) ([]Recordset, error) {
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		"/config-dns/v2/zones/"+zone+"/recordsets?showAll=true",
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 404 {
+		return nil, &ZoneError{zoneName: zone}
+	} else if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	records := NewRecordSetResponse(zone)
+	if err := client.BodyJSON(res, records); err != nil {
+		return nil, err
+	}
+
+	return records.Recordsets, nil
+}
+
+// ComputeZoneSyncPlan compares desired recordsets with live recordsets
+//
+// Recordsets are compared after normalization, see NormalizeRecordset().
+func ComputeZoneSyncPlan(zone string, desired []Recordset, live []Recordset, options ZoneSyncOptions) *ZoneSyncPlan {
+	ignored := map[string]bool{"SOA": true}
+	for _, recordType := range options.IgnoreTypes {
+		ignored[strings.ToUpper(recordType)] = true
+	}
+
+	plan := &ZoneSyncPlan{Zone: zone}
+
+	current := map[string]Recordset{}
+	for _, recordset := range live {
+		recordset = NormalizeRecordset(recordset)
+		if !ignored[recordset.Type] {
+			current[recordsetKey(recordset)] = recordset
+		}
+	}
+
+	wanted := map[string]bool{}
+	for _, recordset := range desired {
+		recordset := NormalizeRecordset(recordset)
+		if ignored[recordset.Type] {
+			continue
+		}
+
+		key := recordsetKey(recordset)
+		wanted[key] = true
+
+		existing, ok := current[key]
+		switch {
+		case !ok:
+			plan.Changes = append(plan.Changes, &ZoneSyncChange{Action: ZoneSyncActionCreate, Desired: &recordset})
+		case !recordsetsEqual(existing, recordset):
+			plan.Changes = append(plan.Changes, &ZoneSyncChange{Action: ZoneSyncActionUpdate, Current: &existing, Desired: &recordset})
+		}
+	}
+
+	if options.Prune {
+		for key, recordset := range current {
+			if !wanted[key] {
+				recordset := recordset
+				plan.Changes = append(plan.Changes, &ZoneSyncChange{Action: ZoneSyncActionDelete, Current: &recordset})
+			}
+		}
+	}
+
+	sort.SliceStable(plan.Changes, func(i, j int) bool {
+		return recordsetKey(*plan.Changes[i].recordset()) < recordsetKey(*plan.Changes[j].recordset())
+	})
+
+	return plan
+}
+
+// Empty reports whether the zone is already in sync
+func (plan *ZoneSyncPlan) Empty() bool {
+	return len(plan.Changes) == 0
+}
+
+// String formats the plan for review, one change per line
+//
+// Lines are prefixed with "+" for recordsets to create, "~" for recordsets to
+// update, followed by their current TTL and rdata, and "-" for recordsets to
+// delete.
+func (plan *ZoneSyncPlan) String() string {
+	if plan.Empty() {
+		return fmt.Sprintf("zone %s is in sync\n", plan.Zone)
+	}
+
+	var builder strings.Builder
+	for _, change := range plan.Changes {
+		switch change.Action {
+		case ZoneSyncActionCreate:
+			fmt.Fprintf(&builder, "+ %s\n", formatRecordset(change.Desired))
+		case ZoneSyncActionUpdate:
+			fmt.Fprintf(&builder, "~ %s (was %d %s)\n", formatRecordset(change.Desired), change.Current.TTL, strings.Join(change.Current.Rdata, ", "))
+		case ZoneSyncActionDelete:
+			fmt.Fprintf(&builder, "- %s\n", formatRecordset(change.Current))
+		}
+	}
+
+	return builder.String()
+}
+
+// Apply makes the changes of the plan atomically through a changelist
+//
+// A changelist is created from the current zone version, every change is
+// added to it and it is submitted. If any change is rejected the changelist
+// is discarded and the zone is left unchanged.
+func (plan *ZoneSyncPlan) Apply() error {
+	if plan.Empty() {
+		return nil
+	}
+
+	if _, err := CreateChangelist(plan.Zone, false); err != nil {
+		return err
+	}
+
+	for _, change := range plan.Changes {
+		if err := AddChangelistChange(plan.Zone, change.changelistChange()); err != nil {
+			return discardChangelistAfter(plan.Zone, err)
+		}
+	}
+
+	if err := SubmitChangelist(plan.Zone, false); err != nil {
+		return discardChangelistAfter(plan.Zone, err)
+	}
+
+	return nil
+}
+
+// discardChangelistAfter discards the changelist of a zone after err, adding
+// any error discarding it so the caller knows the changelist was left open
+func discardChangelistAfter(zone string, err error) error {
+	if discardErr := DiscardChangelist(zone); discardErr != nil {
+		return fmt.Errorf("%s (discarding the changelist failed: %s)", err, discardErr)
+	}
+
+	return err
+}
+
+// NormalizeRecordset returns a recordset in a canonical form for comparison
+//
+// Names are lower case without a trailing dot, domain names within rdata are
+// lower case with a trailing dot, AAAA addresses are fully expanded, TXT
+// values are quoted and split into strings of at most 255 characters, rdata
+// of types with a typed model is reformatted (see ParseRdata()) and rdata is
+// sorted.
+func NormalizeRecordset(recordset Recordset) Recordset {
+	normalized := Recordset{
+		Name:  strings.ToLower(strings.TrimSuffix(recordset.Name, ".")),
+		Type:  strings.ToUpper(recordset.Type),
+		TTL:   recordset.TTL,
+		Rdata: make([]string, 0, len(recordset.Rdata)),
+	}
+
+	for _, rdata := range recordset.Rdata {
+		normalized.Rdata = append(normalized.Rdata, normalizeRdata(normalized.Type, rdata))
+	}
+	sort.Strings(normalized.Rdata)
+
+	return normalized
+}
+
+func normalizeRdata(recordType string, rdata string) string {
+	rdata = strings.TrimSpace(rdata)
+
+	switch recordType {
+	case "AAAA":
+		if ip := net.ParseIP(rdata); ip != nil {
+			return FullIPv6(ip)
+		}
+		return rdata
+	case "TXT", "SPF":
+		values := []string{rdata}
+		if strings.HasPrefix(rdata, "\"") {
+			var err error
+			if values, err = splitRdata(rdata); err != nil {
+				return rdata
+			}
+		}
+		return formatCharacterStrings(splitCharacterStrings(values))
+	}
+
+	if typed, err := ParseRdata(recordType, rdata); err == nil {
+		if _, unknown := typed.(*UnknownRdata); !unknown {
+			rdata = typed.String()
+		}
+	}
+
+	fields, ok := rdataNameFields[recordType]
+	if !ok {
+		return rdata
+	}
+
+	parts := strings.Fields(rdata)
+	for _, field := range fields {
+		if field < len(parts) {
+			parts[field] = strings.ToLower(strings.TrimSuffix(parts[field], ".")) + "."
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// maxCharacterString is the length limit of a single TXT character string
+const maxCharacterString = 255
+
+// splitCharacterStrings splits strings longer than 255 characters into
+// chunks, as Edge DNS does when storing TXT values
+func splitCharacterStrings(values []string) []string {
+	var split []string
+	for _, value := range values {
+		for len(value) > maxCharacterString {
+			split = append(split, value[:maxCharacterString])
+			value = value[maxCharacterString:]
+		}
+		split = append(split, value)
+	}
+
+	return split
+}
+
+func recordsetsEqual(a Recordset, b Recordset) bool {
+	return a.TTL == b.TTL && strings.Join(a.Rdata, "\n") == strings.Join(b.Rdata, "\n")
+}
+
+func recordsetKey(recordset Recordset) string {
+	return recordset.Name + " " + recordset.Type
+}
+
+func formatRecordset(recordset *Recordset) string {
+	return fmt.Sprintf("%s %s %d %s", recordset.Name, recordset.Type, recordset.TTL, strings.Join(recordset.Rdata, ", "))
+}
+
+// recordset returns the recordset a change applies to
+func (change *ZoneSyncChange) recordset() *Recordset {
+	if change.Desired != nil {
+		return change.Desired
+	}
+
+	return change.Current
+}
+
+// changelistChange converts a zone sync change to a changelist change
+func (change *ZoneSyncChange) changelistChange() *ChangelistChange {
+	switch change.Action {
+	case ZoneSyncActionCreate:
+		return NewChangelistChange(ChangelistOpAdd, *change.Desired)
+	case ZoneSyncActionUpdate:
+		return NewChangelistChange(ChangelistOpEdit, *change.Desired)
+	}
+
+	return NewChangelistChange(ChangelistOpDelete, *change.Current)
+}
+
+// ZoneSyncActionValue is used to create an "enum" of possible ZoneSyncChange.Action values
+type ZoneSyncActionValue string
+
+const (
+	// ZoneSyncActionCreate ZoneSyncChange.Action value CREATE
+	ZoneSyncActionCreate ZoneSyncActionValue = "CREATE"
+	// ZoneSyncActionUpdate ZoneSyncChange.Action value UPDATE
+	ZoneSyncActionUpdate ZoneSyncActionValue = "UPDATE"
+	// ZoneSyncActionDelete ZoneSyncChange.Action value DELETE
+	ZoneSyncActionDelete ZoneSyncActionValue = "DELETE"
+)
@@ -0,0 +1,157 @@
+package dnsv2
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestComputeZoneSyncPlan(t *testing.T) {
+	live := []Recordset{
+		{Name: "example.com", Type: "SOA", TTL: 3600, Rdata: []string{"ns1.example.com. hostmaster.example.com. 2019061101 3600 600 604800 300"}},
+		{Name: "WWW.example.com.", Type: "AAAA", TTL: 300, Rdata: []string{"2001:0db8:0000:0000:0000:0000:0000:0001"}},
+		{Name: "example.com", Type: "TXT", TTL: 300, Rdata: []string{`"v=spf1 -all"`}},
+		{Name: "mail.example.com", Type: "MX", TTL: 300, Rdata: []string{"10 MX.example.com."}},
+		{Name: "old.example.com", Type: "CNAME", TTL: 300, Rdata: []string{"www.example.com."}},
+	}
+	desired := []Recordset{
+		{Name: "example.com", Type: "SOA", TTL: 60, Rdata: []string{"ns1.example.com. hostmaster.example.com. 1 3600 600 604800 300"}},
+		{Name: "www.example.com", Type: "AAAA", TTL: 300, Rdata: []string{"2001:db8::1"}},
+		{Name: "example.com", Type: "TXT", TTL: 300, Rdata: []string{"v=spf1 -all"}},
+		{Name: "mail.example.com", Type: "MX", TTL: 3600, Rdata: []string{"10 mx.example.com"}},
+		{Name: "api.example.com", Type: "A", TTL: 300, Rdata: []string{"192.0.2.1"}},
+	}
+
+	plan := ComputeZoneSyncPlan("example.com", desired, live, ZoneSyncOptions{})
+	assert.Equal(t, "+ api.example.com A 300 192.0.2.1\n~ mail.example.com MX 3600 10 mx.example.com. (was 300 10 mx.example.com.)\n", plan.String())
+
+	plan = ComputeZoneSyncPlan("example.com", desired, live, ZoneSyncOptions{Prune: true, IgnoreTypes: []string{"MX"}})
+	if assert.Len(t, plan.Changes, 2) {
+		assert.Equal(t, ZoneSyncActionCreate, plan.Changes[0].Action)
+		assert.Equal(t, ZoneSyncActionDelete, plan.Changes[1].Action)
+		assert.Equal(t, "old.example.com", plan.Changes[1].Current.Name)
+	}
+}
+
+func TestZoneSyncPlan_Apply(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/changelists").
+		MatchParam("zone", "example.com").
+		MatchParam("overwrite", "false").
+		HeaderPresent("Authorization").
+		Reply(201)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/changelists/example.com").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"zone": "example.com", "changeTag": "tag", "zoneVersionId": "v1", "stale": false}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/changelists/example.com/recordsets/add-change").
+		JSON(`{"name": "api.example.com", "type": "A", "op": "ADD", "ttl": 300, "rdata": ["192.0.2.1"]}`).
+		HeaderPresent("Authorization").
+		Reply(204)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/changelists/example.com/recordsets/add-change").
+		JSON(`{"name": "old.example.com", "type": "CNAME", "op": "DELETE"}`).
+		HeaderPresent("Authorization").
+		Reply(400).
+		SetHeader("Content-Type", "application/problem+json").
+		BodyString(`{"type": "https://problems.luna.akamaiapis.net/config-dns/v2/invalid", "title": "Invalid", "detail": "record is locked", "status": 400}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Delete("/config-dns/v2/changelists/example.com").
+		HeaderPresent("Authorization").
+		Reply(204)
+
+	Init(config)
+
+	plan := &ZoneSyncPlan{Zone: "example.com", Changes: []*ZoneSyncChange{
+		{Action: ZoneSyncActionCreate, Desired: &Recordset{Name: "api.example.com", Type: "A", TTL: 300, Rdata: []string{"192.0.2.1"}}},
+		{Action: ZoneSyncActionDelete, Current: &Recordset{Name: "old.example.com", Type: "CNAME", TTL: 300, Rdata: []string{"www.example.com."}}},
+	}}
+
+	err := plan.Apply()
+	assert.EqualError(t, err, `Zone "example.com" validation failed: [old.example.com CNAME: record is locked]`)
+	assert.True(t, gock.IsDone())
+}
+
+func TestNormalizeRecordset_LongTXT(t *testing.T) {
+	long := strings.Repeat("a", 255) + strings.Repeat("b", 45)
+	split := `"` + strings.Repeat("a", 255) + `" "` + strings.Repeat("b", 45) + `"`
+
+	assert.Equal(t, []string{split}, NormalizeRecordset(Recordset{Type: "TXT", Rdata: []string{long}}).Rdata)
+	assert.Equal(t, []string{split}, NormalizeRecordset(Recordset{Type: "TXT", Rdata: []string{`"` + long + `"`}}).Rdata)
+	assert.Equal(t, []string{split}, NormalizeRecordset(Recordset{Type: "TXT", Rdata: []string{split}}).Rdata)
+
+	live := []Recordset{{Name: "example.com", Type: "TXT", TTL: 300, Rdata: []string{split}}}
+	desired := []Recordset{{Name: "example.com", Type: "TXT", TTL: 300, Rdata: []string{long}}}
+	assert.True(t, ComputeZoneSyncPlan("example.com", desired, live, ZoneSyncOptions{}).Empty())
+}
+
+func TestZoneSyncPlan_Apply_DiscardFails(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/changelists").
+		MatchParam("zone", "example.com").
+		HeaderPresent("Authorization").
+		Reply(201)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/changelists/example.com").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"zone": "example.com", "changeTag": "tag", "zoneVersionId": "v1", "stale": false}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/changelists/example.com/recordsets/add-change").
+		HeaderPresent("Authorization").
+		Reply(204)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/changelists/example.com").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"zone": "example.com", "changeTag": "tag", "zoneVersionId": "v1", "stale": true}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Delete("/config-dns/v2/changelists/example.com").
+		HeaderPresent("Authorization").
+		Reply(500).
+		SetHeader("Content-Type", "application/problem+json").
+		BodyString(`{"type": "https://problems.luna.akamaiapis.net/config-dns/v2/error", "title": "Error", "detail": "internal error", "status": 500}`)
+
+	Init(config)
+
+	plan := &ZoneSyncPlan{Zone: "example.com", Changes: []*ZoneSyncChange{
+		{Action: ZoneSyncActionCreate, Desired: &Recordset{Name: "api.example.com", Type: "A", TTL: 300, Rdata: []string{"192.0.2.1"}}},
+	}}
+
+	err := plan.Apply()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "stale")
+		assert.Contains(t, err.Error(), "discarding the changelist failed")
+		assert.Contains(t, err.Error(), "internal error")
+	}
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,364 @@
+package dnsv2
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// TSIGKey is a transaction signature key used to authenticate zone transfers
+// from the masters of a secondary zone
+type TSIGKey struct {
+	Name      string             `json:"name"`
+	Algorithm TSIGAlgorithmValue `json:"algorithm,omitempty"`
+	Secret    string             `json:"secret,omitempty"`
+}
+
+// TSIGKeyResponse is a TSIG key and the number of zones using it
+type TSIGKeyResponse struct {
+	TSIGKey
+	ZonesCount int `json:"zonesCount,omitempty"`
+}
+
+// TSIGKeyListResponse is a list of TSIG keys
+type TSIGKeyListResponse struct {
+	Keys []*TSIGKeyResponse `json:"keys"`
+}
+
+// TSIGQueryString filters the TSIG keys returned by ListTSIGKeys()
+type TSIGQueryString struct {
+	ContractIds []string
+	Search      string
+	SortBy      []string
+	Gid         int
+}
+
+// ZoneNames is a list of zone names, the body of requests about several zones
+type ZoneNames struct {
+	Zones []string `json:"zones"`
+}
+
+// TSIGKeyBulkUpdate sets the TSIG key of many zones
+type TSIGKeyBulkUpdate struct {
+	Key   *TSIGKey `json:"key"`
+	Zones []string `json:"zones"`
+}
+
+// ZoneTransferStatus is the state of zone transfers of a secondary zone
+type ZoneTransferStatus struct {
+	Zone              string `json:"zone"`
+	MasterServer      string `json:"masterServer,omitempty"`
+	ZoneVersionId     string `json:"zoneVersionId,omitempty"`
+	LastRefreshTime   string `json:"lastRefreshTime,omitempty"`
+	LastRefreshStatus string `json:"lastRefreshStatus,omitempty"`
+	LastRefreshError  string `json:"lastRefreshError,omitempty"`
+	NextRefreshTime   string `json:"nextRefreshTime,omitempty"`
+	Expires           string `json:"expires,omitempty"`
+}
+
+// ZoneTransferStatusResponse is the transfer status of several zones
+type ZoneTransferStatusResponse struct {
+	Zones []*ZoneTransferStatus `json:"zones"`
+}
+
+// NewTSIGKey creates a new TSIGKey
+func NewTSIGKey(name string, algorithm TSIGAlgorithmValue, secret string) *TSIGKey {
+	return &TSIGKey{Name: name, Algorithm: algorithm, Secret: secret}
+}
+
+// Validate checks the key name, algorithm and base64 encoded secret
+func (key *TSIGKey) Validate() error {
+	if err := validateDomainName("TSIG key name", key.Name); err != nil {
+		return err
+	}
+
+	switch key.Algorithm {
+	case TSIGAlgorithmHmacMD5, TSIGAlgorithmHmacSHA1, TSIGAlgorithmHmacSHA224,
+		TSIGAlgorithmHmacSHA256, TSIGAlgorithmHmacSHA384, TSIGAlgorithmHmacSHA512:
+	default:
+		return fmt.Errorf("invalid TSIG key algorithm \"%s\"", key.Algorithm)
+	}
+
+	if _, err := base64.StdEncoding.DecodeString(key.Secret); err != nil || key.Secret == "" {
+		return fmt.Errorf("invalid TSIG key secret, expected base64")
+	}
+
+	return nil
+}
+
+// Failed reports whether the last transfer of the zone from its masters failed
+func (status *ZoneTransferStatus) Failed() bool {
+	return status.LastRefreshError != "" || strings.EqualFold(status.LastRefreshStatus, "FAILED")
+}
+
+// GetZoneTSIGKey retrieves the TSIG key of a secondary zone
+//
+// Endpoint: GET /config-dns/v2/zones/{zone}/key
+func GetZoneTSIGKey(zone string) (*TSIGKeyResponse, error) {
+	key := &TSIGKeyResponse{}
+
+	req, err := client.NewRequest(Config, "GET", "/config-dns/v2/zones/"+zone+"/key", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) && res.StatusCode != 404 {
+		return nil, client.NewAPIError(res)
+	} else if res.StatusCode == 404 {
+		return nil, &ZoneError{zoneName: zone}
+	}
+
+	if err = client.BodyJSON(res, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// UpdateZoneTSIGKey attaches a TSIG key to a secondary zone, replacing any existing key
+//
+// Endpoint: PUT /config-dns/v2/zones/{zone}/key
+func UpdateZoneTSIGKey(zone string, key *TSIGKey) error {
+	if err := key.Validate(); err != nil {
+		return &ZoneError{zoneName: zone, apiErrorMessage: err.Error(), err: err}
+	}
+
+	return saveTSIG(zone, "PUT", "/config-dns/v2/zones/"+zone+"/key", key)
+}
+
+// DeleteZoneTSIGKey detaches the TSIG key from a secondary zone
+//
+// Endpoint: DELETE /config-dns/v2/zones/{zone}/key
+func DeleteZoneTSIGKey(zone string) error {
+	return saveTSIG(zone, "DELETE", "/config-dns/v2/zones/"+zone+"/key", nil)
+}
+
+// GetTSIGKeyZones retrieves the zones that use the same TSIG key as a zone
+//
+// Endpoint: GET /config-dns/v2/zones/{zone}/key/used-by
+func GetTSIGKeyZones(zone string) ([]string, error) {
+	zones := &ZoneNames{}
+
+	req, err := client.NewRequest(Config, "GET", "/config-dns/v2/zones/"+zone+"/key/used-by", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) && res.StatusCode != 404 {
+		return nil, client.NewAPIError(res)
+	} else if res.StatusCode == 404 {
+		return nil, &ZoneError{zoneName: zone}
+	}
+
+	if err = client.BodyJSON(res, zones); err != nil {
+		return nil, err
+	}
+
+	return zones.Zones, nil
+}
+
+// ListTSIGKeys retrieves the TSIG keys available to the contracts of the account
+//
+// Secrets are not returned, see GetZoneTSIGKey().
+//
+// Endpoint: GET /config-dns/v2/keys{?contractIds,search,sortBy,gid}
+func ListTSIGKeys(query TSIGQueryString) (*TSIGKeyListResponse, error) {
+	keys := &TSIGKeyListResponse{}
+
+	values := url.Values{}
+	if len(query.ContractIds) != 0 {
+		values.Set("contractIds", strings.Join(query.ContractIds, ","))
+	}
+	if query.Search != "" {
+		values.Set("search", query.Search)
+	}
+	if len(query.SortBy) != 0 {
+		values.Set("sortBy", strings.Join(query.SortBy, ","))
+	}
+	if query.Gid != 0 {
+		values.Set("gid", strconv.Itoa(query.Gid))
+	}
+
+	path := "/config-dns/v2/keys"
+	if len(values) != 0 {
+		path += "?" + values.Encode()
+	}
+
+	req, err := client.NewRequest(Config, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	if err = client.BodyJSON(res, keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// FindTSIGKeyZones retrieves the zones that use a TSIG key
+//
+// Endpoint: POST /config-dns/v2/keys/used-by
+func FindTSIGKeyZones(key *TSIGKey) ([]string, error) {
+	zones := &ZoneNames{}
+
+	req, err := client.NewJSONRequest(Config, "POST", "/config-dns/v2/keys/used-by", key)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	if err = client.BodyJSON(res, zones); err != nil {
+		return nil, err
+	}
+
+	return zones.Zones, nil
+}
+
+// UpdateTSIGKeyBulk attaches a TSIG key to many secondary zones
+//
+// This is typically used to rotate the secret of a key shared by zones, see
+// FindTSIGKeyZones().
+//
+// Endpoint: POST /config-dns/v2/keys/bulk-update
+func UpdateTSIGKeyBulk(key *TSIGKey, zones []string) error {
+	if err := key.Validate(); err != nil {
+		return err
+	}
+
+	return saveTSIG(strings.Join(zones, ", "), "POST", "/config-dns/v2/keys/bulk-update", &TSIGKeyBulkUpdate{Key: key, Zones: zones})
+}
+
+// DeleteTSIGKeyBulk detaches the TSIG keys of many secondary zones
+//
+// Endpoint: POST /config-dns/v2/keys/bulk-delete
+func DeleteTSIGKeyBulk(zones []string) error {
+	return saveTSIG(strings.Join(zones, ", "), "POST", "/config-dns/v2/keys/bulk-delete", &ZoneNames{Zones: zones})
+}
+
+// GetZoneTransferStatus retrieves the transfer status of secondary zones
+//
+// Endpoint: POST /config-dns/v2/zones/zone-transfer-status
+func GetZoneTransferStatus(zones ...string) ([]*ZoneTransferStatus, error) {
+	status := &ZoneTransferStatusResponse{}
+
+	req, err := client.NewJSONRequest(Config, "POST", "/config-dns/v2/zones/zone-transfer-status", &ZoneNames{Zones: zones})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	if err = client.BodyJSON(res, status); err != nil {
+		return nil, err
+	}
+
+	return status.Zones, nil
+}
+
+// GetZoneTransferErrors retrieves the secondary zones whose last transfer failed
+func GetZoneTransferErrors(zones ...string) ([]*ZoneTransferStatus, error) {
+	statuses, err := GetZoneTransferStatus(zones...)
+	if err != nil {
+		return nil, err
+	}
+
+	var failed []*ZoneTransferStatus
+	for _, status := range statuses {
+		if status.Failed() {
+			failed = append(failed, status)
+		}
+	}
+
+	return failed, nil
+}
+
+// saveTSIG sends a TSIG key change, reporting errors against zone
+func saveTSIG(zone string, method string, path string, body interface{}) error {
+	var req *http.Request
+	var err error
+	if body == nil {
+		req, err = client.NewRequest(Config, method, path, nil)
+	} else {
+		req, err = client.NewJSONRequest(Config, method, path, body)
+	}
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+
+	// Network error
+	if err != nil {
+		return &ZoneError{
+			zoneName:         zone,
+			httpErrorMessage: err.Error(),
+			err:              err,
+		}
+	}
+
+	// API error
+	if client.IsError(res) {
+		err := client.NewAPIError(res)
+		return &ZoneError{zoneName: zone, apiErrorMessage: err.Detail, err: err}
+	}
+
+	return nil
+}
+
+// TSIGAlgorithmValue is used to create an "enum" of possible TSIGKey.Algorithm values
+type TSIGAlgorithmValue string
+
+const (
+	// TSIGAlgorithmHmacMD5 TSIGKey.Algorithm value hmac-md5.sig-alg.reg.int
+	TSIGAlgorithmHmacMD5 TSIGAlgorithmValue = "hmac-md5.sig-alg.reg.int"
+	// TSIGAlgorithmHmacSHA1 TSIGKey.Algorithm value hmac-sha1
+	TSIGAlgorithmHmacSHA1 TSIGAlgorithmValue = "hmac-sha1"
+	// TSIGAlgorithmHmacSHA224 TSIGKey.Algorithm value hmac-sha224
+	TSIGAlgorithmHmacSHA224 TSIGAlgorithmValue = "hmac-sha224"
+	// TSIGAlgorithmHmacSHA256 TSIGKey.Algorithm value hmac-sha256
+	TSIGAlgorithmHmacSHA256 TSIGAlgorithmValue = "hmac-sha256"
+	// TSIGAlgorithmHmacSHA384 TSIGKey.Algorithm value hmac-sha384
+	TSIGAlgorithmHmacSHA384 TSIGAlgorithmValue = "hmac-sha384"
+	// TSIGAlgorithmHmacSHA512 TSIGKey.Algorithm value hmac-sha512
+	TSIGAlgorithmHmacSHA512 TSIGAlgorithmValue = "hmac-sha512"
+)
@@ -0,0 +1,118 @@
+package dnsv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestTSIGKey_Validate(t *testing.T) {
+	assert.NoError(t, NewTSIGKey("transfer.example.com", TSIGAlgorithmHmacSHA256, "c2VjcmV0").Validate())
+
+	assert.EqualError(t, NewTSIGKey("bad name", TSIGAlgorithmHmacSHA256, "c2VjcmV0").Validate(), `invalid TSIG key name "bad name"`)
+	assert.EqualError(t, NewTSIGKey("transfer", "hmac-sha3", "c2VjcmV0").Validate(), `invalid TSIG key algorithm "hmac-sha3"`)
+	assert.EqualError(t, NewTSIGKey("transfer", TSIGAlgorithmHmacSHA1, "not base64!").Validate(), "invalid TSIG key secret, expected base64")
+}
+
+func TestUpdateZoneTSIGKey(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Put("/config-dns/v2/zones/example.com/key").
+		JSON(`{"name": "transfer", "algorithm": "hmac-sha256", "secret": "c2VjcmV0"}`).
+		HeaderPresent("Authorization").
+		Reply(204)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones/example.com/key").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"name": "transfer", "algorithm": "hmac-sha256", "secret": "c2VjcmV0", "zonesCount": 2}`)
+
+	Init(config)
+
+	err := UpdateZoneTSIGKey("example.com", NewTSIGKey("transfer", TSIGAlgorithmHmacSHA256, "c2VjcmV0"))
+	assert.NoError(t, err)
+
+	key, err := GetZoneTSIGKey("example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, TSIGAlgorithmHmacSHA256, key.Algorithm)
+	assert.Equal(t, 2, key.ZonesCount)
+
+	err = UpdateZoneTSIGKey("example.com", NewTSIGKey("transfer", "md5", "c2VjcmV0"))
+	assert.True(t, err.(ConfigDNSError).ValidationFailed())
+	assert.True(t, gock.IsDone())
+}
+
+func TestListTSIGKeys(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/keys").
+		MatchParam("contractIds", "C-1,C-2").
+		MatchParam("search", "transfer").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"keys": [{"name": "transfer", "algorithm": "hmac-sha256", "zonesCount": 3}]}`)
+
+	Init(config)
+
+	keys, err := ListTSIGKeys(TSIGQueryString{ContractIds: []string{"C-1", "C-2"}, Search: "transfer"})
+
+	assert.NoError(t, err)
+	if assert.Len(t, keys.Keys, 1) {
+		assert.Equal(t, "transfer", keys.Keys[0].Name)
+		assert.Equal(t, 3, keys.Keys[0].ZonesCount)
+	}
+}
+
+func TestUpdateTSIGKeyBulk(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/keys/bulk-update").
+		JSON(`{"key": {"name": "transfer", "algorithm": "hmac-sha512", "secret": "bmV3"}, "zones": ["example.com", "example.net"]}`).
+		HeaderPresent("Authorization").
+		Reply(400).
+		SetHeader("Content-Type", "application/problem+json").
+		BodyString(`{"title": "Bad Request", "detail": "example.net is not a secondary zone", "status": 400}`)
+
+	Init(config)
+
+	err := UpdateTSIGKeyBulk(NewTSIGKey("transfer", TSIGAlgorithmHmacSHA512, "bmV3"), []string{"example.com", "example.net"})
+
+	assert.EqualError(t, err, `Zone "example.com, example.net" validation failed: [example.net is not a secondary zone]`)
+}
+
+func TestGetZoneTransferErrors(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/zones/zone-transfer-status").
+		JSON(`{"zones": ["example.com", "example.net"]}`).
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"zones": [
+			{"zone": "example.com", "masterServer": "192.0.2.53", "lastRefreshStatus": "SUCCESS", "lastRefreshTime": "2019-05-01T10:00:00Z"},
+			{"zone": "example.net", "masterServer": "192.0.2.54", "lastRefreshStatus": "FAILED", "lastRefreshError": "TSIG verification failed"}
+		]}`)
+
+	Init(config)
+
+	failed, err := GetZoneTransferErrors("example.com", "example.net")
+
+	assert.NoError(t, err)
+	if assert.Len(t, failed, 1) {
+		assert.Equal(t, "example.net", failed[0].Zone)
+		assert.Equal(t, "TSIG verification failed", failed[0].LastRefreshError)
+	}
+}
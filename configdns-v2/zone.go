@@ -42,25 +42,29 @@ type ZoneQueryString struct {
 }
 
 type ZoneCreate struct {
-	Zone         string   `json:"zone,omitempty"`
-	Type         string   `json:"type,omitempty"`
-	Masters      []string `json:"masters,omitempty"`
-	Comment      string   `json:"comment,omitempty"`
-	SignAndServe bool     `json:"signAndServe"`
+	Zone                  string                     `json:"zone,omitempty"`
+	Type                  string                     `json:"type,omitempty"`
+	Masters               []string                   `json:"masters,omitempty"`
+	Comment               string                     `json:"comment,omitempty"`
+	SignAndServe          bool                       `json:"signAndServe"`
+	SignAndServeAlgorithm SignAndServeAlgorithmValue `json:"signAndServeAlgorithm,omitempty"`
+	TsigKey               *TSIGKey                   `json:"tsigKey,omitempty"`
 }
 
 type ZoneResponse struct {
-	Zone               string   `json:"zone,omitempty"`
-	Type               string   `json:"type,omitempty"`
-	Masters            []string `json:"masters,omitempty"`
-	Comment            string   `json:"comment,omitempty"`
-	ActivationState    string   `json:"activationstate,omitempty"`
-	ContractId         string   `json:"contractid,omitempty"`
-	LastActivationDate string   `json:"lastactivationdate,omitempty"`
-	LastModifiedBy     string   `json:"lastmodifiedby,omitempty"`
-	LastModifiedDate   string   `json:"lastmodifieddate,omitempty"`
-	SignAndServe       bool     `json:"signandserve"`
-	VersionId          string   `json:"versionid,omitempty"`
+	Zone                  string                     `json:"zone,omitempty"`
+	Type                  string                     `json:"type,omitempty"`
+	Masters               []string                   `json:"masters,omitempty"`
+	Comment               string                     `json:"comment,omitempty"`
+	ActivationState       string                     `json:"activationstate,omitempty"`
+	ContractId            string                     `json:"contractid,omitempty"`
+	LastActivationDate    string                     `json:"lastactivationdate,omitempty"`
+	LastModifiedBy        string                     `json:"lastmodifiedby,omitempty"`
+	LastModifiedDate      string                     `json:"lastmodifieddate,omitempty"`
+	SignAndServe          bool                       `json:"signandserve"`
+	SignAndServeAlgorithm SignAndServeAlgorithmValue `json:"signAndServeAlgorithm,omitempty"`
+	VersionId             string                     `json:"versionid,omitempty"`
+	TsigKey               *TSIGKey                   `json:"tsigKey,omitempty"`
 }
 
 type ChangeListResponse struct {
@@ -73,7 +77,7 @@ type ChangeListResponse struct {
 
 // NewZone creates a new Zone
 func NewZone(params ZoneCreate) *ZoneCreate {
-	zone := &ZoneCreate{Zone: params.Zone, Type: params.Type, Masters: params.Masters, Comment: params.Comment, SignAndServe: params.SignAndServe}
+	zone := &ZoneCreate{Zone: params.Zone, Type: params.Type, Masters: params.Masters, Comment: params.Comment, SignAndServe: params.SignAndServe, SignAndServeAlgorithm: params.SignAndServeAlgorithm, TsigKey: params.TsigKey}
 	return zone
 }
 
@@ -0,0 +1,255 @@
+package dnsv2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// BulkZonesCreate is a batch of zones to create
+type BulkZonesCreate struct {
+	Zones []*ZoneCreate `json:"zones"`
+}
+
+// BulkZonesResponse is a submitted bulk zone request
+type BulkZonesResponse struct {
+	RequestId      string `json:"requestId"`
+	ExpirationDate string `json:"expirationDate,omitempty"`
+}
+
+// BulkStatusResponse is the progress of a bulk zone request
+type BulkStatusResponse struct {
+	RequestId      string `json:"requestId"`
+	ZonesSubmitted int    `json:"zonesSubmitted"`
+	SuccessCount   int    `json:"successCount"`
+	FailureCount   int    `json:"failureCount"`
+	IsComplete     bool   `json:"isComplete"`
+	ExpirationDate string `json:"expirationDate,omitempty"`
+}
+
+// BulkFailedZone is a zone that a bulk request failed to create or delete
+type BulkFailedZone struct {
+	Zone          string `json:"zone"`
+	FailureReason string `json:"failureReason"`
+}
+
+// BulkCreateResultResponse is the per-zone result of a bulk create request
+type BulkCreateResultResponse struct {
+	RequestId                string            `json:"requestId"`
+	SuccessfullyCreatedZones []string          `json:"successfullyCreatedZones"`
+	FailedZones              []*BulkFailedZone `json:"failedZones"`
+}
+
+// BulkDeleteResultResponse is the per-zone result of a bulk delete request
+type BulkDeleteResultResponse struct {
+	RequestId                string            `json:"requestId"`
+	SuccessfullyDeletedZones []string          `json:"successfullyDeletedZones"`
+	FailedZones              []*BulkFailedZone `json:"failedZones"`
+}
+
+// BulkRequestTimeoutError is returned when a bulk request does not complete in time
+type BulkRequestTimeoutError struct {
+	RequestId string
+	Status    *BulkStatusResponse
+}
+
+func (e *BulkRequestTimeoutError) Error() string {
+	return fmt.Sprintf("bulk zone request %s incomplete: %d of %d zones processed", e.RequestId, e.Status.SuccessCount+e.Status.FailureCount, e.Status.ZonesSubmitted)
+}
+
+// CreateBulkZones submits a request to create many zones in a contract and group
+//
+// Zones are created asynchronously, see GetBulkZoneCreateStatus() and
+// GetBulkZoneCreateResult().
+//
+// Endpoint: POST /config-dns/v2/zones/create-requests{?contractId,gid}
+func CreateBulkZones(zones *BulkZonesCreate, zonequerystring ZoneQueryString) (*BulkZonesResponse, error) {
+	return submitBulkZones(
+		"/config-dns/v2/zones/create-requests?contractId="+zonequerystring.Contract+"&gid="+zonequerystring.Group,
+		zones,
+	)
+}
+
+// DeleteBulkZones submits a request to delete many zones
+//
+// Unless bypassSafetyChecks is true, zones that are delegated to Edge DNS
+// are not deleted.
+//
+// Endpoint: POST /config-dns/v2/zones/delete-requests{?bypassSafetyChecks}
+func DeleteBulkZones(zones *ZoneNames, bypassSafetyChecks bool) (*BulkZonesResponse, error) {
+	return submitBulkZones(
+		"/config-dns/v2/zones/delete-requests?bypassSafetyChecks="+strconv.FormatBool(bypassSafetyChecks),
+		zones,
+	)
+}
+
+// GetBulkZoneCreateStatus retrieves the progress of a bulk create request
+//
+// Endpoint: GET /config-dns/v2/zones/create-requests/{requestId}
+func GetBulkZoneCreateStatus(requestId string) (*BulkStatusResponse, error) {
+	status := &BulkStatusResponse{}
+	if err := getBulkZones("/config-dns/v2/zones/create-requests/"+requestId, status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// GetBulkZoneDeleteStatus retrieves the progress of a bulk delete request
+//
+// Endpoint: GET /config-dns/v2/zones/delete-requests/{requestId}
+func GetBulkZoneDeleteStatus(requestId string) (*BulkStatusResponse, error) {
+	status := &BulkStatusResponse{}
+	if err := getBulkZones("/config-dns/v2/zones/delete-requests/"+requestId, status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// GetBulkZoneCreateResult retrieves the zones created and failed by a bulk create request
+//
+// Endpoint: GET /config-dns/v2/zones/create-requests/{requestId}/result
+func GetBulkZoneCreateResult(requestId string) (*BulkCreateResultResponse, error) {
+	result := &BulkCreateResultResponse{}
+	if err := getBulkZones("/config-dns/v2/zones/create-requests/"+requestId+"/result", result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetBulkZoneDeleteResult retrieves the zones deleted and failed by a bulk delete request
+//
+// Endpoint: GET /config-dns/v2/zones/delete-requests/{requestId}/result
+func GetBulkZoneDeleteResult(requestId string) (*BulkDeleteResultResponse, error) {
+	result := &BulkDeleteResultResponse{}
+	if err := getBulkZones("/config-dns/v2/zones/delete-requests/"+requestId+"/result", result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// WaitForBulkZoneCreate polls a bulk create request every interval until it
+// completes, returning a *BulkRequestTimeoutError after timeout
+func WaitForBulkZoneCreate(requestId string, interval time.Duration, timeout time.Duration) (*BulkStatusResponse, error) {
+	return waitForBulkZones(requestId, GetBulkZoneCreateStatus, interval, timeout)
+}
+
+// WaitForBulkZoneDelete polls a bulk delete request every interval until it
+// completes, returning a *BulkRequestTimeoutError after timeout
+func WaitForBulkZoneDelete(requestId string, interval time.Duration, timeout time.Duration) (*BulkStatusResponse, error) {
+	return waitForBulkZones(requestId, GetBulkZoneDeleteStatus, interval, timeout)
+}
+
+// RetryFailedBulkZoneCreate resubmits the zones of a bulk create request that failed
+//
+// zones is the original batch; only the zones listed in result.FailedZones
+// are submitted again. Zone names are matched case-insensitively, ignoring
+// any trailing dot. nil is returned if no zone failed.
+func RetryFailedBulkZoneCreate(zones *BulkZonesCreate, result *BulkCreateResultResponse, zonequerystring ZoneQueryString) (*BulkZonesResponse, error) {
+	failed := map[string]bool{}
+	for _, zone := range result.FailedZones {
+		failed[bulkZoneKey(zone.Zone)] = true
+	}
+
+	retry := &BulkZonesCreate{}
+	for _, zone := range zones.Zones {
+		if failed[bulkZoneKey(zone.Zone)] {
+			retry.Zones = append(retry.Zones, zone)
+		}
+	}
+
+	if len(retry.Zones) == 0 {
+		return nil, nil
+	}
+
+	return CreateBulkZones(retry, zonequerystring)
+}
+
+// RetryFailedBulkZoneDelete resubmits the zones of a bulk delete request that failed
+//
+// nil is returned if no zone failed.
+func RetryFailedBulkZoneDelete(result *BulkDeleteResultResponse, bypassSafetyChecks bool) (*BulkZonesResponse, error) {
+	retry := &ZoneNames{}
+	for _, zone := range result.FailedZones {
+		retry.Zones = append(retry.Zones, zone.Zone)
+	}
+
+	if len(retry.Zones) == 0 {
+		return nil, nil
+	}
+
+	return DeleteBulkZones(retry, bypassSafetyChecks)
+}
+
+func bulkZoneKey(zone string) string {
+	return strings.ToLower(strings.TrimSuffix(zone, "."))
+}
+
+func submitBulkZones(path string, body interface{}) (*BulkZonesResponse, error) {
+	submitted := &BulkZonesResponse{}
+
+	req, err := client.NewJSONRequest(Config, "POST", path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	if err = client.BodyJSON(res, submitted); err != nil {
+		return nil, err
+	}
+
+	return submitted, nil
+}
+
+func getBulkZones(path string, response interface{}) error {
+	req, err := client.NewRequest(Config, "GET", path, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	return client.BodyJSON(res, response)
+}
+
+func waitForBulkZones(requestId string, getStatus func(string) (*BulkStatusResponse, error), interval time.Duration, timeout time.Duration) (*BulkStatusResponse, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		status, err := getStatus(requestId)
+		if err != nil {
+			return nil, err
+		}
+
+		if status.IsComplete {
+			return status, nil
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return status, &BulkRequestTimeoutError{RequestId: requestId, Status: status}
+		}
+
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,147 @@
+package dnsv2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestCreateBulkZones(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/zones/create-requests").
+		MatchParam("contractId", "C-1").
+		MatchParam("gid", "1234").
+		JSON(`{"zones": [{"zone": "example.com", "type": "PRIMARY", "signAndServe": false}, {"zone": "example.net", "type": "SECONDARY", "masters": ["192.0.2.53"], "signAndServe": false}]}`).
+		HeaderPresent("Authorization").
+		Reply(202).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"requestId": "req-1", "expirationDate": "2019-06-01T00:00:00Z"}`)
+
+	Init(config)
+
+	submitted, err := CreateBulkZones(&BulkZonesCreate{Zones: []*ZoneCreate{
+		{Zone: "example.com", Type: "PRIMARY"},
+		{Zone: "example.net", Type: "SECONDARY", Masters: []string{"192.0.2.53"}},
+	}}, ZoneQueryString{Contract: "C-1", Group: "1234"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "req-1", submitted.RequestId)
+}
+
+func TestWaitForBulkZoneCreate(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones/create-requests/req-1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"requestId": "req-1", "zonesSubmitted": 3, "successCount": 1, "failureCount": 0, "isComplete": false}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones/create-requests/req-1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"requestId": "req-1", "zonesSubmitted": 3, "successCount": 2, "failureCount": 1, "isComplete": true}`)
+
+	Init(config)
+
+	status, err := WaitForBulkZoneCreate("req-1", time.Millisecond, time.Minute)
+
+	assert.NoError(t, err)
+	assert.True(t, status.IsComplete)
+	assert.Equal(t, 1, status.FailureCount)
+	assert.True(t, gock.IsDone())
+}
+
+func TestWaitForBulkZoneCreate_Timeout(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones/create-requests/req-1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"requestId": "req-1", "zonesSubmitted": 3, "successCount": 1, "failureCount": 0, "isComplete": false}`)
+
+	Init(config)
+
+	_, err := WaitForBulkZoneCreate("req-1", time.Minute, time.Second)
+
+	assert.IsType(t, &BulkRequestTimeoutError{}, err)
+	assert.EqualError(t, err, "bulk zone request req-1 incomplete: 1 of 3 zones processed")
+}
+
+func TestRetryFailedBulkZoneCreate(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones/create-requests/req-1/result").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"requestId": "req-1", "successfullyCreatedZones": ["example.com", "example.org"], "failedZones": [{"zone": "example.net", "failureReason": "ZONE_ALREADY_EXISTS"}]}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/zones/create-requests").
+		MatchParam("contractId", "C-1").
+		JSON(`{"zones": [{"zone": "Example.NET.", "type": "PRIMARY", "signAndServe": false}]}`).
+		HeaderPresent("Authorization").
+		Reply(202).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"requestId": "req-2"}`)
+
+	Init(config)
+
+	zones := &BulkZonesCreate{Zones: []*ZoneCreate{
+		{Zone: "example.com", Type: "PRIMARY"},
+		{Zone: "Example.NET.", Type: "PRIMARY"},
+		{Zone: "example.org", Type: "PRIMARY"},
+	}}
+
+	result, err := GetBulkZoneCreateResult("req-1")
+	assert.NoError(t, err)
+	if assert.Len(t, result.FailedZones, 1) {
+		assert.Equal(t, "ZONE_ALREADY_EXISTS", result.FailedZones[0].FailureReason)
+	}
+
+	submitted, err := RetryFailedBulkZoneCreate(zones, result, ZoneQueryString{Contract: "C-1", Group: "1234"})
+	assert.NoError(t, err)
+	assert.Equal(t, "req-2", submitted.RequestId)
+
+	submitted, err = RetryFailedBulkZoneCreate(zones, &BulkCreateResultResponse{RequestId: "req-2"}, ZoneQueryString{Contract: "C-1", Group: "1234"})
+	assert.NoError(t, err)
+	assert.Nil(t, submitted)
+}
+
+func TestDeleteBulkZones(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/zones/delete-requests").
+		MatchParam("bypassSafetyChecks", "false").
+		JSON(`{"zones": ["example.com", "example.net"]}`).
+		HeaderPresent("Authorization").
+		Reply(202).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"requestId": "del-1"}`)
+
+	Init(config)
+
+	submitted, err := DeleteBulkZones(&ZoneNames{Zones: []string{"example.com", "example.net"}}, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "del-1", submitted.RequestId)
+}
@@ -0,0 +1,167 @@
+package dnsv2
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// DefaultZoneListPageSize is the page size used by ListAllZones() when none is given
+const DefaultZoneListPageSize = 100
+
+// ZoneListQueryString filters and orders the zones returned by ListZones()
+type ZoneListQueryString struct {
+	ContractIds []string
+	Types       []ZoneTypeValue
+	// Search matches zone names containing the text
+	Search string
+	// SortBy are zone fields, prefixed with "-" for descending order
+	SortBy   []string
+	Page     int
+	PageSize int
+	ShowAll  bool
+}
+
+// ZoneListMetadata describes a page of zones
+type ZoneListMetadata struct {
+	ContractIds   []string `json:"contractIds,omitempty"`
+	Page          int      `json:"page"`
+	PageSize      int      `json:"pageSize"`
+	ShowAll       bool     `json:"showAll"`
+	TotalElements int      `json:"totalElements"`
+}
+
+// ZoneListResponse is a page of zones
+type ZoneListResponse struct {
+	Metadata ZoneListMetadata `json:"metadata"`
+	Zones    []*ZoneResponse  `json:"zones"`
+}
+
+// ListZones retrieves a page of the zones matching a query
+//
+// Endpoint: GET /config-dns/v2/zones{?contractIds,types,search,sortBy,page,pageSize,showAll}
+func ListZones(query ZoneListQueryString) (*ZoneListResponse, error) {
+	zones := &ZoneListResponse{}
+
+	values := url.Values{}
+	if len(query.ContractIds) != 0 {
+		values.Set("contractIds", strings.Join(query.ContractIds, ","))
+	}
+	if len(query.Types) != 0 {
+		types := make([]string, len(query.Types))
+		for i, zoneType := range query.Types {
+			types[i] = string(zoneType)
+		}
+		values.Set("types", strings.Join(types, ","))
+	}
+	if query.Search != "" {
+		values.Set("search", query.Search)
+	}
+	if len(query.SortBy) != 0 {
+		values.Set("sortBy", strings.Join(query.SortBy, ","))
+	}
+	if query.Page != 0 {
+		values.Set("page", strconv.Itoa(query.Page))
+	}
+	if query.PageSize != 0 {
+		values.Set("pageSize", strconv.Itoa(query.PageSize))
+	}
+	if query.ShowAll {
+		values.Set("showAll", "true")
+	}
+
+	path := "/config-dns/v2/zones"
+	if len(values) != 0 {
+		path += "?" + values.Encode()
+	}
+
+	req, err := client.NewRequest(Config, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	if err = client.BodyJSON(res, zones); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+// ListAllZones retrieves every zone matching a query, one page at a time
+//
+// query.Page is ignored; query.PageSize defaults to DefaultZoneListPageSize.
+func ListAllZones(query ZoneListQueryString) ([]*ZoneResponse, error) {
+	if query.PageSize == 0 {
+		query.PageSize = DefaultZoneListPageSize
+	}
+	query.ShowAll = false
+
+	var zones []*ZoneResponse
+	for query.Page = 1; ; query.Page++ {
+		page, err := ListZones(query)
+		if err != nil {
+			return nil, err
+		}
+
+		zones = append(zones, page.Zones...)
+		if len(page.Zones) == 0 || len(zones) >= page.Metadata.TotalElements {
+			return zones, nil
+		}
+	}
+}
+
+// CountZonesByContract counts the zones matching a query in each contract
+func CountZonesByContract(query ZoneListQueryString) (map[string]int, error) {
+	zones, err := ListAllZones(query)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, zone := range zones {
+		counts[zone.ContractId]++
+	}
+
+	return counts, nil
+}
+
+// FindPendingActivationZones retrieves the zones matching a query with
+// changes that are not yet active
+func FindPendingActivationZones(query ZoneListQueryString) ([]*ZoneResponse, error) {
+	zones, err := ListAllZones(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []*ZoneResponse
+	for _, zone := range zones {
+		if strings.EqualFold(zone.ActivationState, "PENDING") {
+			pending = append(pending, zone)
+		}
+	}
+
+	return pending, nil
+}
+
+// ZoneTypeValue is used to create an "enum" of possible ZoneListQueryString.Types values
+type ZoneTypeValue string
+
+const (
+	// ZoneTypePrimary ZoneListQueryString.Types value PRIMARY
+	ZoneTypePrimary ZoneTypeValue = "PRIMARY"
+	// ZoneTypeSecondary ZoneListQueryString.Types value SECONDARY
+	ZoneTypeSecondary ZoneTypeValue = "SECONDARY"
+	// ZoneTypeAlias ZoneListQueryString.Types value ALIAS
+	ZoneTypeAlias ZoneTypeValue = "ALIAS"
+)
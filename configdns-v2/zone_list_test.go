@@ -0,0 +1,123 @@
+package dnsv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestListZones(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones").
+		MatchParam("contractIds", "C-1").
+		MatchParam("types", "PRIMARY,ALIAS").
+		MatchParam("search", "example").
+		MatchParam("sortBy", "-zone").
+		MatchParam("page", "2").
+		MatchParam("pageSize", "1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"metadata": {"contractIds": ["C-1"], "page": 2, "pageSize": 1, "showAll": false, "totalElements": 3},
+			"zones": [{"zone": "example.net", "type": "PRIMARY", "contractId": "C-1", "activationState": "ACTIVE", "versionId": "v2"}]
+		}`)
+
+	Init(config)
+
+	zones, err := ListZones(ZoneListQueryString{
+		ContractIds: []string{"C-1"},
+		Types:       []ZoneTypeValue{ZoneTypePrimary, ZoneTypeAlias},
+		Search:      "example",
+		SortBy:      []string{"-zone"},
+		Page:        2,
+		PageSize:    1,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, zones.Metadata.TotalElements)
+	if assert.Len(t, zones.Zones, 1) {
+		assert.Equal(t, "example.net", zones.Zones[0].Zone)
+		assert.Equal(t, "ACTIVE", zones.Zones[0].ActivationState)
+		assert.Equal(t, "v2", zones.Zones[0].VersionId)
+	}
+}
+
+func TestFindPendingActivationZones(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones").
+		MatchParam("page", "1").
+		MatchParam("pageSize", "2").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"metadata": {"page": 1, "pageSize": 2, "totalElements": 3},
+			"zones": [
+				{"zone": "example.com", "type": "PRIMARY", "contractId": "C-1", "activationState": "ACTIVE"},
+				{"zone": "example.net", "type": "PRIMARY", "contractId": "C-1", "activationState": "PENDING"}
+			]
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones").
+		MatchParam("page", "2").
+		MatchParam("pageSize", "2").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"metadata": {"page": 2, "pageSize": 2, "totalElements": 3},
+			"zones": [
+				{"zone": "example.org", "type": "SECONDARY", "contractId": "C-2", "activationState": "PENDING"}
+			]
+		}`)
+
+	Init(config)
+
+	pending, err := FindPendingActivationZones(ZoneListQueryString{PageSize: 2})
+
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 2) {
+		assert.Equal(t, "example.net", pending[0].Zone)
+		assert.Equal(t, "example.org", pending[1].Zone)
+	}
+	assert.True(t, gock.IsDone())
+}
+
+func TestCountZonesByContract(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones").
+		MatchParam("types", "SECONDARY").
+		MatchParam("page", "1").
+		MatchParam("pageSize", "100").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"metadata": {"page": 1, "pageSize": 100, "totalElements": 3},
+			"zones": [
+				{"zone": "example.com", "type": "SECONDARY", "contractId": "C-1"},
+				{"zone": "example.net", "type": "SECONDARY", "contractId": "C-2"},
+				{"zone": "example.org", "type": "SECONDARY", "contractId": "C-1"}
+			]
+		}`)
+
+	Init(config)
+
+	counts, err := CountZonesByContract(ZoneListQueryString{Types: []ZoneTypeValue{ZoneTypeSecondary}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"C-1": 2, "C-2": 1}, counts)
+}
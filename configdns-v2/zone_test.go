@@ -15,7 +15,7 @@ func TestZone_JSON(t *testing.T) {
     "signAndServe": false
 }`)
 
-	zonecreate := ZoneCreate{"example.com", "PRIMARY", []string{""}, "This is a test zone", false}
+	zonecreate := ZoneCreate{"example.com", "PRIMARY", []string{""}, "This is a test zone", false, "", nil}
 	zone := NewZone(zonecreate)
 	err := jsonhooks.Unmarshal(responseBody, zone)
 	assert.NoError(t, err)
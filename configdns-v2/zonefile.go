@@ -0,0 +1,417 @@
+package dnsv2
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// zoneFileEntry is a logical line of a master zone file, with parentheses joined
+type zoneFileEntry struct {
+	line          int
+	ownerOmitted  bool
+	tokens        []string
+	quotedIndexes map[int]bool
+}
+
+// rdataNameFields lists the rdata fields of each record type that are domain
+// names, and are made absolute when a zone file is parsed
+var rdataNameFields = map[string][]int{
+	"AFSDB": {1},
+	"CNAME": {0},
+	"DNAME": {0},
+	"MX":    {1},
+	"NAPTR": {5},
+	"NS":    {0},
+	"PTR":   {0},
+	"RP":    {0, 1},
+	"SOA":   {0, 1},
+	"SRV":   {3},
+	"SVCB":  {1},
+	"HTTPS": {1},
+}
+
+// ParseZoneFile parses an RFC 1035 master zone file into recordsets
+//
+// $ORIGIN and $TTL directives, relative and omitted owner names, records
+// split over several lines with parentheses, comments and quoted strings are
+// supported. origin is the initial origin, normally the zone name. Records of
+// the same name and type are merged into a single Recordset using the TTL of
+// the first record. Names are returned without a trailing dot, as used by the
+// recordsets API, while names within rdata are absolute.
+func ParseZoneFile(zoneFile string, origin string) ([]Recordset, error) {
+	entries, err := splitZoneFile(zoneFile)
+	if err != nil {
+		return nil, err
+	}
+
+	origin = absoluteZoneName(origin, ".")
+	defaultTTL := -1
+	lastTTL := -1
+	lastOwner := ""
+
+	var recordsets []Recordset
+	index := map[string]int{}
+
+	for _, entry := range entries {
+		tokens := entry.tokens
+
+		if strings.HasPrefix(tokens[0], "$") && !entry.ownerOmitted {
+			switch strings.ToUpper(tokens[0]) {
+			case "$ORIGIN":
+				if len(tokens) != 2 {
+					return nil, fmt.Errorf("line %d: $ORIGIN requires a domain name", entry.line)
+				}
+				origin = absoluteZoneName(tokens[1], origin)
+			case "$TTL":
+				if len(tokens) != 2 {
+					return nil, fmt.Errorf("line %d: $TTL requires a TTL", entry.line)
+				}
+				ttl, err := ParseTTL(tokens[1])
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %s", entry.line, err)
+				}
+				defaultTTL = ttl
+			default:
+				return nil, fmt.Errorf("line %d: unsupported directive %s", entry.line, tokens[0])
+			}
+			continue
+		}
+
+		owner := lastOwner
+		position := 0
+		if !entry.ownerOmitted {
+			owner = absoluteZoneName(tokens[0], origin)
+			position = 1
+		}
+		if owner == "" {
+			return nil, fmt.Errorf("line %d: no owner name", entry.line)
+		}
+		lastOwner = owner
+
+		ttl := -1
+		for i := 0; i < 2 && position < len(tokens); i++ {
+			if isZoneFileClass(tokens[position]) {
+				position++
+				continue
+			}
+
+			parsed, err := ParseTTL(tokens[position])
+			if err != nil {
+				break
+			}
+			ttl = parsed
+			position++
+		}
+
+		if position >= len(tokens) {
+			return nil, fmt.Errorf("line %d: no record type", entry.line)
+		}
+
+		recordType := strings.ToUpper(tokens[position])
+		position++
+
+		rdataStart := position
+		rdata := append([]string{}, tokens[position:]...)
+		if len(rdata) == 0 {
+			return nil, fmt.Errorf("line %d: no rdata for %s record", entry.line, recordType)
+		}
+
+		for _, field := range rdataNameFields[recordType] {
+			if field < len(rdata) && !entry.quotedIndexes[rdataStart+field] {
+				rdata[field] = absoluteZoneName(rdata[field], origin) + "."
+			}
+		}
+
+		switch {
+		case ttl >= 0:
+			lastTTL = ttl
+		case defaultTTL >= 0:
+			ttl = defaultTTL
+		case lastTTL >= 0:
+			ttl = lastTTL
+		case recordType == "SOA" && len(rdata) == 7:
+			ttl, _ = ParseTTL(rdata[6])
+			lastTTL = ttl
+		default:
+			return nil, fmt.Errorf("line %d: no TTL for %s record and no $TTL", entry.line, recordType)
+		}
+
+		key := strings.ToLower(owner) + " " + recordType
+		if i, ok := index[key]; ok {
+			recordsets[i].Rdata = append(recordsets[i].Rdata, strings.Join(rdata, " "))
+			continue
+		}
+
+		index[key] = len(recordsets)
+		recordsets = append(recordsets, Recordset{
+			Name:  owner,
+			Type:  recordType,
+			TTL:   ttl,
+			Rdata: []string{strings.Join(rdata, " ")},
+		})
+	}
+
+	return recordsets, nil
+}
+
+// FormatZoneFile serializes recordsets as an RFC 1035 master zone file
+//
+// Owner names within origin are written relative to it, the SOA and NS
+// records of the apex are written first, followed by the remaining records
+// sorted by name and type.
+func FormatZoneFile(origin string, recordsets []Recordset) string {
+	origin = strings.TrimSuffix(origin, ".")
+
+	sorted := append([]Recordset{}, recordsets...)
+	rank := func(recordset Recordset) int {
+		apex := strings.EqualFold(strings.TrimSuffix(recordset.Name, "."), origin)
+		switch {
+		case apex && recordset.Type == "SOA":
+			return 0
+		case apex && recordset.Type == "NS":
+			return 1
+		case apex:
+			return 2
+		}
+		return 3
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if rank(sorted[i]) != rank(sorted[j]) {
+			return rank(sorted[i]) < rank(sorted[j])
+		}
+		if !strings.EqualFold(sorted[i].Name, sorted[j].Name) {
+			return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+		}
+		return sorted[i].Type < sorted[j].Type
+	})
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "$ORIGIN %s.\n", origin)
+	for _, recordset := range sorted {
+		owner := relativeZoneName(recordset.Name, origin)
+		for _, rdata := range recordset.Rdata {
+			fmt.Fprintf(&builder, "%s\t%d\tIN\t%s\t%s\n", owner, recordset.TTL, recordset.Type, rdata)
+		}
+	}
+
+	return builder.String()
+}
+
+// ParseTTL parses a TTL in seconds, or using BIND units such as 1h30m or 1w
+func ParseTTL(ttl string) (int, error) {
+	if ttl == "" || ttl[0] < '0' || ttl[0] > '9' {
+		return 0, fmt.Errorf("invalid TTL \"%s\"", ttl)
+	}
+
+	if seconds, err := strconv.Atoi(ttl); err == nil {
+		return seconds, nil
+	}
+
+	units := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	total, value, digits := 0, 0, 0
+	for i := 0; i < len(ttl); i++ {
+		c := ttl[i]
+		if c >= '0' && c <= '9' {
+			value = value*10 + int(c-'0')
+			digits++
+			continue
+		}
+
+		unit, ok := units[c|0x20]
+		if !ok || digits == 0 {
+			return 0, fmt.Errorf("invalid TTL \"%s\"", ttl)
+		}
+		total += value * unit
+		value, digits = 0, 0
+	}
+
+	if digits != 0 {
+		return 0, fmt.Errorf("invalid TTL \"%s\"", ttl)
+	}
+
+	return total, nil
+}
+
+// UploadMasterZoneFile replaces the records of a zone with those of a master zone file
+//
+// See: FormatZoneFile()
+// API Docs: https://developer.akamai.com/api/cloud_security/edge_dns_zone_management/v2.html#postzonefile
+// Endpoint: POST /config-dns/v2/zones/{zone}/zone-file
+func UploadMasterZoneFile(zone string, zoneFile string) error {
+	req, err := client.NewRequest(
+		Config,
+		"POST",
+		"/config-dns/v2/zones/"+zone+"/zone-file",
+		strings.NewReader(zoneFile),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/dns")
+
+	res, err := client.Do(Config, req)
+
+	// Network error
+	if err != nil {
+		return &ZoneError{
+			zoneName:         zone,
+			httpErrorMessage: err.Error(),
+			err:              err,
+		}
+	}
+
+	// API error
+	if client.IsError(res) {
+		err := client.NewAPIError(res)
+		return &ZoneError{zoneName: zone, apiErrorMessage: err.Detail, err: err}
+	}
+
+	return nil
+}
+
+// splitZoneFile splits a zone file into entries, removing comments and
+// joining lines within parentheses
+func splitZoneFile(zoneFile string) ([]*zoneFileEntry, error) {
+	var entries []*zoneFileEntry
+
+	line := 1
+	depth := 0
+	var entry *zoneFileEntry
+	var token strings.Builder
+	inToken, inQuote := false, false
+
+	endToken := func() {
+		if inToken {
+			entry.tokens = append(entry.tokens, token.String())
+			token.Reset()
+			inToken = false
+		}
+	}
+	endEntry := func() {
+		endToken()
+		if entry != nil && len(entry.tokens) != 0 {
+			entries = append(entries, entry)
+		}
+		entry = nil
+	}
+	startEntry := func(ownerOmitted bool) {
+		if entry == nil {
+			entry = &zoneFileEntry{line: line, ownerOmitted: ownerOmitted, quotedIndexes: map[int]bool{}}
+		}
+	}
+
+	for i := 0; i < len(zoneFile); i++ {
+		c := zoneFile[i]
+
+		if inQuote {
+			token.WriteByte(c)
+			switch c {
+			case '\\':
+				if i+1 < len(zoneFile) {
+					i++
+					token.WriteByte(zoneFile[i])
+				}
+			case '"':
+				inQuote = false
+			case '\n':
+				line++
+			}
+			continue
+		}
+
+		switch c {
+		case '\n':
+			line++
+			endToken()
+			if depth == 0 {
+				endEntry()
+			}
+		case ' ', '\t', '\r':
+			startEntry(true)
+			endToken()
+		case ';':
+			for i+1 < len(zoneFile) && zoneFile[i+1] != '\n' {
+				i++
+			}
+		case '(':
+			startEntry(false)
+			endToken()
+			depth++
+		case ')':
+			startEntry(false)
+			endToken()
+			if depth == 0 {
+				return nil, fmt.Errorf("line %d: unbalanced parentheses", line)
+			}
+			depth--
+		case '"':
+			startEntry(false)
+			endToken()
+			entry.quotedIndexes[len(entry.tokens)] = true
+			token.WriteByte(c)
+			inToken, inQuote = true, true
+		default:
+			startEntry(false)
+			if c == '\\' && i+1 < len(zoneFile) {
+				token.WriteByte(c)
+				i++
+				c = zoneFile[i]
+			}
+			token.WriteByte(c)
+			inToken = true
+		}
+	}
+
+	if inQuote {
+		return nil, fmt.Errorf("line %d: unterminated quoted string", line)
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("line %d: unbalanced parentheses", line)
+	}
+	endEntry()
+
+	return entries, nil
+}
+
+// absoluteZoneName resolves a name relative to origin, without a trailing dot
+func absoluteZoneName(name string, origin string) string {
+	origin = strings.TrimSuffix(origin, ".")
+
+	switch {
+	case name == "@":
+		return origin
+	case strings.HasSuffix(name, "."):
+		return strings.TrimSuffix(name, ".")
+	case origin == "":
+		return name
+	}
+
+	return name + "." + origin
+}
+
+// relativeZoneName writes a name relative to origin if it is within it
+func relativeZoneName(name string, origin string) string {
+	name = strings.TrimSuffix(name, ".")
+
+	switch {
+	case strings.EqualFold(name, origin):
+		return "@"
+	case strings.HasSuffix(strings.ToLower(name), "."+strings.ToLower(origin)):
+		return name[:len(name)-len(origin)-1]
+	}
+
+	return name + "."
+}
+
+func isZoneFileClass(token string) bool {
+	switch strings.ToUpper(token) {
+	case "IN", "CH", "CS", "HS":
+		return true
+	}
+
+	return false
+}
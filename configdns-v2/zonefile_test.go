@@ -0,0 +1,85 @@
+package dnsv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestParseZoneFile(t *testing.T) {
+	zoneFile := `$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	ns1 hostmaster (
+		2019061101 ; serial
+		3600       ; refresh
+		600        ; retry
+		604800     ; expire
+		300 )      ; minimum
+	IN	NS	ns1
+	IN	NS	ns2.example.net.
+www	300	IN	A	192.0.2.1
+	IN	300	A	192.0.2.2
+mail	IN	MX	10 mx.example.net.
+@	IN	TXT	"v=spf1 include:example.net ; -all" "second string"
+$ORIGIN api.example.com.
+v1	IN	CNAME	@
+_sip._tcp	IN	SRV	10 60 5060 sip
+`
+
+	recordsets, err := ParseZoneFile(zoneFile, "example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Recordset{
+		{Name: "example.com", Type: "SOA", TTL: 3600, Rdata: []string{"ns1.example.com. hostmaster.example.com. 2019061101 3600 600 604800 300"}},
+		{Name: "example.com", Type: "NS", TTL: 3600, Rdata: []string{"ns1.example.com.", "ns2.example.net."}},
+		{Name: "www.example.com", Type: "A", TTL: 300, Rdata: []string{"192.0.2.1", "192.0.2.2"}},
+		{Name: "mail.example.com", Type: "MX", TTL: 3600, Rdata: []string{"10 mx.example.net."}},
+		{Name: "example.com", Type: "TXT", TTL: 3600, Rdata: []string{`"v=spf1 include:example.net ; -all" "second string"`}},
+		{Name: "v1.api.example.com", Type: "CNAME", TTL: 3600, Rdata: []string{"api.example.com."}},
+		{Name: "_sip._tcp.api.example.com", Type: "SRV", TTL: 3600, Rdata: []string{"10 60 5060 sip.api.example.com."}},
+	}, recordsets)
+
+	formatted := FormatZoneFile("example.com", recordsets)
+	reparsed, err := ParseZoneFile(formatted, "example.com")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, recordsets, reparsed)
+
+	_, err = ParseZoneFile("www IN A 192.0.2.1\n", "example.com")
+	assert.EqualError(t, err, "line 1: no TTL for A record and no $TTL")
+
+	_, err = ParseZoneFile("$TTL 300\nwww IN TXT \"unterminated\n", "example.com")
+	assert.EqualError(t, err, "line 3: unterminated quoted string")
+}
+
+func TestParseTTL(t *testing.T) {
+	ttl, err := ParseTTL("1h30m")
+	assert.NoError(t, err)
+	assert.Equal(t, 5400, ttl)
+
+	ttl, err = ParseTTL("86400")
+	assert.NoError(t, err)
+	assert.Equal(t, 86400, ttl)
+
+	_, err = ParseTTL("1x")
+	assert.Error(t, err)
+}
+
+func TestUploadMasterZoneFile(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/zones/example.com/zone-file").
+		MatchHeader("Content-Type", "text/dns").
+		HeaderPresent("Authorization").
+		Reply(204)
+
+	Init(config)
+
+	err := UploadMasterZoneFile("example.com", FormatZoneFile("example.com", []Recordset{
+		{Name: "www.example.com", Type: "A", TTL: 300, Rdata: []string{"192.0.2.1"}},
+	}))
+
+	assert.NoError(t, err)
+}
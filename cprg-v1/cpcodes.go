@@ -0,0 +1,219 @@
+package cprg
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// CpCode represents a CP code and its contracts, products and timezone
+//
+// Contract and group IDs are used without the "ctr_" and "grp_" prefixes
+// used by PAPI.
+//
+// API Docs: https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html#cpcode
+type CpCode struct {
+	CpcodeID         int               `json:"cpcodeId"`
+	CpcodeName       string            `json:"cpcodeName"`
+	AccountID        string            `json:"accountId,omitempty"`
+	Purgeable        bool              `json:"purgeable"`
+	Type             string            `json:"type,omitempty"`
+	DefaultTimezone  string            `json:"defaultTimezone,omitempty"`
+	OverrideTimezone *Timezone         `json:"overrideTimezone,omitempty"`
+	Contracts        []*CpCodeContract `json:"contracts"`
+	Products         []*CpCodeProduct  `json:"products"`
+	AccessGroup      *AccessGroup      `json:"accessGroup,omitempty"`
+}
+
+// CpCodeContract is a contract a CP code belongs to
+type CpCodeContract struct {
+	ContractID string `json:"contractId"`
+	Status     string `json:"status,omitempty"`
+}
+
+// CpCodeProduct is a product a CP code is used with
+type CpCodeProduct struct {
+	ProductID   string `json:"productId"`
+	ProductName string `json:"productName,omitempty"`
+}
+
+// Timezone is the reporting timezone of a CP code
+type Timezone struct {
+	TimezoneID    string `json:"timezoneId"`
+	TimezoneValue string `json:"timezoneValue,omitempty"`
+}
+
+// AccessGroup is the group controlling access to a CP code or reporting group
+type AccessGroup struct {
+	GroupID    int    `json:"groupId,omitempty"`
+	ContractID string `json:"contractId,omitempty"`
+}
+
+// ListCpCodesParams filters GetCpCodes()
+type ListCpCodesParams struct {
+	ContractID string
+	GroupID    int
+	ProductID  string
+	CpcodeName string
+}
+
+// GetCpCodes lists the CP codes of the account, optionally filtered
+//
+// API Docs: https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html#getcpcodes
+// Endpoint: GET /cprg/v1/cpcodes{?contractId,groupId,productId,cpcodeName}
+func GetCpCodes(params ListCpCodesParams) ([]*CpCode, error) {
+	query := url.Values{}
+	if params.ContractID != "" {
+		query.Set("contractId", params.ContractID)
+	}
+	if params.GroupID != 0 {
+		query.Set("groupId", strconv.Itoa(params.GroupID))
+	}
+	if params.ProductID != "" {
+		query.Set("productId", params.ProductID)
+	}
+	if params.CpcodeName != "" {
+		query.Set("cpcodeName", params.CpcodeName)
+	}
+
+	path := "/cprg/v1/cpcodes"
+	if len(query) != 0 {
+		path += "?" + query.Encode()
+	}
+
+	req, err := client.NewRequest(Config, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	cpcodes := &struct {
+		CpCodes []*CpCode `json:"cpcodes"`
+	}{}
+	if err = client.BodyJSON(res, cpcodes); err != nil {
+		return nil, err
+	}
+
+	return cpcodes.CpCodes, nil
+}
+
+// GetCpCode retrieves a CP code
+//
+// API Docs: https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html#getcpcode
+// Endpoint: GET /cprg/v1/cpcodes/{cpcodeId}
+func GetCpCode(cpcodeID int) (*CpCode, error) {
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf("/cprg/v1/cpcodes/%d", cpcodeID),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	cpcode := &CpCode{}
+	if err = client.BodyJSON(res, cpcode); err != nil {
+		return nil, err
+	}
+
+	return cpcode, nil
+}
+
+// ParseCpCodeID converts a PAPI CP code ID such as "cpc_12345" to its integer ID
+func ParseCpCodeID(cpcodeID string) (int, error) {
+	return strconv.Atoi(strings.TrimPrefix(cpcodeID, "cpc_"))
+}
+
+// Save updates the CP code
+//
+// API Docs: https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html#putcpcode
+// Endpoint: PUT /cprg/v1/cpcodes/{cpcodeId}
+func (cpcode *CpCode) Save() error {
+	req, err := client.NewJSONRequest(
+		Config,
+		"PUT",
+		fmt.Sprintf("/cprg/v1/cpcodes/%d", cpcode.CpcodeID),
+		cpcode,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	return client.BodyJSON(res, cpcode)
+}
+
+// Rename updates the name of the CP code
+func (cpcode *CpCode) Rename(name string) error {
+	cpcode.CpcodeName = name
+	return cpcode.Save()
+}
+
+// MoveContract moves the CP code from one contract to another
+func (cpcode *CpCode) MoveContract(fromContractID string, toContractID string) error {
+	found := false
+	for _, contract := range cpcode.Contracts {
+		if contract.ContractID == fromContractID {
+			contract.ContractID = toContractID
+			contract.Status = ""
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("CP code %d does not belong to contract \"%s\"", cpcode.CpcodeID, fromContractID)
+	}
+
+	if cpcode.AccessGroup != nil && cpcode.AccessGroup.ContractID == fromContractID {
+		cpcode.AccessGroup.ContractID = toContractID
+	}
+
+	return cpcode.Save()
+}
+
+// MoveProduct replaces a product of the CP code with another
+func (cpcode *CpCode) MoveProduct(fromProductID string, toProductID string) error {
+	found := false
+	for _, product := range cpcode.Products {
+		if product.ProductID == fromProductID {
+			product.ProductID = toProductID
+			product.ProductName = ""
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("CP code %d is not used with product \"%s\"", cpcode.CpcodeID, fromProductID)
+	}
+
+	return cpcode.Save()
+}
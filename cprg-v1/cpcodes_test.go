@@ -0,0 +1,109 @@
+package cprg
+
+import (
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+var (
+	config = edgegrid.Config{
+		Host:         "akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net/",
+		AccessToken:  "akab-access-token-xxx-xxxxxxxxxxxxxxxx",
+		ClientToken:  "akab-client-token-xxx-xxxxxxxxxxxxxxxx",
+		ClientSecret: "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx=",
+		MaxBody:      2048,
+		Debug:        false,
+	}
+)
+
+func TestCpCode_Rename(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/cprg/v1/cpcodes/12345").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"cpcodeId": 12345, "cpcodeName": "old", "purgeable": true, "contracts": [{"contractId": "C-1", "status": "ongoing"}], "products": [{"productId": "prd_Fresca"}]}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Put("/cprg/v1/cpcodes/12345").
+		JSON(`{"cpcodeId": 12345, "cpcodeName": "new", "purgeable": true, "contracts": [{"contractId": "C-1", "status": "ongoing"}], "products": [{"productId": "prd_Fresca"}]}`).
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"cpcodeId": 12345, "cpcodeName": "new", "accountId": "act_1", "purgeable": true, "contracts": [{"contractId": "C-1", "status": "ongoing"}], "products": [{"productId": "prd_Fresca", "productName": "Ion"}]}`)
+
+	Init(config)
+
+	cpcode, err := GetCpCode(12345)
+	assert.NoError(t, err)
+	assert.Equal(t, "old", cpcode.CpcodeName)
+
+	err = cpcode.Rename("new")
+	assert.NoError(t, err)
+	assert.Equal(t, "new", cpcode.CpcodeName)
+	assert.Equal(t, "act_1", cpcode.AccountID)
+	assert.Equal(t, "Ion", cpcode.Products[0].ProductName)
+}
+
+func TestCpCode_MoveContract(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Put("/cprg/v1/cpcodes/12345").
+		JSON(`{"cpcodeId": 12345, "cpcodeName": "media", "purgeable": false, "contracts": [{"contractId": "C-2"}], "products": [{"productId": "prd_Fresca"}], "accessGroup": {"groupId": 42, "contractId": "C-2"}}`).
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"cpcodeId": 12345, "cpcodeName": "media", "purgeable": false, "contracts": [{"contractId": "C-2", "status": "ongoing"}], "products": [{"productId": "prd_Fresca"}], "accessGroup": {"groupId": 42, "contractId": "C-2"}}`)
+
+	Init(config)
+
+	cpcode := &CpCode{
+		CpcodeID:    12345,
+		CpcodeName:  "media",
+		Contracts:   []*CpCodeContract{{ContractID: "C-1", Status: "ongoing"}},
+		Products:    []*CpCodeProduct{{ProductID: "prd_Fresca"}},
+		AccessGroup: &AccessGroup{GroupID: 42, ContractID: "C-1"},
+	}
+
+	err := cpcode.MoveContract("C-2", "C-3")
+	assert.Error(t, err)
+
+	err = cpcode.MoveContract("C-1", "C-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "C-2", cpcode.Contracts[0].ContractID)
+	assert.Equal(t, "ongoing", cpcode.Contracts[0].Status)
+	assert.Equal(t, "C-2", cpcode.AccessGroup.ContractID)
+	assert.True(t, gock.IsDone())
+}
+
+func TestReportingGroup_AddCpCode(t *testing.T) {
+	group := NewReportingGroup("Media", "C-1", 42)
+
+	group.AddCpCode("C-1", 1)
+	group.AddCpCode("C-1", 2)
+	group.AddCpCode("C-1", 1)
+	group.AddCpCode("C-2", 3)
+
+	assert.Len(t, group.Contracts, 2)
+	assert.Len(t, group.Contracts[0].CpCodes, 2)
+	assert.True(t, group.HasCpCode(3))
+
+	group.RemoveCpCode(1)
+	assert.False(t, group.HasCpCode(1))
+	assert.True(t, group.HasCpCode(2))
+}
+
+func TestParseCpCodeID(t *testing.T) {
+	id, err := ParseCpCodeID("cpc_12345")
+	assert.NoError(t, err)
+	assert.Equal(t, 12345, id)
+}
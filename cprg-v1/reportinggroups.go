@@ -0,0 +1,234 @@
+package cprg
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// ReportingGroup represents a named set of CP codes reported on together
+//
+// API Docs: https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html#reportinggroup
+type ReportingGroup struct {
+	ReportingGroupID   int                       `json:"reportingGroupId,omitempty"`
+	ReportingGroupName string                    `json:"reportingGroupName"`
+	Contracts          []*ReportingGroupContract `json:"contracts"`
+	AccessGroup        *AccessGroup              `json:"accessGroup,omitempty"`
+}
+
+// ReportingGroupContract lists the CP codes of a reporting group for a contract
+type ReportingGroupContract struct {
+	ContractID string                  `json:"contractId"`
+	CpCodes    []*ReportingGroupCpCode `json:"cpcodes"`
+}
+
+// ReportingGroupCpCode is a CP code within a reporting group
+type ReportingGroupCpCode struct {
+	CpcodeID   int    `json:"cpcodeId"`
+	CpcodeName string `json:"cpcodeName,omitempty"`
+}
+
+// ListReportingGroupsParams filters GetReportingGroups()
+type ListReportingGroupsParams struct {
+	ContractID string
+	GroupID    int
+	CpcodeID   int
+}
+
+// NewReportingGroup creates a new ReportingGroup
+func NewReportingGroup(name string, contractID string, groupID int) *ReportingGroup {
+	return &ReportingGroup{
+		ReportingGroupName: name,
+		Contracts:          []*ReportingGroupContract{},
+		AccessGroup:        &AccessGroup{GroupID: groupID, ContractID: contractID},
+	}
+}
+
+// GetReportingGroups lists the reporting groups of the account, optionally filtered
+//
+// API Docs: https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html#getreportinggroups
+// Endpoint: GET /cprg/v1/reporting-groups{?contractId,groupId,cpcodeId}
+func GetReportingGroups(params ListReportingGroupsParams) ([]*ReportingGroup, error) {
+	query := url.Values{}
+	if params.ContractID != "" {
+		query.Set("contractId", params.ContractID)
+	}
+	if params.GroupID != 0 {
+		query.Set("groupId", strconv.Itoa(params.GroupID))
+	}
+	if params.CpcodeID != 0 {
+		query.Set("cpcodeId", strconv.Itoa(params.CpcodeID))
+	}
+
+	path := "/cprg/v1/reporting-groups"
+	if len(query) != 0 {
+		path += "?" + query.Encode()
+	}
+
+	req, err := client.NewRequest(Config, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	groups := &struct {
+		Groups []*ReportingGroup `json:"groups"`
+	}{}
+	if err = client.BodyJSON(res, groups); err != nil {
+		return nil, err
+	}
+
+	return groups.Groups, nil
+}
+
+// GetReportingGroup retrieves a reporting group
+//
+// API Docs: https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html#getreportinggroup
+// Endpoint: GET /cprg/v1/reporting-groups/{reportingGroupId}
+func GetReportingGroup(reportingGroupID int) (*ReportingGroup, error) {
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf("/cprg/v1/reporting-groups/%d", reportingGroupID),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	group := &ReportingGroup{}
+	if err = client.BodyJSON(res, group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// AddCpCode adds a CP code to the reporting group for a contract
+//
+// The change is not saved until ReportingGroup.Save() is called.
+func (group *ReportingGroup) AddCpCode(contractID string, cpcodeID int) {
+	for _, contract := range group.Contracts {
+		if contract.ContractID != contractID {
+			continue
+		}
+
+		for _, cpcode := range contract.CpCodes {
+			if cpcode.CpcodeID == cpcodeID {
+				return
+			}
+		}
+
+		contract.CpCodes = append(contract.CpCodes, &ReportingGroupCpCode{CpcodeID: cpcodeID})
+		return
+	}
+
+	group.Contracts = append(group.Contracts, &ReportingGroupContract{
+		ContractID: contractID,
+		CpCodes:    []*ReportingGroupCpCode{{CpcodeID: cpcodeID}},
+	})
+}
+
+// RemoveCpCode removes a CP code from the reporting group
+//
+// The change is not saved until ReportingGroup.Save() is called.
+func (group *ReportingGroup) RemoveCpCode(cpcodeID int) {
+	for _, contract := range group.Contracts {
+		cpcodes := contract.CpCodes[:0]
+		for _, cpcode := range contract.CpCodes {
+			if cpcode.CpcodeID != cpcodeID {
+				cpcodes = append(cpcodes, cpcode)
+			}
+		}
+		contract.CpCodes = cpcodes
+	}
+}
+
+// HasCpCode reports whether a CP code is in the reporting group
+func (group *ReportingGroup) HasCpCode(cpcodeID int) bool {
+	for _, contract := range group.Contracts {
+		for _, cpcode := range contract.CpCodes {
+			if cpcode.CpcodeID == cpcodeID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Save creates the reporting group, or updates it if it already exists
+//
+// API Docs: https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html#postreportinggroups
+// Endpoint: POST /cprg/v1/reporting-groups
+// Endpoint: PUT /cprg/v1/reporting-groups/{reportingGroupId}
+func (group *ReportingGroup) Save() error {
+	method := "POST"
+	path := "/cprg/v1/reporting-groups"
+	if group.ReportingGroupID != 0 {
+		method = "PUT"
+		path = fmt.Sprintf("/cprg/v1/reporting-groups/%d", group.ReportingGroupID)
+	}
+
+	req, err := client.NewJSONRequest(Config, method, path, group)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	return client.BodyJSON(res, group)
+}
+
+// Delete the reporting group
+//
+// API Docs: https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html#deletereportinggroup
+// Endpoint: DELETE /cprg/v1/reporting-groups/{reportingGroupId}
+func (group *ReportingGroup) Delete() error {
+	req, err := client.NewRequest(
+		Config,
+		"DELETE",
+		fmt.Sprintf("/cprg/v1/reporting-groups/%d", group.ReportingGroupID),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+// Package cprg provides a simple wrapper for the Akamai CP Codes and Reporting Groups API
+package cprg
+
+import (
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+)
+
+var (
+	// Config contains the Akamai OPEN Edgegrid API credentials
+	// for automatic signing of requests
+	Config edgegrid.Config
+)
+
+// Init sets the CPRG edgegrid Config
+func Init(config edgegrid.Config) {
+	Config = config
+}
@@ -0,0 +1,396 @@
+// Package onboarding adds a hostname to a property in a single workflow
+//
+// An edge hostname is created or reused, the hostname is attached to an
+// editable version of the property, and a CNAME to the edge hostname is
+// optionally created or validated in Config DNS.
+package onboarding
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+)
+
+// Init sets the PAPI and Config DNS edgegrid Config
+func Init(config edgegrid.Config) {
+	papi.Init(config)
+	dnsv2.Init(config)
+}
+
+// Request describes a hostname to onboard
+type Request struct {
+	// Property the hostname is added to
+	Property *papi.Property
+	// Hostname is the customer hostname, e.g. www.example.com
+	Hostname string
+	// EdgeHostnamePrefix defaults to Hostname
+	EdgeHostnamePrefix string
+	// ProductID used when creating the edge hostname, defaults to Property.ProductID
+	ProductID string
+	// Secure creates an edgekey.net edge hostname instead of edgesuite.net
+	Secure bool
+	// CertEnrollmentID is the CPS enrollment for a secure edge hostname
+	CertEnrollmentID int
+	// SecureNetwork is papi.SecureNetworkEnhancedTLS or papi.SecureNetworkStandardTLS
+	SecureNetwork string
+	// IPVersionBehavior defaults to papi.IPVersionIPv4
+	IPVersionBehavior string
+	// EdgeHostnameTimeout is how long in total to wait for a new edge hostname
+	// to become active, zero does not wait. The workflow stops if it is not
+	// active in time
+	EdgeHostnameTimeout time.Duration
+	// DNSMode determines what is done with the CNAME record
+	DNSMode DNSModeValue
+	// Zone is the Config DNS zone of the CNAME record
+	Zone string
+	// TTL of the CNAME record, defaults to 300
+	TTL int
+	// OverwriteCname allows DNSModeCreate to replace an existing CNAME record
+	// pointing to another target
+	OverwriteCname bool
+}
+
+// Result is the outcome of an onboarding workflow
+type Result struct {
+	EdgeHostname *papi.EdgeHostname
+	Version      *papi.Version
+	Hostnames    *papi.Hostnames
+	Steps        []*Step
+}
+
+// Step is the outcome of a single onboarding step
+type Step struct {
+	Name   StepName
+	Status StepStatusValue
+	Detail string
+	Err    error
+}
+
+func (result *Result) addStep(name StepName, status StepStatusValue, detail string, err error) {
+	result.Steps = append(result.Steps, &Step{Name: name, Status: status, Detail: detail, Err: err})
+}
+
+// Onboard runs the onboarding workflow
+//
+// Each step is recorded in Result.Steps. Processing stops at the first
+// failing step, and its error is returned along with the partial Result.
+func Onboard(request *Request) (*Result, error) {
+	result := &Result{}
+
+	if request.Property == nil || request.Hostname == "" {
+		return result, fmt.Errorf("a property and hostname are required")
+	}
+
+	edgeHostname, err := ensureEdgeHostname(request, result)
+	result.EdgeHostname = edgeHostname
+	if err != nil {
+		// A created edge hostname that is not active yet is recorded as timed out
+		if edgeHostname == nil {
+			result.addStep(StepEdgeHostname, StepStatusFailed, "", err)
+		}
+		return result, err
+	}
+
+	if err := attachHostname(request, result); err != nil {
+		result.addStep(StepPropertyHostname, StepStatusFailed, "", err)
+		return result, err
+	}
+
+	if err := ensureCname(request, result); err != nil {
+		result.addStep(StepDNSRecord, StepStatusFailed, "", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// EdgeHostnameDomain returns the full edge hostname for a request
+func (request *Request) EdgeHostnameDomain() string {
+	return request.edgeHostnamePrefix() + "." + request.edgeHostnameSuffix()
+}
+
+func (request *Request) edgeHostnamePrefix() string {
+	if request.EdgeHostnamePrefix != "" {
+		return request.EdgeHostnamePrefix
+	}
+
+	return request.Hostname
+}
+
+func (request *Request) edgeHostnameSuffix() string {
+	if request.Secure {
+		return "edgekey.net"
+	}
+
+	return "edgesuite.net"
+}
+
+func ensureEdgeHostname(request *Request, result *Result) (*papi.EdgeHostname, error) {
+	property := request.Property
+	edgeHostnames, err := papi.GetEdgeHostnames(property.Contract, property.Group, "")
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := papi.NewEdgeHostname(edgeHostnames)
+	wanted.DomainPrefix = request.edgeHostnamePrefix()
+	wanted.DomainSuffix = request.edgeHostnameSuffix()
+
+	if existing, err := edgeHostnames.FindEdgeHostname(wanted); err == nil && existing != nil {
+		result.addStep(StepEdgeHostname, StepStatusReused, existing.EdgeHostnameID, nil)
+		return existing, nil
+	}
+
+	edgeHostname := edgeHostnames.NewEdgeHostname()
+	edgeHostname.DomainPrefix = wanted.DomainPrefix
+	edgeHostname.DomainSuffix = wanted.DomainSuffix
+	edgeHostname.ProductID = request.ProductID
+	if edgeHostname.ProductID == "" {
+		edgeHostname.ProductID = property.ProductID
+	}
+	edgeHostname.Secure = request.Secure
+	edgeHostname.SecureNetwork = request.SecureNetwork
+	edgeHostname.CertEnrollmentId = request.CertEnrollmentID
+	edgeHostname.IPVersionBehavior = request.IPVersionBehavior
+	if edgeHostname.IPVersionBehavior == "" {
+		edgeHostname.IPVersionBehavior = papi.IPVersionIPv4
+	}
+
+	if err := edgeHostname.Save(""); err != nil {
+		return nil, err
+	}
+
+	if request.EdgeHostnameTimeout > 0 {
+		active, err := waitForEdgeHostname(edgeHostname, request.EdgeHostnameTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve status of edge hostname %s: %s", request.EdgeHostnameDomain(), err)
+		}
+
+		if !active {
+			err := fmt.Errorf("edge hostname %s was not active after %s", request.EdgeHostnameDomain(), request.EdgeHostnameTimeout)
+			result.addStep(StepEdgeHostname, StepStatusTimedOut, edgeHostname.EdgeHostnameID, err)
+			return edgeHostname, err
+		}
+	}
+
+	result.addStep(StepEdgeHostname, StepStatusCreated, edgeHostname.EdgeHostnameID, nil)
+
+	return edgeHostname, nil
+}
+
+// edgeHostnamePollInterval is how often a new edge hostname's status is checked
+var edgeHostnamePollInterval = time.Minute
+
+// waitForEdgeHostname polls an edge hostname until it is active or timeout
+// has elapsed, reporting whether it became active
+func waitForEdgeHostname(edgeHostname *papi.EdgeHostname, timeout time.Duration) (bool, error) {
+	deadline := time.Now().Add(timeout)
+
+	for edgeHostname.Status != papi.StatusActive {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return false, nil
+		}
+
+		if remaining > edgeHostnamePollInterval {
+			remaining = edgeHostnamePollInterval
+		}
+		time.Sleep(remaining)
+
+		if err := edgeHostname.GetEdgeHostname(""); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// attachHostname adds the hostname to the latest property version, creating
+// a new version if the latest version has been activated
+func attachHostname(request *Request, result *Result) error {
+	property := request.Property
+
+	versions, err := property.GetVersions()
+	if err != nil {
+		return err
+	}
+
+	latest, err := property.GetLatestVersion("")
+	if err != nil {
+		return err
+	}
+
+	version := latest
+	if latest.ProductionStatus != papi.StatusInactive || latest.StagingStatus != papi.StatusInactive {
+		version = versions.NewVersion(latest, true)
+		if err := version.Save(); err != nil {
+			return err
+		}
+		result.addStep(StepPropertyVersion, StepStatusCreated, fmt.Sprintf("version %d", version.PropertyVersion), nil)
+	} else {
+		result.addStep(StepPropertyVersion, StepStatusReused, fmt.Sprintf("version %d", version.PropertyVersion), nil)
+	}
+	result.Version = version
+
+	hostnames, err := property.GetHostnames(version)
+	if err != nil {
+		return err
+	}
+	hostnames.PropertyVersion = version.PropertyVersion
+	result.Hostnames = hostnames
+
+	target := request.EdgeHostnameDomain()
+	status := StepStatusCreated
+
+	var hostname *papi.Hostname
+	for _, existing := range hostnames.Hostnames.Items {
+		if strings.EqualFold(existing.CnameFrom, request.Hostname) {
+			hostname = existing
+		}
+	}
+
+	if hostname == nil {
+		hostname = hostnames.NewHostname()
+		hostname.CnameFrom = request.Hostname
+	} else if hostname.CnameTo == target {
+		result.addStep(StepPropertyHostname, StepStatusReused, target, nil)
+		return nil
+	} else {
+		status = StepStatusUpdated
+	}
+
+	hostname.CnameType = papi.CnameTypeEdgeHostname
+	hostname.CnameTo = target
+	hostname.EdgeHostnameID = result.EdgeHostname.EdgeHostnameID
+
+	if err := hostnames.Save(); err != nil {
+		return err
+	}
+
+	result.addStep(StepPropertyHostname, status, target, nil)
+
+	return nil
+}
+
+// ensureCname creates or validates the CNAME record for the hostname
+func ensureCname(request *Request, result *Result) error {
+	if request.DNSMode == DNSModeSkip || request.DNSMode == "" {
+		result.addStep(StepDNSRecord, StepStatusSkipped, "", nil)
+		return nil
+	}
+
+	if request.Zone == "" {
+		return fmt.Errorf("a zone is required to manage the CNAME for %s", request.Hostname)
+	}
+
+	target := request.EdgeHostnameDomain() + "."
+
+	// A missing record is returned as empty rdata, a missing zone as an error
+	rdata, err := dnsv2.GetRdata(request.Zone, request.Hostname, "CNAME")
+	if isZoneNotFound(err) {
+		return fmt.Errorf("zone %s not found, unable to manage the CNAME for %s", request.Zone, request.Hostname)
+	} else if err != nil {
+		return err
+	}
+
+	for _, existing := range rdata {
+		if sameHostname(existing, target) {
+			result.addStep(StepDNSRecord, StepStatusValidated, target, nil)
+			return nil
+		}
+	}
+
+	if request.DNSMode == DNSModeValidate && len(rdata) == 0 {
+		return fmt.Errorf("no CNAME record found for %s", request.Hostname)
+	}
+
+	if len(rdata) != 0 && (request.DNSMode == DNSModeValidate || !request.OverwriteCname) {
+		return fmt.Errorf("CNAME record for %s points to %s, expected %s", request.Hostname, strings.Join(rdata, ", "), target)
+	}
+
+	ttl := request.TTL
+	if ttl == 0 {
+		ttl = 300
+	}
+
+	record := &dnsv2.RecordBody{
+		Name:       request.Hostname,
+		RecordType: "CNAME",
+		TTL:        ttl,
+		Target:     []string{target},
+	}
+
+	if len(rdata) == 0 {
+		if err := record.Save(request.Zone); err != nil {
+			return err
+		}
+		result.addStep(StepDNSRecord, StepStatusCreated, target, nil)
+		return nil
+	}
+
+	if err := record.Update(request.Zone); err != nil {
+		return err
+	}
+	result.addStep(StepDNSRecord, StepStatusUpdated, target, nil)
+
+	return nil
+}
+
+func isZoneNotFound(err error) bool {
+	zoneErr, ok := err.(*dnsv2.ZoneError)
+	return ok && zoneErr.NotFound()
+}
+
+// sameHostname compares two hostnames ignoring case and trailing dots
+func sameHostname(a string, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
+
+// StepName is used to create an "enum" of possible Step.Name values
+type StepName string
+
+// StepStatusValue is used to create an "enum" of possible Step.Status values
+type StepStatusValue string
+
+// DNSModeValue is used to create an "enum" of possible Request.DNSMode values
+type DNSModeValue string
+
+const (
+	// StepEdgeHostname Step.Name value for the edge hostname
+	StepEdgeHostname StepName = "EDGE_HOSTNAME"
+	// StepPropertyVersion Step.Name value for the property version
+	StepPropertyVersion StepName = "PROPERTY_VERSION"
+	// StepPropertyHostname Step.Name value for the property hostname
+	StepPropertyHostname StepName = "PROPERTY_HOSTNAME"
+	// StepDNSRecord Step.Name value for the CNAME record
+	StepDNSRecord StepName = "DNS_RECORD"
+
+	// StepStatusCreated Step.Status value CREATED
+	StepStatusCreated StepStatusValue = "CREATED"
+	// StepStatusReused Step.Status value REUSED
+	StepStatusReused StepStatusValue = "REUSED"
+	// StepStatusUpdated Step.Status value UPDATED
+	StepStatusUpdated StepStatusValue = "UPDATED"
+	// StepStatusValidated Step.Status value VALIDATED
+	StepStatusValidated StepStatusValue = "VALIDATED"
+	// StepStatusTimedOut Step.Status value TIMED_OUT, the edge hostname was
+	// created but was not active within Request.EdgeHostnameTimeout, and the
+	// workflow stopped
+	StepStatusTimedOut StepStatusValue = "TIMED_OUT"
+	// StepStatusSkipped Step.Status value SKIPPED
+	StepStatusSkipped StepStatusValue = "SKIPPED"
+	// StepStatusFailed Step.Status value FAILED
+	StepStatusFailed StepStatusValue = "FAILED"
+
+	// DNSModeSkip Request.DNSMode value, the CNAME record is not touched
+	DNSModeSkip DNSModeValue = "SKIP"
+	// DNSModeValidate Request.DNSMode value, the CNAME record must already exist
+	DNSModeValidate DNSModeValue = "VALIDATE"
+	// DNSModeCreate Request.DNSMode value, the CNAME record is created, or
+	// updated if Request.OverwriteCname is set
+	DNSModeCreate DNSModeValue = "CREATE"
+)
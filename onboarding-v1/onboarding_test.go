@@ -0,0 +1,461 @@
+package onboarding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+var (
+	config = edgegrid.Config{
+		Host:         "akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net/",
+		AccessToken:  "akab-access-token-xxx-xxxxxxxxxxxxxxxx",
+		ClientToken:  "akab-client-token-xxx-xxxxxxxxxxxxxxxx",
+		ClientSecret: "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx=",
+		MaxBody:      2048,
+		Debug:        false,
+	}
+)
+
+func testProperty() *papi.Property {
+	property := papi.NewProperty(papi.NewProperties())
+	property.Contract = papi.NewContract(papi.NewContracts())
+	property.Contract.ContractID = "ctr_1"
+	property.Group = papi.NewGroup(papi.NewGroups())
+	property.Group.GroupID = "grp_1"
+	property.PropertyID = "prp_1"
+	property.ProductID = "prd_Fresca"
+
+	return property
+}
+
+func TestRequest_EdgeHostnameDomain(t *testing.T) {
+	request := &Request{Hostname: "www.example.com"}
+	assert.Equal(t, "www.example.com.edgesuite.net", request.EdgeHostnameDomain())
+
+	request.Secure = true
+	assert.Equal(t, "www.example.com.edgekey.net", request.EdgeHostnameDomain())
+
+	request.EdgeHostnamePrefix = "example"
+	assert.Equal(t, "example.edgekey.net", request.EdgeHostnameDomain())
+}
+
+func TestSameHostname(t *testing.T) {
+	assert.True(t, sameHostname("www.example.com.edgesuite.net.", "WWW.example.com.edgesuite.net"))
+	assert.False(t, sameHostname("www.example.com.edgesuite.net.", "www.example.com.edgekey.net."))
+}
+
+func TestEnsureCname_Skip(t *testing.T) {
+	result := &Result{}
+	err := ensureCname(&Request{Hostname: "www.example.com"}, result)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Steps, 1)
+	assert.Equal(t, StepDNSRecord, result.Steps[0].Name)
+	assert.Equal(t, StepStatusSkipped, result.Steps[0].Status)
+}
+
+func TestEnsureCname_ZoneNotFound(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones/example.com/recordsets").
+		MatchParam("types", "CNAME").
+		HeaderPresent("Authorization").
+		Reply(404).
+		SetHeader("Content-Type", "application/problem+json").
+		BodyString(`{"title": "Not Found", "status": 404, "detail": "Zone example.com does not exist"}`)
+
+	Init(config)
+
+	result := &Result{}
+	err := ensureCname(&Request{Hostname: "www.example.com", DNSMode: DNSModeCreate, Zone: "example.com"}, result)
+
+	assert.EqualError(t, err, "zone example.com not found, unable to manage the CNAME for www.example.com")
+	assert.Empty(t, result.Steps)
+}
+
+func mockExistingCname(target string) {
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones/example.com/recordsets").
+		MatchParam("types", "CNAME").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"recordsets": [{"name": "www.example.com", "type": "CNAME", "ttl": 300, "rdata": ["` + target + `"]}]}`)
+}
+
+func TestEnsureCname_PointsElsewhere(t *testing.T) {
+	defer gock.Off()
+	mockExistingCname("www.example.com.cdn.example.net.")
+
+	Init(config)
+
+	result := &Result{}
+	err := ensureCname(&Request{Hostname: "www.example.com", DNSMode: DNSModeCreate, Zone: "example.com"}, result)
+
+	assert.EqualError(t, err, "CNAME record for www.example.com points to www.example.com.cdn.example.net., expected www.example.com.edgesuite.net.")
+	assert.Empty(t, result.Steps)
+	assert.True(t, gock.IsDone())
+}
+
+func TestEnsureCname_Overwrite(t *testing.T) {
+	defer gock.Off()
+	mockExistingCname("www.example.com.cdn.example.net.")
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Put("/config-dns/v2/zones/example.com/names/www.example.com/types/CNAME").
+		JSON(`{"name": "www.example.com", "type": "CNAME", "ttl": 300, "rdata": ["www.example.com.edgesuite.net."]}`).
+		HeaderPresent("Authorization").
+		Reply(200)
+
+	Init(config)
+
+	result := &Result{}
+	err := ensureCname(&Request{Hostname: "www.example.com", DNSMode: DNSModeCreate, Zone: "example.com", OverwriteCname: true}, result)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*Step{
+		{Name: StepDNSRecord, Status: StepStatusUpdated, Detail: "www.example.com.edgesuite.net."},
+	}, result.Steps)
+	assert.True(t, gock.IsDone())
+}
+
+func TestOnboard_RequiresHostname(t *testing.T) {
+	_, err := Onboard(&Request{})
+	assert.Error(t, err)
+}
+
+func TestOnboard(t *testing.T) {
+	defer gock.Off()
+	defer func(interval time.Duration) { edgeHostnamePollInterval = interval }(edgeHostnamePollInterval)
+	edgeHostnamePollInterval = time.Millisecond
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/edgehostnames").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"accountId": "act_1",
+			"contractId": "ctr_1",
+			"groupId": "grp_1",
+			"edgeHostnames": {
+				"items": [
+					{"edgeHostnameId": "ehn_1", "edgeHostnameDomain": "other.example.com.edgesuite.net", "productId": "prd_Fresca", "domainPrefix": "other.example.com", "domainSuffix": "edgesuite.net", "status": "ACTIVE"}
+				]
+			}
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/papi/v1/edgehostnames/").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		JSON(`{"productId": "prd_Fresca", "domainPrefix": "www.example.com", "domainSuffix": "edgesuite.net", "ipVersionBehavior": "IPV4"}`).
+		HeaderPresent("Authorization").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"edgeHostnameLink": "/papi/v1/edgehostnames/ehn_2?contractId=ctr_1&groupId=grp_1"}`)
+
+	for _, status := range []string{"PENDING", "ACTIVE"} {
+		mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+		mock.
+			Get("/papi/v1/edgehostnames/ehn_2").
+			MatchParam("contractId", "ctr_1").
+			MatchParam("groupId", "grp_1").
+			HeaderPresent("Authorization").
+			Reply(200).
+			SetHeader("Content-Type", "application/json").
+			BodyString(`{
+				"edgeHostnames": {
+					"items": [
+						{"edgeHostnameId": "ehn_2", "edgeHostnameDomain": "www.example.com.edgesuite.net", "productId": "prd_Fresca", "domainPrefix": "www.example.com", "domainSuffix": "edgesuite.net", "status": "` + status + `"}
+					]
+				}
+			}`)
+	}
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_1",
+			"contractId": "ctr_1",
+			"groupId": "grp_1",
+			"versions": {
+				"items": [{"propertyVersion": 1, "productionStatus": "ACTIVE", "stagingStatus": "ACTIVE", "etag": "e1"}]
+			}
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions/latest").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_1",
+			"versions": {
+				"items": [{"propertyVersion": 1, "productionStatus": "ACTIVE", "stagingStatus": "ACTIVE", "etag": "e1"}]
+			}
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/papi/v1/properties/prp_1/versions").
+		HeaderPresent("Authorization").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"versionLink": "/papi/v1/properties/prp_1/versions/2?contractId=ctr_1&groupId=grp_1"}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions/2").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_1",
+			"versions": {
+				"items": [{"propertyVersion": 2, "productionStatus": "INACTIVE", "stagingStatus": "INACTIVE", "etag": "e2"}]
+			}
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions/2/hostnames/").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_1",
+			"contractId": "ctr_1",
+			"groupId": "grp_1",
+			"propertyVersion": 2,
+			"hostnames": {
+				"items": [{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_1", "cnameFrom": "other.example.com", "cnameTo": "other.example.com.edgesuite.net"}]
+			}
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Put("/papi/v1/properties/prp_1/versions/2/hostnames").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		JSON(`[
+			{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_1", "cnameFrom": "other.example.com", "cnameTo": "other.example.com.edgesuite.net"},
+			{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_2", "cnameFrom": "www.example.com", "cnameTo": "www.example.com.edgesuite.net"}
+		]`).
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_1",
+			"propertyVersion": 2,
+			"hostnames": {
+				"items": [
+					{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_1", "cnameFrom": "other.example.com", "cnameTo": "other.example.com.edgesuite.net"},
+					{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_2", "cnameFrom": "www.example.com", "cnameTo": "www.example.com.edgesuite.net"}
+				]
+			}
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones/example.com/recordsets").
+		MatchParam("types", "CNAME").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"recordsets": []}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/config-dns/v2/zones/example.com/names/www.example.com/types/CNAME").
+		JSON(`{"name": "www.example.com", "type": "CNAME", "ttl": 300, "rdata": ["www.example.com.edgesuite.net."]}`).
+		HeaderPresent("Authorization").
+		Reply(201)
+
+	Init(config)
+
+	result, err := Onboard(&Request{
+		Property:            testProperty(),
+		Hostname:            "www.example.com",
+		EdgeHostnameTimeout: time.Minute,
+		DNSMode:             DNSModeCreate,
+		Zone:                "example.com",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*Step{
+		{Name: StepEdgeHostname, Status: StepStatusCreated, Detail: "ehn_2"},
+		{Name: StepPropertyVersion, Status: StepStatusCreated, Detail: "version 2"},
+		{Name: StepPropertyHostname, Status: StepStatusCreated, Detail: "www.example.com.edgesuite.net"},
+		{Name: StepDNSRecord, Status: StepStatusCreated, Detail: "www.example.com.edgesuite.net."},
+	}, result.Steps)
+	assert.Equal(t, papi.StatusActive, result.EdgeHostname.Status)
+	assert.Equal(t, 2, result.Version.PropertyVersion)
+	assert.True(t, gock.IsDone())
+}
+
+func TestOnboard_Reuse(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/edgehostnames").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"contractId": "ctr_1",
+			"groupId": "grp_1",
+			"edgeHostnames": {
+				"items": [
+					{"edgeHostnameId": "ehn_2", "edgeHostnameDomain": "www.example.com.edgesuite.net", "productId": "prd_Fresca", "domainPrefix": "www.example.com", "domainSuffix": "edgesuite.net", "status": "ACTIVE"}
+				]
+			}
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_1",
+			"versions": {
+				"items": [{"propertyVersion": 3, "productionStatus": "INACTIVE", "stagingStatus": "INACTIVE", "etag": "e3"}]
+			}
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions/latest").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_1",
+			"versions": {
+				"items": [{"propertyVersion": 3, "productionStatus": "INACTIVE", "stagingStatus": "INACTIVE", "etag": "e3"}]
+			}
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions/3/hostnames/").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_1",
+			"propertyVersion": 3,
+			"hostnames": {
+				"items": [{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_2", "cnameFrom": "WWW.example.com", "cnameTo": "www.example.com.edgesuite.net"}]
+			}
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/config-dns/v2/zones/example.com/recordsets").
+		MatchParam("types", "CNAME").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"recordsets": [{"name": "www.example.com", "type": "CNAME", "ttl": 300, "rdata": ["WWW.example.com.edgesuite.net"]}]}`)
+
+	Init(config)
+
+	result, err := Onboard(&Request{
+		Property:            testProperty(),
+		Hostname:            "www.example.com",
+		EdgeHostnameTimeout: time.Minute,
+		DNSMode:             DNSModeValidate,
+		Zone:                "example.com",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*Step{
+		{Name: StepEdgeHostname, Status: StepStatusReused, Detail: "ehn_2"},
+		{Name: StepPropertyVersion, Status: StepStatusReused, Detail: "version 3"},
+		{Name: StepPropertyHostname, Status: StepStatusReused, Detail: "www.example.com.edgesuite.net"},
+		{Name: StepDNSRecord, Status: StepStatusValidated, Detail: "www.example.com.edgesuite.net."},
+	}, result.Steps)
+	assert.True(t, gock.IsDone())
+}
+
+func TestOnboard_EdgeHostnameTimedOut(t *testing.T) {
+	defer gock.Off()
+	defer func(interval time.Duration) { edgeHostnamePollInterval = interval }(edgeHostnamePollInterval)
+	edgeHostnamePollInterval = time.Millisecond
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/edgehostnames").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"contractId": "ctr_1", "groupId": "grp_1", "edgeHostnames": {"items": []}}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/papi/v1/edgehostnames/").
+		HeaderPresent("Authorization").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"edgeHostnameLink": "/papi/v1/edgehostnames/ehn_2?contractId=ctr_1&groupId=grp_1"}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/edgehostnames/ehn_2").
+		HeaderPresent("Authorization").
+		Persist().
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"edgeHostnames": {"items": [{"edgeHostnameId": "ehn_2", "domainPrefix": "www.example.com", "domainSuffix": "edgesuite.net", "status": "PENDING"}]}}`)
+
+	Init(config)
+
+	start := time.Now()
+	result, err := Onboard(&Request{
+		Property:            testProperty(),
+		Hostname:            "www.example.com",
+		EdgeHostnameTimeout: 20 * time.Millisecond,
+		DNSMode:             DNSModeCreate,
+		Zone:                "example.com",
+	})
+
+	assert.EqualError(t, err, "edge hostname www.example.com.edgesuite.net was not active after 20ms")
+	assert.True(t, time.Since(start) < time.Second)
+	assert.Equal(t, "ehn_2", result.EdgeHostname.EdgeHostnameID)
+	if assert.Len(t, result.Steps, 1) {
+		assert.Equal(t, StepEdgeHostname, result.Steps[0].Name)
+		assert.Equal(t, StepStatusTimedOut, result.Steps[0].Status)
+		assert.Equal(t, "ehn_2", result.Steps[0].Detail)
+	}
+	assert.Nil(t, result.Version)
+}
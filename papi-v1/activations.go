@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"sort"
 	"time"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
@@ -108,6 +109,83 @@ func (activations *Activations) GetLatestActivation(network NetworkValue, status
 	return latest, nil
 }
 
+// GetPreviousActivation finds the activation of the last known good version
+// on a network, other than currentVersion
+//
+// The completed activations and deactivations of the network are replayed in
+// order. An activation that completed is ACTIVE until a later activation
+// supersedes it, when it becomes INACTIVE; both are considered known good,
+// unless the version was later deactivated. A rollback made by
+// Property.Rollback() replaces both the version it rolled back from, which is
+// no longer considered good, and the version it copied.
+func (activations *Activations) GetPreviousActivation(network NetworkValue, currentVersion int) (*Activation, error) {
+	if network == "" {
+		network = NetworkProduction
+	}
+
+	var history []*Activation
+	for _, activation := range activations.Activations.Items {
+		if activation.Network != network {
+			continue
+		}
+
+		if activation.Status != StatusActive && activation.Status != StatusInactive {
+			continue
+		}
+
+		history = append(history, activation)
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		if history[i].UpdateDate != history[j].UpdateDate {
+			return history[i].UpdateDate < history[j].UpdateDate
+		}
+
+		return history[i].PropertyVersion < history[j].PropertyVersion
+	})
+
+	// good holds the known good activations, the most recent last
+	var good []*Activation
+	for _, activation := range history {
+		good = removeActivatedVersion(good, activation.PropertyVersion)
+
+		if activation.ActivationType != ActivationTypeActivate {
+			continue
+		}
+
+		var rollbackNetwork NetworkValue
+		var rollbackVersion int
+		if _, err := fmt.Sscanf(activation.Note, rollbackNoteFormat, &rollbackNetwork, &rollbackVersion); err == nil && rollbackNetwork == network {
+			if len(good) != 0 {
+				good = good[:len(good)-1]
+			}
+			good = removeActivatedVersion(good, rollbackVersion)
+		}
+
+		good = append(good, activation)
+	}
+
+	for i := len(good) - 1; i >= 0; i-- {
+		if good[i].PropertyVersion != currentVersion {
+			return good[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("No previous activation found (network: %s, current version: %d)", network, currentVersion)
+}
+
+// removeActivatedVersion removes the activations of a version
+func removeActivatedVersion(activations []*Activation, propertyVersion int) []*Activation {
+	kept := activations[:0]
+	for _, activation := range activations {
+		if activation.PropertyVersion != propertyVersion {
+			kept = append(kept, activation)
+		}
+	}
+
+	return kept
+}
+
 // Activation represents a property activation resource
 type Activation struct {
 	client.Resource
@@ -130,8 +208,14 @@ type Activation struct {
 	StatusChange        chan bool                   `json:"-"`
 }
 
+// ActivationComplianceRecord documents the change management of a production activation
 type ActivationComplianceRecord struct {
-	NoncomplianceReason string `json:"noncomplianceReason,omitempty"`
+	NoncomplianceReason      string `json:"noncomplianceReason,omitempty"`
+	OtherNoncomplianceReason string `json:"otherNoncomplianceReason,omitempty"`
+	TicketID                 string `json:"ticketId,omitempty"`
+	CustomerEmail            string `json:"customerEmail,omitempty"`
+	PeerReviewedBy           string `json:"peerReviewedBy,omitempty"`
+	UnitTested               bool   `json:"unitTested,omitempty"`
 }
 
 // NewActivation creates a new Activation
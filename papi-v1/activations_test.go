@@ -0,0 +1,357 @@
+package papi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestActivations_GetPreviousActivation(t *testing.T) {
+	activations := NewActivations()
+	err := jsonhooks.Unmarshal([]byte(`{
+		"activations": {
+			"items": [
+				{
+					"activationId": "atv_1",
+					"activationType": "ACTIVATE",
+					"propertyVersion": 1,
+					"network": "PRODUCTION",
+					"status": "ACTIVE",
+					"updateDate": "2019-01-01T00:00:00Z"
+				},
+				{
+					"activationId": "atv_2",
+					"activationType": "ACTIVATE",
+					"propertyVersion": 2,
+					"network": "PRODUCTION",
+					"status": "ACTIVE",
+					"updateDate": "2019-02-01T00:00:00Z"
+				},
+				{
+					"activationId": "atv_3",
+					"activationType": "ACTIVATE",
+					"propertyVersion": 3,
+					"network": "PRODUCTION",
+					"status": "FAILED",
+					"updateDate": "2019-03-01T00:00:00Z"
+				},
+				{
+					"activationId": "atv_4",
+					"activationType": "ACTIVATE",
+					"propertyVersion": 4,
+					"network": "STAGING",
+					"status": "ACTIVE",
+					"updateDate": "2019-04-01T00:00:00Z"
+				},
+				{
+					"activationId": "atv_5",
+					"activationType": "ACTIVATE",
+					"propertyVersion": 5,
+					"network": "PRODUCTION",
+					"status": "ACTIVE",
+					"updateDate": "2019-05-01T00:00:00Z"
+				}
+			]
+		}
+	}`), activations)
+	assert.NoError(t, err)
+
+	previous, err := activations.GetPreviousActivation(NetworkProduction, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, "atv_2", previous.ActivationID)
+	assert.Equal(t, 2, previous.PropertyVersion)
+
+	previous, err = activations.GetPreviousActivation(NetworkStaging, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, previous.PropertyVersion)
+
+	_, err = activations.GetPreviousActivation(NetworkStaging, 4)
+	assert.Error(t, err)
+}
+
+func TestActivations_GetPreviousActivation_Superseded(t *testing.T) {
+	activations := NewActivations()
+	err := jsonhooks.Unmarshal([]byte(`{
+		"activations": {
+			"items": [
+				{
+					"activationId": "atv_14",
+					"activationType": "ACTIVATE",
+					"propertyVersion": 14,
+					"network": "PRODUCTION",
+					"status": "ACTIVE",
+					"updateDate": "2019-05-01T00:00:00Z"
+				},
+				{
+					"activationId": "atv_13",
+					"activationType": "ACTIVATE",
+					"propertyVersion": 13,
+					"network": "PRODUCTION",
+					"status": "FAILED",
+					"updateDate": "2019-04-01T00:00:00Z"
+				},
+				{
+					"activationId": "atv_12d",
+					"activationType": "DEACTIVATE",
+					"propertyVersion": 12,
+					"network": "PRODUCTION",
+					"status": "INACTIVE",
+					"updateDate": "2019-03-15T00:00:00Z"
+				},
+				{
+					"activationId": "atv_12",
+					"activationType": "ACTIVATE",
+					"propertyVersion": 12,
+					"network": "PRODUCTION",
+					"status": "INACTIVE",
+					"updateDate": "2019-03-01T00:00:00Z"
+				},
+				{
+					"activationId": "atv_11",
+					"activationType": "ACTIVATE",
+					"propertyVersion": 11,
+					"network": "PRODUCTION",
+					"status": "INACTIVE",
+					"updateDate": "2019-02-01T00:00:00Z"
+				},
+				{
+					"activationId": "atv_13s",
+					"activationType": "ACTIVATE",
+					"propertyVersion": 13,
+					"network": "STAGING",
+					"status": "ACTIVE",
+					"updateDate": "2019-03-20T00:00:00Z"
+				}
+			]
+		}
+	}`), activations)
+	assert.NoError(t, err)
+
+	previous, err := activations.GetPreviousActivation(NetworkProduction, 14)
+	assert.NoError(t, err)
+	assert.Equal(t, "atv_11", previous.ActivationID)
+
+	previous, err = activations.GetPreviousActivation(NetworkProduction, 12)
+	assert.NoError(t, err)
+	assert.Equal(t, 14, previous.PropertyVersion)
+}
+
+func TestActivations_GetPreviousActivation_RolledBack(t *testing.T) {
+	activations := NewActivations()
+	err := jsonhooks.Unmarshal([]byte(`{
+		"activations": {
+			"items": [
+				{
+					"activationId": "atv_15",
+					"activationType": "ACTIVATE",
+					"propertyVersion": 15,
+					"network": "PRODUCTION",
+					"status": "ACTIVE",
+					"note": "Rollback of PRODUCTION to version 12",
+					"updateDate": "2019-06-01T00:00:00Z"
+				},
+				{
+					"activationId": "atv_14",
+					"activationType": "ACTIVATE",
+					"propertyVersion": 14,
+					"network": "PRODUCTION",
+					"status": "INACTIVE",
+					"updateDate": "2019-05-01T00:00:00Z"
+				},
+				{
+					"activationId": "atv_12",
+					"activationType": "ACTIVATE",
+					"propertyVersion": 12,
+					"network": "PRODUCTION",
+					"status": "INACTIVE",
+					"updateDate": "2019-03-01T00:00:00Z"
+				},
+				{
+					"activationId": "atv_11",
+					"activationType": "ACTIVATE",
+					"propertyVersion": 11,
+					"network": "PRODUCTION",
+					"status": "INACTIVE",
+					"updateDate": "2019-02-01T00:00:00Z"
+				}
+			]
+		}
+	}`), activations)
+	assert.NoError(t, err)
+
+	previous, err := activations.GetPreviousActivation(NetworkProduction, 15)
+	assert.NoError(t, err)
+	assert.Equal(t, "atv_11", previous.ActivationID)
+}
+
+func mockRollback() {
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_1",
+			"versions": {
+				"items": [
+					{"propertyVersion": 15, "productionStatus": "ACTIVE", "etag": "etag-15"},
+					{"propertyVersion": 14, "productionStatus": "INACTIVE", "etag": "etag-14"},
+					{"propertyVersion": 12, "productionStatus": "INACTIVE", "etag": "etag-12"},
+					{"propertyVersion": 11, "productionStatus": "INACTIVE", "etag": "etag-11"}
+				]
+			}
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/activations").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"activations": {
+				"items": [
+					{"activationId": "atv_15", "activationType": "ACTIVATE", "propertyVersion": 15, "network": "PRODUCTION", "status": "ACTIVE", "note": "Rollback of PRODUCTION to version 12", "updateDate": "2019-06-01T00:00:00Z"},
+					{"activationId": "atv_14", "activationType": "ACTIVATE", "propertyVersion": 14, "network": "PRODUCTION", "status": "INACTIVE", "updateDate": "2019-05-01T00:00:00Z"},
+					{"activationId": "atv_12", "activationType": "ACTIVATE", "propertyVersion": 12, "network": "PRODUCTION", "status": "INACTIVE", "updateDate": "2019-03-01T00:00:00Z"},
+					{"activationId": "atv_11", "activationType": "ACTIVATE", "propertyVersion": 11, "network": "PRODUCTION", "status": "INACTIVE", "updateDate": "2019-02-01T00:00:00Z"}
+				]
+			}
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/papi/v1/properties/prp_1/versions").
+		JSON(`{"updatedDate": "0001-01-01T00:00:00Z", "note": "Rollback of PRODUCTION to version 11", "createFromVersion": 11, "createFromVersionEtag": "etag-11"}`).
+		HeaderPresent("Authorization").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"versionLink": "/papi/v1/properties/prp_1/versions/16?contractId=ctr_1&groupId=grp_1"}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions/16").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"versions": {"items": [{"propertyVersion": 16, "note": "Rollback of PRODUCTION to version 11"}]}}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/papi/v1/properties/prp_1/activations").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		JSON(`{
+			"propertyVersion": 16,
+			"network": "PRODUCTION",
+			"fastPush": true,
+			"note": "Rollback of PRODUCTION to version 11",
+			"notifyEmails": ["ops@example.com"],
+			"complianceRecord": {"noncomplianceReason": "NO_PRODUCTION_TRAFFIC"}
+		}`).
+		HeaderPresent("Authorization").
+		Reply(400).
+		SetHeader("Content-Type", "application/problem+json").
+		BodyString(`{"type": "activation-warnings", "warnings": [{"messageId": "msg_1", "detail": "Hostname not covered"}]}`)
+}
+
+func newRollbackTestProperty() *Property {
+	property := NewProperty(NewProperties())
+	property.Contract = NewContract(NewContracts())
+	property.Contract.ContractID = "ctr_1"
+	property.Group = NewGroup(NewGroups())
+	property.Group.GroupID = "grp_1"
+	property.ContractID = "ctr_1"
+	property.GroupID = "grp_1"
+	property.PropertyID = "prp_1"
+
+	return property
+}
+
+func TestProperty_Rollback(t *testing.T) {
+	defer gock.Off()
+	mockRollback()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/papi/v1/properties/prp_1/activations").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		JSON(`{
+			"propertyVersion": 16,
+			"network": "PRODUCTION",
+			"acknowledgeWarnings": ["msg_1"],
+			"fastPush": true,
+			"note": "Rollback of PRODUCTION to version 11",
+			"notifyEmails": ["ops@example.com"],
+			"complianceRecord": {"noncomplianceReason": "NO_PRODUCTION_TRAFFIC"}
+		}`).
+		HeaderPresent("Authorization").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activationLink": "/papi/v1/properties/prp_1/activations/atv_16?contractId=ctr_1&groupId=grp_1"}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/activations/atv_16").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activations": {"items": [{"activationId": "atv_16", "activationType": "ACTIVATE", "propertyVersion": 16, "network": "PRODUCTION", "status": "PENDING", "fastPush": true}]}}`)
+
+	Init(config)
+
+	activation, err := newRollbackTestProperty().Rollback(NetworkProduction, []string{"ops@example.com"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "atv_16", activation.ActivationID)
+	assert.Equal(t, 16, activation.PropertyVersion)
+	assert.Equal(t, StatusPending, activation.Status)
+	assert.True(t, gock.IsDone())
+}
+
+func TestProperty_Rollback_Warnings(t *testing.T) {
+	defer gock.Off()
+	mockRollback()
+
+	Init(config)
+
+	_, err := newRollbackTestProperty().Rollback(NetworkProduction, []string{"ops@example.com"}, false)
+	assert.Error(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+func TestActivationComplianceRecord_JSON(t *testing.T) {
+	record := &ActivationComplianceRecord{
+		NoncomplianceReason:      "OTHER",
+		OtherNoncomplianceReason: "Emergency fix",
+		TicketID:                 "CHG-1",
+		CustomerEmail:            "customer@example.com",
+		PeerReviewedBy:           "reviewer@example.com",
+		UnitTested:               true,
+	}
+
+	data, err := json.Marshal(record)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"noncomplianceReason": "OTHER",
+		"otherNoncomplianceReason": "Emergency fix",
+		"ticketId": "CHG-1",
+		"customerEmail": "customer@example.com",
+		"peerReviewedBy": "reviewer@example.com",
+		"unitTested": true
+	}`, string(data))
+
+	data, err = json.Marshal(&ActivationComplianceRecord{NoncomplianceReason: "NO_PRODUCTION_TRAFFIC"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"noncomplianceReason": "NO_PRODUCTION_TRAFFIC"}`, string(data))
+
+	decoded := &ActivationComplianceRecord{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"noncomplianceReason": "OTHER", "otherNoncomplianceReason": "Emergency fix", "ticketId": "CHG-1", "customerEmail": "customer@example.com", "peerReviewedBy": "reviewer@example.com", "unitTested": true}`), decoded))
+	assert.Equal(t, record, decoded)
+}
@@ -0,0 +1,629 @@
+package papi
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// BulkSearch represents a bulk rule tree search request
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#bulksearchrequest
+type BulkSearch struct {
+	client.Resource
+	BulkSearchID       int                 `json:"bulkSearchId,omitempty"`
+	SearchTargetStatus BulkStatusValue     `json:"searchTargetStatus,omitempty"`
+	SearchSubmitDate   string              `json:"searchSubmitDate,omitempty"`
+	SearchUpdateDate   string              `json:"searchUpdateDate,omitempty"`
+	BulkSearchQuery    *BulkSearchQuery    `json:"bulkSearchQuery"`
+	Results            []*BulkSearchResult `json:"results,omitempty"`
+	StatusChange       chan bool           `json:"-"`
+}
+
+// BulkSearchQuery represents the JSONPath query of a BulkSearch
+type BulkSearchQuery struct {
+	Syntax               BulkSearchSyntaxValue `json:"syntax"`
+	Match                string                `json:"match"`
+	BulkSearchQualifiers []string              `json:"bulkSearchQualifiers,omitempty"`
+}
+
+// BulkSearchResult represents a property version matched by a BulkSearch
+//
+// MatchLocations are JSON pointers into the rule tree, suitable for use as
+// PatchOperation.Path values.
+type BulkSearchResult struct {
+	AccountID        string      `json:"accountId"`
+	PropertyID       string      `json:"propertyId"`
+	PropertyName     string      `json:"propertyName"`
+	PropertyVersion  int         `json:"propertyVersion"`
+	PropertyType     string      `json:"propertyType,omitempty"`
+	IsLatest         bool        `json:"isLatest"`
+	IsLocked         bool        `json:"isLocked"`
+	IsSecure         bool        `json:"isSecure"`
+	LastModifiedTime string      `json:"lastModifiedTime,omitempty"`
+	ProductionStatus StatusValue `json:"productionStatus,omitempty"`
+	StagingStatus    StatusValue `json:"stagingStatus,omitempty"`
+	MatchLocations   []string    `json:"matchLocations"`
+}
+
+// NewBulkSearch creates a new BulkSearch for a JSONPath match expression
+func NewBulkSearch(match string) *BulkSearch {
+	bulkSearch := &BulkSearch{
+		BulkSearchQuery: &BulkSearchQuery{
+			Syntax: BulkSearchSyntaxJSONPath,
+			Match:  match,
+		},
+	}
+	bulkSearch.Init()
+
+	return bulkSearch
+}
+
+func (bulkSearch *BulkSearch) Init() {
+	bulkSearch.Complete = make(chan bool, 1)
+	bulkSearch.StatusChange = make(chan bool, 1)
+}
+
+// Save submits the bulk search request
+//
+// contract and group are optional and limit the search scope.
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#postbulksearchrequest
+// Endpoint: POST /papi/v1/bulk/rules-search-requests{?contractId,groupId}
+func (bulkSearch *BulkSearch) Save(contract *Contract, group *Group) error {
+	id, err := bulkSubmit(
+		"/papi/v1/bulk/rules-search-requests"+bulkQueryString(contract, group),
+		client.JSONBody{"bulkSearchQuery": bulkSearch.BulkSearchQuery},
+		"bulkSearchLink",
+	)
+	if err != nil {
+		return err
+	}
+
+	bulkSearch.BulkSearchID = id
+
+	_, err = bulkSearch.GetBulkSearch()
+	return err
+}
+
+// GetBulkSearch populates the BulkSearch with its current status and results
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getbulksearchrequest
+// Endpoint: GET /papi/v1/bulk/rules-search-requests/{bulkSearchId}
+func (bulkSearch *BulkSearch) GetBulkSearch() (time.Duration, error) {
+	err := bulkGet(
+		fmt.Sprintf("/papi/v1/bulk/rules-search-requests/%d", bulkSearch.BulkSearchID),
+		bulkSearch,
+	)
+
+	return time.Duration(10 * time.Second), err
+}
+
+// PollStatus will responsibly poll till the search is complete or an error occurs
+//
+// The BulkSearch.StatusChange is a channel that can be used to
+// block on status changes. If a new valid status is returned, true will
+// be sent to the channel, otherwise, false will be sent.
+func (bulkSearch *BulkSearch) PollStatus() bool {
+	currentStatus := bulkSearch.SearchTargetStatus
+	var retry time.Duration = 0
+
+	for !currentStatus.IsDone() {
+		time.Sleep(retry)
+
+		var err error
+		retry, err = bulkSearch.GetBulkSearch()
+		if err != nil {
+			bulkSearch.StatusChange <- false
+			return false
+		}
+
+		if currentStatus != bulkSearch.SearchTargetStatus {
+			currentStatus = bulkSearch.SearchTargetStatus
+			bulkSearch.StatusChange <- true
+		}
+	}
+
+	return currentStatus == BulkStatusComplete
+}
+
+// NewBulkVersionCreation creates a BulkVersionCreation with a new version
+// for each property version found by the search
+func (bulkSearch *BulkSearch) NewBulkVersionCreation() *BulkVersionCreation {
+	bulkVersionCreation := NewBulkVersionCreation()
+	for _, result := range bulkSearch.Results {
+		bulkVersionCreation.CreatePropertyVersions = append(
+			bulkVersionCreation.CreatePropertyVersions,
+			&BulkCreatePropertyVersion{
+				PropertyID:        result.PropertyID,
+				CreateFromVersion: result.PropertyVersion,
+			},
+		)
+	}
+
+	return bulkVersionCreation
+}
+
+// NewBulkPatch creates a BulkPatch for the property versions found by the search
+//
+// patchFunc is called for every result and returns the patches to apply to
+// that property version. Results for which no patches are returned are skipped.
+func (bulkSearch *BulkSearch) NewBulkPatch(patchFunc func(result *BulkSearchResult) []*PatchOperation) *BulkPatch {
+	bulkPatch := NewBulkPatch()
+	for _, result := range bulkSearch.Results {
+		patches := patchFunc(result)
+		if len(patches) == 0 {
+			continue
+		}
+
+		bulkPatch.PatchPropertyVersions = append(
+			bulkPatch.PatchPropertyVersions,
+			&BulkPatchPropertyVersion{
+				PropertyID:      result.PropertyID,
+				PropertyVersion: result.PropertyVersion,
+				Patches:         patches,
+			},
+		)
+	}
+
+	return bulkPatch
+}
+
+// BulkVersionCreation represents a bulk property version creation request
+//
+// Active property versions cannot be patched; use this to create editable
+// versions of the properties found by a BulkSearch.
+type BulkVersionCreation struct {
+	client.Resource
+	BulkCreateVersionsID     int                          `json:"bulkCreateVersionsId,omitempty"`
+	BulkCreateVersionsStatus BulkStatusValue              `json:"bulkCreateVersionsStatus,omitempty"`
+	SubmitDate               string                       `json:"submitDate,omitempty"`
+	UpdateDate               string                       `json:"updateDate,omitempty"`
+	CreatePropertyVersions   []*BulkCreatePropertyVersion `json:"createPropertyVersions"`
+	StatusChange             chan bool                    `json:"-"`
+}
+
+// BulkCreatePropertyVersion represents a single version created by a BulkVersionCreation
+type BulkCreatePropertyVersion struct {
+	PropertyID            string          `json:"propertyId"`
+	PropertyName          string          `json:"propertyName,omitempty"`
+	CreateFromVersion     int             `json:"createFromVersion"`
+	CreateFromVersionEtag string          `json:"createFromVersionEtag,omitempty"`
+	PropertyVersion       int             `json:"propertyVersion,omitempty"`
+	Etag                  string          `json:"etag,omitempty"`
+	CreateVersionStatus   BulkStatusValue `json:"createVersionStatus,omitempty"`
+	FailureCause          string          `json:"failureCause,omitempty"`
+}
+
+// NewBulkVersionCreation creates a new BulkVersionCreation
+func NewBulkVersionCreation() *BulkVersionCreation {
+	bulkVersionCreation := &BulkVersionCreation{}
+	bulkVersionCreation.Init()
+
+	return bulkVersionCreation
+}
+
+func (bulkVersionCreation *BulkVersionCreation) Init() {
+	bulkVersionCreation.Complete = make(chan bool, 1)
+	bulkVersionCreation.StatusChange = make(chan bool, 1)
+}
+
+// Save submits the bulk version creation request
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#postbulkversioning
+// Endpoint: POST /papi/v1/bulk/property-version-creations{?contractId,groupId}
+func (bulkVersionCreation *BulkVersionCreation) Save(contract *Contract, group *Group) error {
+	id, err := bulkSubmit(
+		"/papi/v1/bulk/property-version-creations"+bulkQueryString(contract, group),
+		client.JSONBody{"createPropertyVersions": bulkVersionCreation.CreatePropertyVersions},
+		"bulkCreateVersionLink",
+	)
+	if err != nil {
+		return err
+	}
+
+	bulkVersionCreation.BulkCreateVersionsID = id
+
+	_, err = bulkVersionCreation.GetBulkVersionCreation()
+	return err
+}
+
+// GetBulkVersionCreation populates the BulkVersionCreation with its current status
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getbulkversioning
+// Endpoint: GET /papi/v1/bulk/property-version-creations/{bulkCreateId}
+func (bulkVersionCreation *BulkVersionCreation) GetBulkVersionCreation() (time.Duration, error) {
+	err := bulkGet(
+		fmt.Sprintf("/papi/v1/bulk/property-version-creations/%d", bulkVersionCreation.BulkCreateVersionsID),
+		bulkVersionCreation,
+	)
+
+	return time.Duration(10 * time.Second), err
+}
+
+// PollStatus will responsibly poll till the versions are created or an error occurs
+//
+// See: BulkSearch.PollStatus()
+func (bulkVersionCreation *BulkVersionCreation) PollStatus() bool {
+	currentStatus := bulkVersionCreation.BulkCreateVersionsStatus
+	var retry time.Duration = 0
+
+	for !currentStatus.IsDone() {
+		time.Sleep(retry)
+
+		var err error
+		retry, err = bulkVersionCreation.GetBulkVersionCreation()
+		if err != nil {
+			bulkVersionCreation.StatusChange <- false
+			return false
+		}
+
+		if currentStatus != bulkVersionCreation.BulkCreateVersionsStatus {
+			currentStatus = bulkVersionCreation.BulkCreateVersionsStatus
+			bulkVersionCreation.StatusChange <- true
+		}
+	}
+
+	return currentStatus == BulkStatusComplete
+}
+
+// NewBulkPatch creates a BulkPatch targeting the newly created versions
+//
+// patchFunc is called for every search result, see BulkSearch.NewBulkPatch().
+// Only results with a newly created version are patched. Results whose version
+// could not be created are left out, and the failed creations are returned so
+// the caller can report or retry them.
+func (bulkVersionCreation *BulkVersionCreation) NewBulkPatch(bulkSearch *BulkSearch, patchFunc func(result *BulkSearchResult) []*PatchOperation) (*BulkPatch, []*BulkCreatePropertyVersion) {
+	var failed []*BulkCreatePropertyVersion
+	for _, created := range bulkVersionCreation.CreatePropertyVersions {
+		if created.PropertyVersion == 0 {
+			failed = append(failed, created)
+		}
+	}
+
+	bulkPatch := NewBulkPatch()
+	for _, patchVersion := range bulkSearch.NewBulkPatch(patchFunc).PatchPropertyVersions {
+		for _, created := range bulkVersionCreation.CreatePropertyVersions {
+			if created.PropertyID == patchVersion.PropertyID && created.CreateFromVersion == patchVersion.PropertyVersion && created.PropertyVersion != 0 {
+				patchVersion.PropertyVersion = created.PropertyVersion
+				patchVersion.Etag = created.Etag
+				bulkPatch.PatchPropertyVersions = append(bulkPatch.PatchPropertyVersions, patchVersion)
+				break
+			}
+		}
+	}
+
+	return bulkPatch, failed
+}
+
+// BulkPatch represents a bulk rule tree patch request
+type BulkPatch struct {
+	client.Resource
+	BulkPatchID           int                         `json:"bulkPatchId,omitempty"`
+	BulkPatchStatus       BulkStatusValue             `json:"bulkPatchStatus,omitempty"`
+	SubmitDate            string                      `json:"submitDate,omitempty"`
+	UpdateDate            string                      `json:"updateDate,omitempty"`
+	PatchPropertyVersions []*BulkPatchPropertyVersion `json:"patchPropertyVersions"`
+	StatusChange          chan bool                   `json:"-"`
+}
+
+// BulkPatchPropertyVersion represents the patches applied to a single property version
+type BulkPatchPropertyVersion struct {
+	PropertyID                 string            `json:"propertyId"`
+	PropertyName               string            `json:"propertyName,omitempty"`
+	PropertyVersion            int               `json:"propertyVersion"`
+	Etag                       string            `json:"etag,omitempty"`
+	Patches                    []*PatchOperation `json:"patches,omitempty"`
+	PatchPropertyVersionStatus BulkStatusValue   `json:"patchPropertyVersionStatus,omitempty"`
+	FailureCause               string            `json:"failureCause,omitempty"`
+}
+
+// NewBulkPatch creates a new BulkPatch
+func NewBulkPatch() *BulkPatch {
+	bulkPatch := &BulkPatch{}
+	bulkPatch.Init()
+
+	return bulkPatch
+}
+
+func (bulkPatch *BulkPatch) Init() {
+	bulkPatch.Complete = make(chan bool, 1)
+	bulkPatch.StatusChange = make(chan bool, 1)
+}
+
+// Save submits the bulk patch request
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#postbulkpatch
+// Endpoint: POST /papi/v1/bulk/rules-patch-requests{?contractId,groupId}
+func (bulkPatch *BulkPatch) Save(contract *Contract, group *Group) error {
+	id, err := bulkSubmit(
+		"/papi/v1/bulk/rules-patch-requests"+bulkQueryString(contract, group),
+		client.JSONBody{"patchPropertyVersions": bulkPatch.PatchPropertyVersions},
+		"bulkPatchLink",
+	)
+	if err != nil {
+		return err
+	}
+
+	bulkPatch.BulkPatchID = id
+
+	_, err = bulkPatch.GetBulkPatch()
+	return err
+}
+
+// GetBulkPatch populates the BulkPatch with its current status
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getbulkpatch
+// Endpoint: GET /papi/v1/bulk/rules-patch-requests/{bulkPatchId}
+func (bulkPatch *BulkPatch) GetBulkPatch() (time.Duration, error) {
+	err := bulkGet(
+		fmt.Sprintf("/papi/v1/bulk/rules-patch-requests/%d", bulkPatch.BulkPatchID),
+		bulkPatch,
+	)
+
+	return time.Duration(10 * time.Second), err
+}
+
+// PollStatus will responsibly poll till the patches are applied or an error occurs
+//
+// See: BulkSearch.PollStatus()
+func (bulkPatch *BulkPatch) PollStatus() bool {
+	currentStatus := bulkPatch.BulkPatchStatus
+	var retry time.Duration = 0
+
+	for !currentStatus.IsDone() {
+		time.Sleep(retry)
+
+		var err error
+		retry, err = bulkPatch.GetBulkPatch()
+		if err != nil {
+			bulkPatch.StatusChange <- false
+			return false
+		}
+
+		if currentStatus != bulkPatch.BulkPatchStatus {
+			currentStatus = bulkPatch.BulkPatchStatus
+			bulkPatch.StatusChange <- true
+		}
+	}
+
+	return currentStatus == BulkStatusComplete
+}
+
+// NewBulkActivation creates a BulkActivation for every successfully patched
+// property version
+func (bulkPatch *BulkPatch) NewBulkActivation(network NetworkValue, note string) *BulkActivation {
+	bulkActivation := NewBulkActivation()
+	for _, patchVersion := range bulkPatch.PatchPropertyVersions {
+		if patchVersion.PatchPropertyVersionStatus != BulkStatusUpdated {
+			continue
+		}
+
+		bulkActivation.ActivatePropertyVersions = append(
+			bulkActivation.ActivatePropertyVersions,
+			&BulkActivatePropertyVersion{
+				PropertyID:      patchVersion.PropertyID,
+				PropertyVersion: patchVersion.PropertyVersion,
+				Network:         network,
+				Note:            note,
+			},
+		)
+	}
+
+	return bulkActivation
+}
+
+// BulkActivation represents a bulk property activation request
+type BulkActivation struct {
+	client.Resource
+	BulkActivationID          int                            `json:"bulkActivationId,omitempty"`
+	BulkActivationStatus      BulkStatusValue                `json:"bulkActivationStatus,omitempty"`
+	SubmitDate                string                         `json:"submitDate,omitempty"`
+	UpdateDate                string                         `json:"updateDate,omitempty"`
+	DefaultActivationSettings *BulkActivationSettings        `json:"defaultActivationSettings,omitempty"`
+	ActivatePropertyVersions  []*BulkActivatePropertyVersion `json:"activatePropertyVersions"`
+	StatusChange              chan bool                      `json:"-"`
+}
+
+// BulkActivationSettings represents the settings shared by all activations in a BulkActivation
+type BulkActivationSettings struct {
+	NotifyEmails           []string `json:"notifyEmails,omitempty"`
+	AcknowledgeAllWarnings bool     `json:"acknowledgeAllWarnings"`
+	FastPush               bool     `json:"fastPush"`
+	UseFastFallback        bool     `json:"useFastFallback"`
+}
+
+// BulkActivatePropertyVersion represents a single activation within a BulkActivation
+type BulkActivatePropertyVersion struct {
+	PropertyID       string          `json:"propertyId"`
+	PropertyName     string          `json:"propertyName,omitempty"`
+	PropertyVersion  int             `json:"propertyVersion"`
+	Network          NetworkValue    `json:"network"`
+	Note             string          `json:"note,omitempty"`
+	NotifyEmails     []string        `json:"notifyEmails,omitempty"`
+	ActivationID     string          `json:"activationId,omitempty"`
+	ActivationStatus StatusValue     `json:"activationStatus,omitempty"`
+	TaskStatus       BulkStatusValue `json:"taskStatus,omitempty"`
+	FatalError       string          `json:"fatalError,omitempty"`
+}
+
+// NewBulkActivation creates a new BulkActivation
+func NewBulkActivation() *BulkActivation {
+	bulkActivation := &BulkActivation{
+		DefaultActivationSettings: &BulkActivationSettings{
+			AcknowledgeAllWarnings: true,
+		},
+	}
+	bulkActivation.Init()
+
+	return bulkActivation
+}
+
+func (bulkActivation *BulkActivation) Init() {
+	bulkActivation.Complete = make(chan bool, 1)
+	bulkActivation.StatusChange = make(chan bool, 1)
+}
+
+// Save submits the bulk activation request
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#postbulkactivations
+// Endpoint: POST /papi/v1/bulk/activations{?contractId,groupId}
+func (bulkActivation *BulkActivation) Save(contract *Contract, group *Group) error {
+	id, err := bulkSubmit(
+		"/papi/v1/bulk/activations"+bulkQueryString(contract, group),
+		client.JSONBody{
+			"defaultActivationSettings": bulkActivation.DefaultActivationSettings,
+			"activatePropertyVersions":  bulkActivation.ActivatePropertyVersions,
+		},
+		"bulkActivationLink",
+	)
+	if err != nil {
+		return err
+	}
+
+	bulkActivation.BulkActivationID = id
+
+	_, err = bulkActivation.GetBulkActivation()
+	return err
+}
+
+// GetBulkActivation populates the BulkActivation with its current status
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getbulkactivation
+// Endpoint: GET /papi/v1/bulk/activations/{bulkActivationId}
+func (bulkActivation *BulkActivation) GetBulkActivation() (time.Duration, error) {
+	err := bulkGet(
+		fmt.Sprintf("/papi/v1/bulk/activations/%d", bulkActivation.BulkActivationID),
+		bulkActivation,
+	)
+
+	return time.Duration(30 * time.Second), err
+}
+
+// PollStatus will responsibly poll till all activations are complete or an error occurs
+//
+// See: BulkSearch.PollStatus()
+func (bulkActivation *BulkActivation) PollStatus() bool {
+	currentStatus := bulkActivation.BulkActivationStatus
+	var retry time.Duration = 0
+
+	for !currentStatus.IsDone() {
+		time.Sleep(retry)
+
+		var err error
+		retry, err = bulkActivation.GetBulkActivation()
+		if err != nil {
+			bulkActivation.StatusChange <- false
+			return false
+		}
+
+		if currentStatus != bulkActivation.BulkActivationStatus {
+			currentStatus = bulkActivation.BulkActivationStatus
+			bulkActivation.StatusChange <- true
+		}
+	}
+
+	return currentStatus == BulkStatusComplete
+}
+
+func bulkQueryString(contract *Contract, group *Group) string {
+	query := url.Values{}
+	if contract != nil && contract.ContractID != "" {
+		query.Set("contractId", contract.ContractID)
+	}
+
+	if group != nil && group.GroupID != "" {
+		query.Set("groupId", group.GroupID)
+	}
+
+	if len(query) == 0 {
+		return ""
+	}
+
+	return "?" + query.Encode()
+}
+
+// bulkSubmit POSTs a bulk request and returns the ID from the returned link
+func bulkSubmit(endpoint string, body interface{}, linkKey string) (int, error) {
+	req, err := client.NewJSONRequest(Config, "POST", endpoint, body)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return 0, err
+	}
+
+	if client.IsError(res) {
+		return 0, client.NewAPIError(res)
+	}
+
+	var location client.JSONBody
+	if err = client.BodyJSON(res, &location); err != nil {
+		return 0, err
+	}
+
+	link, ok := location[linkKey].(string)
+	if !ok {
+		return 0, fmt.Errorf("Bulk request response is missing \"%s\"", linkKey)
+	}
+
+	linkURL, err := url.Parse(link)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(path.Base(linkURL.Path))
+}
+
+func bulkGet(endpoint string, data interface{}) error {
+	req, err := client.NewRequest(Config, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	return client.BodyJSON(res, data)
+}
+
+// BulkStatusValue is used to create an "enum" of possible bulk request status values
+type BulkStatusValue string
+
+// IsDone determines if a bulk request has stopped processing
+func (status BulkStatusValue) IsDone() bool {
+	return status != "" && status != BulkStatusPending && status != BulkStatusSubmitted && status != BulkStatusInProgress
+}
+
+// BulkSearchSyntaxValue is used to create an "enum" of possible BulkSearchQuery.Syntax values
+type BulkSearchSyntaxValue string
+
+const (
+	// BulkStatusPending bulk status value PENDING
+	BulkStatusPending BulkStatusValue = "PENDING"
+	// BulkStatusSubmitted bulk status value SUBMITTED
+	BulkStatusSubmitted BulkStatusValue = "SUBMITTED"
+	// BulkStatusInProgress bulk status value IN_PROGRESS
+	BulkStatusInProgress BulkStatusValue = "IN_PROGRESS"
+	// BulkStatusComplete bulk status value COMPLETE
+	BulkStatusComplete BulkStatusValue = "COMPLETE"
+	// BulkStatusUpdated bulk status value UPDATED, for a successfully patched version
+	BulkStatusUpdated BulkStatusValue = "UPDATED"
+	// BulkStatusFailed bulk status value FAILED
+	BulkStatusFailed BulkStatusValue = "FAILED"
+
+	// BulkSearchSyntaxJSONPath BulkSearchQuery.Syntax value JSONPATH
+	BulkSearchSyntaxJSONPath BulkSearchSyntaxValue = "JSONPATH"
+)
@@ -0,0 +1,116 @@
+package papi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestBulkSearch_Save(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/papi/v1/bulk/rules-search-requests").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		HeaderPresent("Authorization").
+		Reply(202).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"bulkSearchLink": "/papi/v1/bulk/rules-search-requests/5?contractId=ctr_1&groupId=grp_1"}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/bulk/rules-search-requests/5").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"bulkSearchId": 5,
+			"searchTargetStatus": "COMPLETE",
+			"searchSubmitDate": "2018-01-18T00:00:00Z",
+			"searchUpdateDate": "2018-01-18T00:01:00Z",
+			"bulkSearchQuery": {
+				"syntax": "JSONPATH",
+				"match": "$..behaviors[?(@.name == 'origin')].options.hostname"
+			},
+			"results": [
+				{
+					"accountId": "act_1",
+					"propertyId": "prp_1",
+					"propertyName": "example.com",
+					"propertyVersion": 3,
+					"isLatest": true,
+					"isLocked": false,
+					"isSecure": true,
+					"productionStatus": "ACTIVE",
+					"stagingStatus": "INACTIVE",
+					"matchLocations": ["/rules/behaviors/0/options/hostname"]
+				}
+			]
+		}`)
+
+	Init(config)
+
+	contract := NewContract(NewContracts())
+	contract.ContractID = "ctr_1"
+	group := NewGroup(NewGroups())
+	group.GroupID = "grp_1"
+
+	bulkSearch := NewBulkSearch("$..behaviors[?(@.name == 'origin')].options.hostname")
+	err := bulkSearch.Save(contract, group)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, bulkSearch.BulkSearchID)
+	assert.Equal(t, BulkStatusComplete, bulkSearch.SearchTargetStatus)
+	assert.True(t, bulkSearch.SearchTargetStatus.IsDone())
+	assert.Len(t, bulkSearch.Results, 1)
+	assert.Equal(t, []string{"/rules/behaviors/0/options/hostname"}, bulkSearch.Results[0].MatchLocations)
+
+	bulkPatch := bulkSearch.NewBulkPatch(func(result *BulkSearchResult) []*PatchOperation {
+		var patches []*PatchOperation
+		for _, location := range result.MatchLocations {
+			patches = append(patches, NewPatchOperation(PatchOpReplace, location, "origin.example.com"))
+		}
+		return patches
+	})
+
+	assert.Len(t, bulkPatch.PatchPropertyVersions, 1)
+	assert.Equal(t, "prp_1", bulkPatch.PatchPropertyVersions[0].PropertyID)
+	assert.Equal(t, 3, bulkPatch.PatchPropertyVersions[0].PropertyVersion)
+	assert.Equal(t, PatchOpReplace, bulkPatch.PatchPropertyVersions[0].Patches[0].Op)
+
+	bulkPatch.PatchPropertyVersions[0].PatchPropertyVersionStatus = BulkStatusUpdated
+	bulkActivation := bulkPatch.NewBulkActivation(NetworkStaging, "Rotate origin")
+	assert.Len(t, bulkActivation.ActivatePropertyVersions, 1)
+	assert.Equal(t, NetworkStaging, bulkActivation.ActivatePropertyVersions[0].Network)
+}
+
+func TestBulkVersionCreation_NewBulkPatch(t *testing.T) {
+	bulkSearch := NewBulkSearch("$..behaviors[?(@.name == 'origin')]")
+	bulkSearch.Results = []*BulkSearchResult{
+		{PropertyID: "prp_1", PropertyVersion: 3},
+		{PropertyID: "prp_2", PropertyVersion: 5},
+	}
+
+	bulkVersionCreation := NewBulkVersionCreation()
+	bulkVersionCreation.CreatePropertyVersions = []*BulkCreatePropertyVersion{
+		{PropertyID: "prp_1", CreateFromVersion: 3, PropertyVersion: 4, Etag: "etag-4", CreateVersionStatus: BulkStatusComplete},
+		{PropertyID: "prp_2", CreateFromVersion: 5, CreateVersionStatus: BulkStatusFailed, FailureCause: "Version limit reached"},
+	}
+
+	bulkPatch, failed := bulkVersionCreation.NewBulkPatch(bulkSearch, func(result *BulkSearchResult) []*PatchOperation {
+		return []*PatchOperation{{Op: PatchOpReplace, Path: "/rules/options/is_secure", Value: true}}
+	})
+
+	if assert.Len(t, bulkPatch.PatchPropertyVersions, 1) {
+		assert.Equal(t, "prp_1", bulkPatch.PatchPropertyVersions[0].PropertyID)
+		assert.Equal(t, 4, bulkPatch.PatchPropertyVersions[0].PropertyVersion)
+		assert.Equal(t, "etag-4", bulkPatch.PatchPropertyVersions[0].Etag)
+	}
+	if assert.Len(t, failed, 1) {
+		assert.Equal(t, "prp_2", failed[0].PropertyID)
+		assert.Equal(t, "Version limit reached", failed[0].FailureCause)
+	}
+}
@@ -0,0 +1,149 @@
+package papi
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AccountCpCodes is a cached index of the CP codes of every contract and
+// group in the account
+//
+// CpCodes.FindCpCode() only searches a single contract and group; AccountCpCodes
+// searches them all. The listing is retrieved on first use and again once it
+// is older than TTL. A TTL of zero caches the listing until Invalidate() is
+// called.
+type AccountCpCodes struct {
+	TTL time.Duration
+
+	mutex    sync.Mutex
+	loadedAt time.Time
+	byID     map[string]*CpCode
+	byName   map[string][]*CpCode
+}
+
+// NewAccountCpCodes creates a new AccountCpCodes
+func NewAccountCpCodes(ttl time.Duration) *AccountCpCodes {
+	return &AccountCpCodes{TTL: ttl}
+}
+
+// FindByID finds a CP code by ID, with or without the "cpc_" prefix
+//
+// nil is returned if the CP code is not in the account.
+func (index *AccountCpCodes) FindByID(cpcodeID string) (*CpCode, error) {
+	if err := index.load(); err != nil {
+		return nil, err
+	}
+
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	if !strings.HasPrefix(cpcodeID, "cpc_") {
+		cpcodeID = "cpc_" + cpcodeID
+	}
+
+	return index.byID[cpcodeID], nil
+}
+
+// FindByName finds the CP codes with a name, ignoring case
+//
+// CP code names are not unique, so every match is returned.
+func (index *AccountCpCodes) FindByName(name string) ([]*CpCode, error) {
+	if err := index.load(); err != nil {
+		return nil, err
+	}
+
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	return index.byName[strings.ToLower(name)], nil
+}
+
+// Add adds a CP code to the index, e.g. after creating it with CpCode.Save()
+func (index *AccountCpCodes) Add(cpcode *CpCode) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	if index.byID == nil {
+		return
+	}
+
+	index.add(cpcode)
+}
+
+// Invalidate discards the cached listing
+func (index *AccountCpCodes) Invalidate() {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	index.byID = nil
+	index.byName = nil
+}
+
+// Refresh retrieves the CP codes of every contract and group
+//
+// API Docs: https://developer.akamai.com/api/luna/papi/resources.html#listcpcodes
+// Endpoint: GET /papi/v1/cpcodes/{?contractId,groupId}
+func (index *AccountCpCodes) Refresh() error {
+	contracts, err := GetContracts()
+	if err != nil {
+		return err
+	}
+
+	groups, err := GetGroups()
+	if err != nil {
+		return err
+	}
+
+	var cpcodes []*CpCode
+	for _, group := range groups.Groups.Items {
+		for _, contractID := range group.ContractIDs {
+			contract, err := contracts.FindContract(contractID)
+			if err != nil {
+				continue
+			}
+
+			groupCpCodes, err := GetCpCodes(contract, group)
+			if err != nil {
+				return fmt.Errorf("unable to list CP codes of %s/%s: %s", contractID, group.GroupID, err)
+			}
+
+			cpcodes = append(cpcodes, groupCpCodes.CpCodes.Items...)
+		}
+	}
+
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	index.byID = map[string]*CpCode{}
+	index.byName = map[string][]*CpCode{}
+	for _, cpcode := range cpcodes {
+		index.add(cpcode)
+	}
+	index.loadedAt = time.Now()
+
+	return nil
+}
+
+func (index *AccountCpCodes) add(cpcode *CpCode) {
+	if _, ok := index.byID[cpcode.CpcodeID]; ok {
+		return
+	}
+
+	index.byID[cpcode.CpcodeID] = cpcode
+	name := strings.ToLower(cpcode.CpcodeName)
+	index.byName[name] = append(index.byName[name], cpcode)
+}
+
+func (index *AccountCpCodes) load() error {
+	index.mutex.Lock()
+	fresh := index.byID != nil && (index.TTL == 0 || time.Since(index.loadedAt) < index.TTL)
+	index.mutex.Unlock()
+
+	if fresh {
+		return nil
+	}
+
+	return index.Refresh()
+}
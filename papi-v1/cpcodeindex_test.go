@@ -0,0 +1,207 @@
+package papi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockAccountCpCodes(cpcodes string) {
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/contracts").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"contracts": {"items": [{"contractId": "ctr_1"}, {"contractId": "ctr_2"}]}}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/groups").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"groups": {"items": [{"groupId": "grp_1", "contractIds": ["ctr_1"]}, {"groupId": "grp_2", "contractIds": ["ctr_2"]}]}}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/cpcodes").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"cpcodes": {"items": [{"cpcodeId": "cpc_1", "cpcodeName": "Media"}]}}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/cpcodes").
+		MatchParam("contractId", "ctr_2").
+		MatchParam("groupId", "grp_2").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(cpcodes)
+}
+
+func TestAccountCpCodes_Find(t *testing.T) {
+	defer gock.Off()
+
+	mockAccountCpCodes(`{"cpcodes": {"items": [{"cpcodeId": "cpc_2", "cpcodeName": "media"}, {"cpcodeId": "cpc_3", "cpcodeName": "API"}]}}`)
+
+	Init(config)
+
+	index := NewAccountCpCodes(time.Hour)
+
+	cpcode, err := index.FindByID("2")
+	assert.NoError(t, err)
+	if assert.NotNil(t, cpcode) {
+		assert.Equal(t, "media", cpcode.CpcodeName)
+	}
+	assert.True(t, gock.IsDone())
+
+	// Later lookups are served from the cache without further requests
+	cpcode, err = index.FindByID("cpc_3")
+	assert.NoError(t, err)
+	if assert.NotNil(t, cpcode) {
+		assert.Equal(t, "API", cpcode.CpcodeName)
+	}
+
+	cpcode, err = index.FindByID("cpc_4")
+	assert.NoError(t, err)
+	assert.Nil(t, cpcode)
+
+	cpcodes, err := index.FindByName("MEDIA")
+	assert.NoError(t, err)
+	assert.Len(t, cpcodes, 2)
+
+	index.Add(&CpCode{CpcodeID: "cpc_4", CpcodeName: "Media"})
+	cpcodes, err = index.FindByName("media")
+	assert.NoError(t, err)
+	assert.Len(t, cpcodes, 3)
+}
+
+func TestAccountCpCodes_TTL(t *testing.T) {
+	defer gock.Off()
+
+	mockAccountCpCodes(`{"cpcodes": {"items": []}}`)
+	mockAccountCpCodes(`{"cpcodes": {"items": [{"cpcodeId": "cpc_2", "cpcodeName": "API"}]}}`)
+
+	Init(config)
+
+	index := NewAccountCpCodes(time.Minute)
+
+	cpcodes, err := index.FindByName("api")
+	assert.NoError(t, err)
+	assert.Len(t, cpcodes, 0)
+	assert.False(t, gock.IsDone())
+
+	cpcodes, err = index.FindByName("api")
+	assert.NoError(t, err)
+	assert.Len(t, cpcodes, 0)
+	assert.False(t, gock.IsDone())
+
+	// Once older than the TTL the listing is retrieved again
+	index.loadedAt = time.Now().Add(-2 * time.Minute)
+
+	cpcodes, err = index.FindByName("api")
+	assert.NoError(t, err)
+	assert.Len(t, cpcodes, 1)
+	assert.True(t, gock.IsDone())
+}
+
+func TestAccountCpCodes_Invalidate(t *testing.T) {
+	defer gock.Off()
+
+	mockAccountCpCodes(`{"cpcodes": {"items": []}}`)
+	mockAccountCpCodes(`{"cpcodes": {"items": [{"cpcodeId": "cpc_2", "cpcodeName": "API"}]}}`)
+
+	Init(config)
+
+	index := NewAccountCpCodes(0)
+
+	cpcode, err := index.FindByID("cpc_2")
+	assert.NoError(t, err)
+	assert.Nil(t, cpcode)
+
+	// Add is ignored until the listing has been retrieved again
+	index.Invalidate()
+	index.Add(&CpCode{CpcodeID: "cpc_5", CpcodeName: "Media"})
+
+	cpcode, err = index.FindByID("cpc_2")
+	assert.NoError(t, err)
+	assert.NotNil(t, cpcode)
+	assert.True(t, gock.IsDone())
+
+	cpcode, err = index.FindByID("cpc_5")
+	assert.NoError(t, err)
+	assert.Nil(t, cpcode)
+}
+
+func TestAccountCpCodes_Refresh(t *testing.T) {
+	defer gock.Off()
+
+	mockAccountCpCodes(`{"cpcodes": {"items": [{"cpcodeId": "cpc_2", "cpcodeName": "API"}]}}`)
+	mockAccountCpCodes(`{"cpcodes": {"items": []}}`)
+
+	Init(config)
+
+	index := NewAccountCpCodes(0)
+	assert.NoError(t, index.Refresh())
+
+	cpcode, err := index.FindByID("cpc_2")
+	assert.NoError(t, err)
+	assert.NotNil(t, cpcode)
+	assert.False(t, gock.IsDone())
+
+	// Refresh retrieves the listing even when the cache is fresh
+	assert.NoError(t, index.Refresh())
+	assert.True(t, gock.IsDone())
+
+	cpcode, err = index.FindByID("cpc_2")
+	assert.NoError(t, err)
+	assert.Nil(t, cpcode)
+	cpcode, err = index.FindByID("cpc_1")
+	assert.NoError(t, err)
+	assert.NotNil(t, cpcode)
+}
+
+func TestAccountCpCodes_Refresh_Error(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/contracts").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"contracts": {"items": [{"contractId": "ctr_1"}]}}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/groups").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"groups": {"items": [{"groupId": "grp_1", "contractIds": ["ctr_1"]}]}}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/cpcodes").
+		HeaderPresent("Authorization").
+		Reply(403).
+		SetHeader("Content-Type", "application/problem+json").
+		BodyString(`{"type": "https://problems.luna.akamaiapis.net/papi/v0/forbidden", "title": "Forbidden", "detail": "no access", "status": 403}`)
+
+	Init(config)
+
+	index := NewAccountCpCodes(0)
+	cpcode, err := index.FindByID("cpc_1")
+	assert.Nil(t, cpcode)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unable to list CP codes of ctr_1/grp_1")
+	}
+	assert.True(t, gock.IsDone())
+}
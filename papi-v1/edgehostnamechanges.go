@@ -0,0 +1,348 @@
+package papi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/cps-v2"
+)
+
+// EdgeHostnameChange is a change request made to an existing edge hostname
+//
+// Edge hostnames can only be created by PAPI; they are modified and deleted
+// using the Edge Hostnames API, which processes changes asynchronously.
+//
+// API Docs: https://developer.akamai.com/api/core_features/edge_hostnames/v1.html#changerequest
+type EdgeHostnameChange struct {
+	ChangeID         int                           `json:"changeId"`
+	Action           string                        `json:"action"`
+	Status           EdgeHostnameChangeStatusValue `json:"status"`
+	StatusMessage    string                        `json:"statusMessage,omitempty"`
+	StatusUpdateDate string                        `json:"statusUpdateDate,omitempty"`
+	SubmitDate       string                        `json:"submitDate,omitempty"`
+	Submitter        string                        `json:"submitter,omitempty"`
+}
+
+// edgeHostnamePatch is a JSON Patch operation supported by the Edge Hostnames API
+type edgeHostnamePatch struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value string `json:"value"`
+}
+
+// Update modifies the TTL and IP version behavior of an edge hostname
+//
+// A ttl of 0 or an empty ipVersionBehavior leaves the current value unchanged.
+// See IPVersionIPv4 and IPVersionIPv6Compliance for ipVersionBehavior values.
+//
+// API Docs: https://developer.akamai.com/api/core_features/edge_hostnames/v1.html#patchedgehostname
+// Endpoint: PATCH /hapi/v1/dns-zones/{dnsZone}/edge-hostnames/{recordName}
+func (edgeHostname *EdgeHostname) Update(ttl int, ipVersionBehavior string) (*EdgeHostnameChange, error) {
+	var patches []*edgeHostnamePatch
+	if ttl != 0 {
+		patches = append(patches, &edgeHostnamePatch{Op: "replace", Path: "/ttl", Value: strconv.Itoa(ttl)})
+	}
+	if ipVersionBehavior != "" {
+		patches = append(patches, &edgeHostnamePatch{Op: "replace", Path: "/ipVersionBehavior", Value: ipVersionBehavior})
+	}
+
+	if len(patches) == 0 {
+		return nil, fmt.Errorf("no changes given for edge hostname %s", edgeHostname.domain())
+	}
+
+	hapiPath, err := edgeHostname.hapiPath()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := client.NewJSONRequest(Config, "PATCH", hapiPath, patches)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json-patch+json")
+
+	change, err := doEdgeHostnameChange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if ipVersionBehavior != "" {
+		edgeHostname.IPVersionBehavior = ipVersionBehavior
+	}
+
+	return change, nil
+}
+
+// Delete removes an edge hostname
+//
+// The edge hostname must not be used by any property hostname.
+//
+// See: EdgeHostname.DeleteIfUnused()
+// API Docs: https://developer.akamai.com/api/core_features/edge_hostnames/v1.html#deleteedgehostname
+// Endpoint: DELETE /hapi/v1/dns-zones/{dnsZone}/edge-hostnames/{recordName}
+func (edgeHostname *EdgeHostname) Delete() (*EdgeHostnameChange, error) {
+	hapiPath, err := edgeHostname.hapiPath()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := client.NewRequest(Config, "DELETE", hapiPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return doEdgeHostnameChange(req)
+}
+
+// DeleteIfUnused removes an edge hostname if no property in the inventory uses it
+//
+// Besides the indexed versions, the hostnames of the versions currently
+// active on production and staging are retrieved for every property in the
+// inventory, as activations may have happened since it was built. The edge
+// hostname is not deleted if any of them cannot be retrieved.
+//
+// See: BuildInventory()
+func (edgeHostname *EdgeHostname) DeleteIfUnused(inventory *Inventory) (*EdgeHostnameChange, error) {
+	domain := normalizeInventoryHostname(edgeHostname.domain())
+
+	properties := inventory.FindByEdgeHostname(domain)
+	if len(properties) == 0 {
+		inventory.mutex.RLock()
+		entries := make([]*InventoryProperty, 0, len(inventory.Properties))
+		for _, entry := range inventory.Properties {
+			entries = append(entries, entry)
+		}
+		inventory.mutex.RUnlock()
+
+		sort.Slice(entries, func(i, j int) bool { return entries[i].PropertyID < entries[j].PropertyID })
+
+		for _, entry := range entries {
+			used, err := activeVersionsUseEdgeHostname(entry, domain)
+			if err != nil {
+				return nil, fmt.Errorf("unable to check whether property %s uses edge hostname %s: %s", entry.PropertyName, edgeHostname.domain(), err)
+			}
+
+			if used {
+				properties = append(properties, entry)
+			}
+		}
+	}
+
+	if len(properties) != 0 {
+		names := make([]string, 0, len(properties))
+		for _, property := range properties {
+			names = append(names, property.PropertyName)
+		}
+
+		return nil, fmt.Errorf("edge hostname %s is used by %s", edgeHostname.domain(), strings.Join(names, ", "))
+	}
+
+	return edgeHostname.Delete()
+}
+
+// activeVersionsUseEdgeHostname retrieves the hostnames of the versions of a
+// property active on production and staging and reports whether any of them
+// points to an edge hostname
+func activeVersionsUseEdgeHostname(entry *InventoryProperty, domain string) (bool, error) {
+	property := NewProperty(NewProperties())
+	property.PropertyID = entry.PropertyID
+	property.PropertyName = entry.PropertyName
+	property.ContractID = entry.ContractID
+	property.GroupID = entry.GroupID
+	property.Contract = NewContract(NewContracts())
+	property.Contract.ContractID = entry.ContractID
+	property.Group = NewGroup(NewGroups())
+	property.Group.GroupID = entry.GroupID
+
+	versions, err := property.GetVersions()
+	if err != nil {
+		return false, err
+	}
+
+	checked := map[int]bool{}
+	for _, network := range []NetworkValue{NetworkProduction, NetworkStaging} {
+		version := versions.GetActiveVersion(network)
+		if version == nil || checked[version.PropertyVersion] {
+			continue
+		}
+		checked[version.PropertyVersion] = true
+
+		hostnames, err := property.GetHostnames(version)
+		if err != nil {
+			return false, err
+		}
+
+		for _, hostname := range hostnames.Hostnames.Items {
+			if normalizeInventoryHostname(hostname.CnameTo) == domain {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// LinkCertEnrollment sets the CPS enrollment used by a secure edge hostname
+//
+// The certificate of the enrollment must cover every hostname that will
+// point to the edge hostname. Only edge hostnames that have not been saved
+// can be linked, as the enrollment of an existing edge hostname cannot be
+// changed.
+//
+// See: ValidateCertificateCoverage()
+func (edgeHostname *EdgeHostname) LinkCertEnrollment(enrollmentID int, enrollment *cps.Enrollment, hostnames []string) error {
+	if edgeHostname.EdgeHostnameID != "" {
+		return fmt.Errorf("the certificate enrollment of existing edge hostname %s cannot be changed", edgeHostname.EdgeHostnameID)
+	}
+
+	if err := ValidateCertificateCoverage(enrollment, hostnames); err != nil {
+		return err
+	}
+
+	edgeHostname.Secure = true
+	edgeHostname.CertEnrollmentId = enrollmentID
+	if edgeHostname.SecureNetwork == "" {
+		edgeHostname.SecureNetwork = SecureNetworkEnhancedTLS
+	}
+	if edgeHostname.DomainSuffix == "" {
+		edgeHostname.DomainSuffix = "edgekey.net"
+	}
+
+	return nil
+}
+
+// ValidateCertificateCoverage checks that the common name or SANs of an
+// enrollment cover every hostname
+//
+// Wildcard names such as "*.example.com" cover a single label only.
+func ValidateCertificateCoverage(enrollment *cps.Enrollment, hostnames []string) error {
+	if enrollment == nil || enrollment.CertificateSigningRequest == nil {
+		return fmt.Errorf("the enrollment has no certificate signing request")
+	}
+
+	names := []string{enrollment.CertificateSigningRequest.CommonName}
+	if enrollment.CertificateSigningRequest.AlternativeNames != nil {
+		names = append(names, *enrollment.CertificateSigningRequest.AlternativeNames...)
+	}
+
+	var uncovered []string
+	for _, hostname := range hostnames {
+		covered := false
+		for _, name := range names {
+			if certificateNameCovers(name, hostname) {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			uncovered = append(uncovered, hostname)
+		}
+	}
+
+	if len(uncovered) != 0 {
+		return fmt.Errorf("the certificate does not cover %s", strings.Join(uncovered, ", "))
+	}
+
+	return nil
+}
+
+// certificateNameCovers reports whether a certificate name matches a hostname
+func certificateNameCovers(name string, hostname string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	hostname = strings.ToLower(strings.TrimSuffix(hostname, "."))
+
+	if name == hostname {
+		return true
+	}
+
+	if !strings.HasPrefix(name, "*.") {
+		return false
+	}
+
+	labels := strings.SplitN(hostname, ".", 2)
+
+	return len(labels) == 2 && labels[0] != "" && labels[1] == name[2:]
+}
+
+// GetEdgeHostnameChange retrieves the current state of an edge hostname change
+//
+// API Docs: https://developer.akamai.com/api/core_features/edge_hostnames/v1.html#getchangerequest
+// Endpoint: GET /hapi/v1/change-requests/{changeId}
+func GetEdgeHostnameChange(changeID int) (*EdgeHostnameChange, error) {
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf("/hapi/v1/change-requests/%d", changeID),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return doEdgeHostnameChange(req)
+}
+
+// domain returns the full edge hostname, e.g. www.example.com.edgesuite.net
+func (edgeHostname *EdgeHostname) domain() string {
+	if edgeHostname.EdgeHostnameDomain != "" {
+		return edgeHostname.EdgeHostnameDomain
+	}
+
+	return edgeHostname.DomainPrefix + "." + edgeHostname.DomainSuffix
+}
+
+// hapiPath returns the Edge Hostnames API path of an edge hostname
+func (edgeHostname *EdgeHostname) hapiPath() (string, error) {
+	recordName := edgeHostname.DomainPrefix
+	dnsZone := edgeHostname.DomainSuffix
+
+	if recordName == "" || dnsZone == "" {
+		for _, suffix := range []string{"edgesuite.net", "edgekey.net", "akamaized.net"} {
+			if strings.HasSuffix(edgeHostname.EdgeHostnameDomain, "."+suffix) {
+				recordName = strings.TrimSuffix(edgeHostname.EdgeHostnameDomain, "."+suffix)
+				dnsZone = suffix
+			}
+		}
+	}
+
+	if recordName == "" || dnsZone == "" {
+		return "", fmt.Errorf("unable to determine the DNS zone of edge hostname \"%s\"", edgeHostname.domain())
+	}
+
+	return fmt.Sprintf("/hapi/v1/dns-zones/%s/edge-hostnames/%s", dnsZone, recordName), nil
+}
+
+func doEdgeHostnameChange(req *http.Request) (*EdgeHostnameChange, error) {
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	change := &EdgeHostnameChange{}
+	if err = client.BodyJSON(res, change); err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// EdgeHostnameChangeStatusValue is used to create an "enum" of possible EdgeHostnameChange.Status values
+type EdgeHostnameChangeStatusValue string
+
+const (
+	// EdgeHostnameChangeStatusPending EdgeHostnameChange.Status value PENDING
+	EdgeHostnameChangeStatusPending EdgeHostnameChangeStatusValue = "PENDING"
+	// EdgeHostnameChangeStatusSucceeded EdgeHostnameChange.Status value SUCCEEDED
+	EdgeHostnameChangeStatusSucceeded EdgeHostnameChangeStatusValue = "SUCCEEDED"
+	// EdgeHostnameChangeStatusFailed EdgeHostnameChange.Status value FAILED
+	EdgeHostnameChangeStatusFailed EdgeHostnameChangeStatusValue = "FAILED"
+)
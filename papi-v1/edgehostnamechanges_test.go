@@ -0,0 +1,182 @@
+package papi
+
+import (
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/cps-v2"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestEdgeHostname_Update(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Patch("/hapi/v1/dns-zones/edgesuite.net/edge-hostnames/www.example.com").
+		MatchHeader("Content-Type", "application/json-patch\\+json").
+		JSON(`[
+			{"op": "replace", "path": "/ttl", "value": "300"},
+			{"op": "replace", "path": "/ipVersionBehavior", "value": "IPV6_COMPLIANCE"}
+		]`).
+		HeaderPresent("Authorization").
+		Reply(202).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"changeId": 42, "action": "EDIT", "status": "PENDING"}`)
+
+	Init(config)
+
+	edgeHostname := NewEdgeHostname(NewEdgeHostnames())
+	edgeHostname.EdgeHostnameDomain = "www.example.com.edgesuite.net"
+
+	change, err := edgeHostname.Update(300, IPVersionIPv6Compliance)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, change.ChangeID)
+	assert.Equal(t, EdgeHostnameChangeStatusPending, change.Status)
+	assert.Equal(t, IPVersionIPv6Compliance, edgeHostname.IPVersionBehavior)
+}
+
+func TestEdgeHostname_DeleteIfUnused(t *testing.T) {
+	inventory := NewInventory()
+	hostnames := NewHostnames()
+	hostname := hostnames.NewHostname()
+	hostname.CnameFrom = "www.example.com"
+	hostname.CnameTo = "www.example.com.edgesuite.net"
+
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_1"
+	property.PropertyName = "www.example.com"
+	inventory.AddProperty(property, hostnames, nil)
+
+	edgeHostname := NewEdgeHostname(NewEdgeHostnames())
+	edgeHostname.DomainPrefix = "www.example.com"
+	edgeHostname.DomainSuffix = "edgesuite.net"
+
+	_, err := edgeHostname.DeleteIfUnused(inventory)
+	assert.EqualError(t, err, "edge hostname www.example.com.edgesuite.net is used by www.example.com")
+}
+
+func mockEdgeHostnameActiveVersions(hostname string) {
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_1",
+			"versions": {
+				"items": [
+					{"propertyVersion": 3, "productionStatus": "INACTIVE", "stagingStatus": "INACTIVE"},
+					{"propertyVersion": 2, "productionStatus": "ACTIVE", "stagingStatus": "ACTIVE"}
+				]
+			}
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions/2/hostnames/").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"hostnames": {"items": [{"cnameFrom": "www.example.com", "cnameTo": "` + hostname + `"}]}}`)
+}
+
+func newEdgeHostnameTestInventory() *Inventory {
+	inventory := NewInventory()
+	hostnames := NewHostnames()
+	hostname := hostnames.NewHostname()
+	hostname.CnameFrom = "www.example.com"
+	hostname.CnameTo = "www.example.com.edgekey.net"
+
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_1"
+	property.PropertyName = "www.example.com"
+	property.ContractID = "ctr_1"
+	property.GroupID = "grp_1"
+	property.LatestVersion = 3
+	inventory.AddProperty(property, hostnames, nil)
+
+	return inventory
+}
+
+func TestEdgeHostname_DeleteIfUnused_ActiveVersion(t *testing.T) {
+	defer gock.Off()
+	mockEdgeHostnameActiveVersions("www.example.com.edgesuite.net")
+
+	Init(config)
+
+	edgeHostname := NewEdgeHostname(NewEdgeHostnames())
+	edgeHostname.EdgeHostnameDomain = "www.example.com.edgesuite.net"
+
+	_, err := edgeHostname.DeleteIfUnused(newEdgeHostnameTestInventory())
+	assert.EqualError(t, err, "edge hostname www.example.com.edgesuite.net is used by www.example.com")
+	assert.True(t, gock.IsDone())
+}
+
+func TestEdgeHostname_DeleteIfUnused_Unused(t *testing.T) {
+	defer gock.Off()
+	mockEdgeHostnameActiveVersions("www.example.com.edgekey.net")
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Delete("/hapi/v1/dns-zones/edgesuite.net/edge-hostnames/www.example.com").
+		HeaderPresent("Authorization").
+		Reply(202).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"changeId": 43, "action": "DELETE", "status": "PENDING"}`)
+
+	Init(config)
+
+	edgeHostname := NewEdgeHostname(NewEdgeHostnames())
+	edgeHostname.EdgeHostnameDomain = "www.example.com.edgesuite.net"
+
+	change, err := edgeHostname.DeleteIfUnused(newEdgeHostnameTestInventory())
+	assert.NoError(t, err)
+	assert.Equal(t, 43, change.ChangeID)
+	assert.True(t, gock.IsDone())
+}
+
+func TestEdgeHostname_DeleteIfUnused_CheckFails(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions").
+		HeaderPresent("Authorization").
+		Reply(500).
+		SetHeader("Content-Type", "application/problem+json").
+		BodyString(`{"type": "internal_error", "title": "Internal Server Error", "status": 500}`)
+
+	Init(config)
+
+	edgeHostname := NewEdgeHostname(NewEdgeHostnames())
+	edgeHostname.EdgeHostnameDomain = "www.example.com.edgesuite.net"
+
+	_, err := edgeHostname.DeleteIfUnused(newEdgeHostnameTestInventory())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to check whether property www.example.com uses edge hostname www.example.com.edgesuite.net")
+	assert.True(t, gock.IsDone())
+}
+
+func TestEdgeHostname_LinkCertEnrollment(t *testing.T) {
+	sans := []string{"www.example.com", "*.api.example.com"}
+	enrollment := &cps.Enrollment{
+		CertificateSigningRequest: &cps.CSR{CommonName: "example.com", AlternativeNames: &sans},
+	}
+
+	edgeHostname := NewEdgeHostname(NewEdgeHostnames())
+	edgeHostname.DomainPrefix = "www.example.com"
+
+	err := edgeHostname.LinkCertEnrollment(1234, enrollment, []string{"example.com", "www.example.com", "v1.api.example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1234, edgeHostname.CertEnrollmentId)
+	assert.True(t, edgeHostname.Secure)
+	assert.Equal(t, "edgekey.net", edgeHostname.DomainSuffix)
+
+	err = ValidateCertificateCoverage(enrollment, []string{"a.b.api.example.com", "static.example.com"})
+	assert.EqualError(t, err, "the certificate does not cover a.b.api.example.com, static.example.com")
+}
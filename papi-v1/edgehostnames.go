@@ -350,3 +350,17 @@ func (edgeHostname *EdgeHostname) PollStatus(options string) bool {
 
 	return true
 }
+
+const (
+	// IPVersionIPv4 EdgeHostname.IPVersionBehavior value IPV4
+	IPVersionIPv4 = "IPV4"
+	// IPVersionIPv6Compliance EdgeHostname.IPVersionBehavior value IPV6_COMPLIANCE (dual-stack)
+	IPVersionIPv6Compliance = "IPV6_COMPLIANCE"
+	// IPVersionIPv6Performance EdgeHostname.IPVersionBehavior value IPV6_PERFORMANCE
+	IPVersionIPv6Performance = "IPV6_PERFORMANCE"
+
+	// SecureNetworkEnhancedTLS EdgeHostname.SecureNetwork value ENHANCED_TLS
+	SecureNetworkEnhancedTLS = "ENHANCED_TLS"
+	// SecureNetworkStandardTLS EdgeHostname.SecureNetwork value STANDARD_TLS
+	SecureNetworkStandardTLS = "STANDARD_TLS"
+)
@@ -0,0 +1,270 @@
+package papi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// On-disk file names used by ExportProperty() and ImportProperty()
+const (
+	ExportPropertyFile  = "property.json"
+	ExportHostnamesFile = "hostnames.json"
+	ExportRulesFile     = "rules.json"
+)
+
+// PropertyExport represents the metadata of an exported property version
+//
+// See: ExportProperty()
+type PropertyExport struct {
+	AccountID       string `json:"accountId"`
+	ContractID      string `json:"contractId"`
+	GroupID         string `json:"groupId"`
+	PropertyID      string `json:"propertyId"`
+	PropertyName    string `json:"propertyName"`
+	PropertyVersion int    `json:"propertyVersion"`
+	ProductID       string `json:"productId"`
+	RuleFormat      string `json:"ruleFormat"`
+	Note            string `json:"note,omitempty"`
+}
+
+// ExportAccount writes every property in the account to dir
+//
+// Properties are written to {dir}/{contractId}/{groupId}/{propertyName}
+//
+// See: ExportProperty()
+func ExportAccount(dir string) error {
+	contracts, err := GetContracts()
+	if err != nil {
+		return err
+	}
+
+	groups, err := GetGroups()
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups.Groups.Items {
+		for _, contractID := range group.ContractIDs {
+			contract, err := contracts.FindContract(contractID)
+			if err != nil {
+				continue
+			}
+
+			properties, err := GetProperties(contract, group)
+			if err != nil {
+				return err
+			}
+
+			for _, property := range properties.Properties.Items {
+				propertyDir := filepath.Join(dir, contract.ContractID, group.GroupID, property.PropertyName)
+				if err := ExportProperty(property, propertyDir); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ExportProperty writes the latest version of a property to dir
+//
+// The metadata, hostnames and rule tree are written to ExportPropertyFile,
+// ExportHostnamesFile and ExportRulesFile as indented JSON. Hostnames are
+// sorted so that exporting an unchanged property produces identical files.
+func ExportProperty(property *Property, dir string) error {
+	rules, err := property.GetRules()
+	if err != nil {
+		return err
+	}
+
+	version := NewVersion(NewVersions())
+	version.PropertyVersion = property.LatestVersion
+
+	hostnames, err := property.GetHostnames(version)
+	if err != nil {
+		return err
+	}
+
+	items := hostnames.Hostnames.Items
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CnameFrom < items[j].CnameFrom
+	})
+
+	metadata := &PropertyExport{
+		AccountID:       property.AccountID,
+		ContractID:      property.ContractID,
+		GroupID:         property.GroupID,
+		PropertyID:      property.PropertyID,
+		PropertyName:    property.PropertyName,
+		PropertyVersion: property.LatestVersion,
+		ProductID:       property.ProductID,
+		RuleFormat:      rules.RuleFormat,
+		Note:            property.Note,
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	if err := writeExportFile(filepath.Join(dir, ExportPropertyFile), metadata); err != nil {
+		return err
+	}
+
+	if err := writeExportFile(filepath.Join(dir, ExportHostnamesFile), items); err != nil {
+		return err
+	}
+
+	return writeExportFile(filepath.Join(dir, ExportRulesFile), rules.Rule)
+}
+
+// ReadPropertyExport reads the metadata of a property exported to dir
+func ReadPropertyExport(dir string) (*PropertyExport, error) {
+	metadata := &PropertyExport{}
+	if err := readExportFile(filepath.Join(dir, ExportPropertyFile), metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// ImportProperty creates a new property in the given contract/group from a
+// property exported to dir
+//
+// The property is created with the exported product, then the exported
+// hostnames and rule tree are saved to its first version. If the export used a
+// frozen rule format, the rule tree is saved using that format.
+//
+// Edge hostname IDs are specific to an account, so hostnames are attached to
+// the edge hostname of the same name (Hostname.CnameTo) in the target contract
+// and group, which is created if it does not exist. To import into another
+// account, set Config.AccountKey before importing.
+func ImportProperty(dir string, contract *Contract, group *Group) (*Property, error) {
+	metadata, err := ReadPropertyExport(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var hostnameItems []*Hostname
+	if err := readExportFile(filepath.Join(dir, ExportHostnamesFile), &hostnameItems); err != nil {
+		return nil, err
+	}
+
+	rule := NewRule()
+	if err := readExportFile(filepath.Join(dir, ExportRulesFile), rule); err != nil {
+		return nil, err
+	}
+
+	property := NewProperty(NewProperties())
+	property.Contract = contract
+	property.Group = group
+	property.PropertyName = metadata.PropertyName
+	property.ProductID = metadata.ProductID
+	property.RuleFormat = metadata.RuleFormat
+	if err := property.Save(); err != nil {
+		return nil, err
+	}
+
+	if err := resolveEdgeHostnames(hostnameItems, contract, group, metadata.ProductID); err != nil {
+		return nil, err
+	}
+
+	hostnames := NewHostnames()
+	hostnames.PropertyID = property.PropertyID
+	hostnames.PropertyVersion = property.LatestVersion
+	hostnames.ContractID = contract.ContractID
+	hostnames.GroupID = group.GroupID
+	for _, hostname := range hostnameItems {
+		hostname.parent = hostnames
+	}
+	hostnames.Hostnames.Items = hostnameItems
+	if err := hostnames.Save(); err != nil {
+		return nil, err
+	}
+
+	rules := NewRules()
+	rules.PropertyID = property.PropertyID
+	rules.PropertyVersion = property.LatestVersion
+	rules.ContractID = contract.ContractID
+	rules.GroupID = group.GroupID
+	rules.Rule = rule
+
+	if metadata.RuleFormat != "" && metadata.RuleFormat != "latest" {
+		err = rules.Freeze(metadata.RuleFormat)
+	} else {
+		err = rules.Save()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return property, nil
+}
+
+// resolveEdgeHostnames sets the EdgeHostnameID of each hostname to the ID of
+// the edge hostname named by its CnameTo in the given contract and group,
+// creating missing edge hostnames with the given product
+func resolveEdgeHostnames(hostnameItems []*Hostname, contract *Contract, group *Group, productID string) error {
+	var edgeHostnames *EdgeHostnames
+	for _, hostname := range hostnameItems {
+		if hostname.CnameTo == "" {
+			continue
+		}
+
+		if edgeHostnames == nil {
+			var err error
+			if edgeHostnames, err = GetEdgeHostnames(contract, group, ""); err != nil {
+				return err
+			}
+			edgeHostnames.ContractID = contract.ContractID
+			edgeHostnames.GroupID = group.GroupID
+		}
+
+		wanted := NewEdgeHostname(edgeHostnames)
+		wanted.EdgeHostnameDomain = hostname.CnameTo
+		if existing, err := edgeHostnames.FindEdgeHostname(wanted); err == nil && existing != nil {
+			hostname.EdgeHostnameID = existing.EdgeHostnameID
+			continue
+		}
+
+		edgeHostname := edgeHostnames.NewEdgeHostname()
+		edgeHostname.DomainPrefix = wanted.DomainPrefix
+		edgeHostname.DomainSuffix = wanted.DomainSuffix
+		edgeHostname.ProductID = productID
+		edgeHostname.Secure = wanted.DomainSuffix == "edgekey.net"
+		edgeHostname.IPVersionBehavior = IPVersionIPv4
+		if err := edgeHostname.Save(""); err != nil {
+			return fmt.Errorf("Unable to create edge hostname \"%s\": %s", hostname.CnameTo, err)
+		}
+
+		hostname.EdgeHostnameID = edgeHostname.EdgeHostnameID
+	}
+
+	return nil
+}
+
+func writeExportFile(path string, data interface{}) error {
+	body, err := json.MarshalIndent(data, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(body, '\n'), 0644)
+}
+
+func readExportFile(path string, data interface{}) error {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, data); err != nil {
+		return fmt.Errorf("Unable to read \"%s\": %s", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package papi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockExportProperty() {
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions/3/rules").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_1",
+			"propertyVersion": 3,
+			"ruleFormat": "latest",
+			"rules": {
+				"name": "default",
+				"behaviors": [{"name": "origin", "options": {"hostname": "origin.example.com"}}]
+			}
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions/3/hostnames/").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_1",
+			"propertyVersion": 3,
+			"hostnames": {
+				"items": [
+					{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_2", "cnameFrom": "www.example.com", "cnameTo": "www.example.com.edgesuite.net"},
+					{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_1", "cnameFrom": "api.example.com", "cnameTo": "api.example.com.edgesuite.net"}
+				]
+			}
+		}`)
+}
+
+func testExportedProperty() *Property {
+	property := NewProperty(NewProperties())
+	property.Contract = NewContract(NewContracts())
+	property.Contract.ContractID = "ctr_1"
+	property.Group = NewGroup(NewGroups())
+	property.Group.GroupID = "grp_1"
+	property.AccountID = "act_1"
+	property.ContractID = "ctr_1"
+	property.GroupID = "grp_1"
+	property.PropertyID = "prp_1"
+	property.PropertyName = "www.example.com"
+	property.ProductID = "prd_Fresca"
+	property.LatestVersion = 3
+
+	return property
+}
+
+func TestExportProperty(t *testing.T) {
+	defer gock.Off()
+	mockExportProperty()
+
+	Init(config)
+
+	dir, err := ioutil.TempDir("", "papi-export")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	err = ExportProperty(testExportedProperty(), dir)
+	assert.NoError(t, err)
+
+	metadata, err := ReadPropertyExport(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, &PropertyExport{
+		AccountID:       "act_1",
+		ContractID:      "ctr_1",
+		GroupID:         "grp_1",
+		PropertyID:      "prp_1",
+		PropertyName:    "www.example.com",
+		PropertyVersion: 3,
+		ProductID:       "prd_Fresca",
+		RuleFormat:      "latest",
+	}, metadata)
+
+	hostnames, err := ioutil.ReadFile(filepath.Join(dir, ExportHostnamesFile))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_1", "cnameFrom": "api.example.com", "cnameTo": "api.example.com.edgesuite.net"},
+		{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_2", "cnameFrom": "www.example.com", "cnameTo": "www.example.com.edgesuite.net"}
+	]`, string(hostnames))
+
+	rules, err := ioutil.ReadFile(filepath.Join(dir, ExportRulesFile))
+	assert.NoError(t, err)
+	assert.Contains(t, string(rules), `"hostname": "origin.example.com"`)
+}
+
+func TestImportProperty(t *testing.T) {
+	defer gock.Off()
+	mockExportProperty()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/papi/v1/properties").
+		MatchParam("contractId", "ctr_2").
+		MatchParam("groupId", "grp_2").
+		HeaderPresent("Authorization").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyLink": "/papi/v1/properties/prp_9?contractId=ctr_2&groupId=grp_2"}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_9").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"properties": {"items": [{"accountId": "act_2", "contractId": "ctr_2", "groupId": "grp_2", "propertyId": "prp_9", "propertyName": "www.example.com", "latestVersion": 1, "productId": "prd_Fresca"}]}}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/edgehostnames").
+		MatchParam("contractId", "ctr_2").
+		MatchParam("groupId", "grp_2").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"accountId": "act_2",
+			"contractId": "ctr_2",
+			"groupId": "grp_2",
+			"edgeHostnames": {
+				"items": [
+					{"edgeHostnameId": "ehn_900", "edgeHostnameDomain": "www.example.com.edgesuite.net", "productId": "prd_Fresca", "domainPrefix": "www.example.com", "domainSuffix": "edgesuite.net"}
+				]
+			}
+		}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/papi/v1/edgehostnames/").
+		MatchParam("contractId", "ctr_2").
+		MatchParam("groupId", "grp_2").
+		JSON(`{"productId": "prd_Fresca", "domainPrefix": "api.example.com", "domainSuffix": "edgesuite.net", "ipVersionBehavior": "IPV4"}`).
+		HeaderPresent("Authorization").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"edgeHostnameLink": "/papi/v1/edgehostnames/ehn_901?contractId=ctr_2&groupId=grp_2"}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Put("/papi/v1/properties/prp_9/versions/1/hostnames").
+		MatchParam("contractId", "ctr_2").
+		MatchParam("groupId", "grp_2").
+		JSON(`[
+			{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_901", "cnameFrom": "api.example.com", "cnameTo": "api.example.com.edgesuite.net"},
+			{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_900", "cnameFrom": "www.example.com", "cnameTo": "www.example.com.edgesuite.net"}
+		]`).
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_9", "propertyVersion": 1, "hostnames": {"items": []}}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Put("/papi/v1/properties/prp_9/versions/1/rules").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_9", "propertyVersion": 1, "ruleFormat": "latest", "rules": {"name": "default"}}`)
+
+	Init(config)
+
+	dir, err := ioutil.TempDir("", "papi-export")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	err = ExportProperty(testExportedProperty(), dir)
+	assert.NoError(t, err)
+
+	contract := NewContract(NewContracts())
+	contract.ContractID = "ctr_2"
+	group := NewGroup(NewGroups())
+	group.GroupID = "grp_2"
+
+	property, err := ImportProperty(dir, contract, group)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "prp_9", property.PropertyID)
+	assert.True(t, gock.IsDone())
+}
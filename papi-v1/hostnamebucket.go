@@ -0,0 +1,464 @@
+package papi
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// HostnameBucket is the collection of hostnames of a property using hostname
+// buckets, where hostnames are activated independently of property versions
+type HostnameBucket struct {
+	client.Resource
+	AccountID    string `json:"accountId"`
+	ContractID   string `json:"contractId"`
+	GroupID      string `json:"groupId"`
+	PropertyID   string `json:"propertyId"`
+	PropertyName string `json:"propertyName"`
+	Hostnames    struct {
+		Items      []*BucketHostname `json:"items"`
+		TotalItems int               `json:"totalItems"`
+		NextLink   string            `json:"nextLink,omitempty"`
+	} `json:"hostnames"`
+}
+
+// BucketHostname represents a hostname within a HostnameBucket and its state
+// on each network
+type BucketHostname struct {
+	CnameFrom                string         `json:"cnameFrom"`
+	CnameType                CnameTypeValue `json:"cnameType"`
+	ProductionCnameTo        string         `json:"productionCnameTo,omitempty"`
+	ProductionEdgeHostnameID string         `json:"productionEdgeHostnameId,omitempty"`
+	ProductionCertType       string         `json:"productionCertType,omitempty"`
+	StagingCnameTo           string         `json:"stagingCnameTo,omitempty"`
+	StagingEdgeHostnameID    string         `json:"stagingEdgeHostnameId,omitempty"`
+	StagingCertType          string         `json:"stagingCertType,omitempty"`
+}
+
+// HostnameBucketChange represents hostnames to add to and remove from a
+// HostnameBucket on a network
+//
+// See: HostnameBucket.Patch()
+type HostnameBucketChange struct {
+	Network      NetworkValue `json:"network"`
+	Add          []*Hostname  `json:"add,omitempty"`
+	Remove       []string     `json:"remove,omitempty"`
+	Note         string       `json:"note,omitempty"`
+	NotifyEmails []string     `json:"notifyEmails,omitempty"`
+}
+
+// NewHostnameBucket creates a new HostnameBucket
+func NewHostnameBucket() *HostnameBucket {
+	bucket := &HostnameBucket{}
+	bucket.Init()
+
+	return bucket
+}
+
+// NewHostnameBucketChange creates a new HostnameBucketChange for a network
+func NewHostnameBucketChange(network NetworkValue) *HostnameBucketChange {
+	return &HostnameBucketChange{Network: network}
+}
+
+// AddHostname adds a hostname pointing to an edge hostname to the change
+func (change *HostnameBucketChange) AddHostname(cnameFrom string, edgeHostnameID string, certProvisioningType string) *Hostname {
+	hostname := NewHostname(nil)
+	hostname.CnameFrom = cnameFrom
+	hostname.EdgeHostnameID = edgeHostnameID
+	hostname.CertProvisioningType = certProvisioningType
+	change.Add = append(change.Add, hostname)
+
+	return hostname
+}
+
+// RemoveHostname removes a hostname as part of the change
+func (change *HostnameBucketChange) RemoveHostname(cnameFrom string) {
+	change.Remove = append(change.Remove, cnameFrom)
+}
+
+// GetHostnames populates the HostnameBucket with all hostnames of the property
+//
+// All pages are retrieved.
+//
+// See: Property.GetHostnameBucket()
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getpropertyhostnames
+// Endpoint: GET /papi/v1/properties/{propertyId}/hostnames{?contractId,groupId,offset,limit}
+func (bucket *HostnameBucket) GetHostnames() error {
+	var items []*BucketHostname
+	offset := 0
+
+	for {
+		page := NewHostnameBucket()
+		req, err := client.NewRequest(
+			Config,
+			"GET",
+			fmt.Sprintf(
+				"/papi/v1/properties/%s/hostnames?contractId=%s&groupId=%s&offset=%d&limit=%d",
+				bucket.PropertyID,
+				bucket.ContractID,
+				bucket.GroupID,
+				offset,
+				hostnameBucketPageSize,
+			),
+			nil,
+		)
+		if err != nil {
+			return err
+		}
+
+		res, err := client.Do(Config, req)
+		if err != nil {
+			return err
+		}
+
+		if client.IsError(res) {
+			return client.NewAPIError(res)
+		}
+
+		if err = client.BodyJSON(res, page); err != nil {
+			return err
+		}
+
+		items = append(items, page.Hostnames.Items...)
+		offset += len(page.Hostnames.Items)
+
+		bucket.AccountID = page.AccountID
+		bucket.PropertyName = page.PropertyName
+		bucket.Hostnames.TotalItems = page.Hostnames.TotalItems
+
+		if page.Hostnames.NextLink == "" || len(page.Hostnames.Items) == 0 {
+			break
+		}
+	}
+
+	bucket.Hostnames.Items = items
+	bucket.Hostnames.NextLink = ""
+
+	return nil
+}
+
+// FindHostname finds a hostname within the bucket
+func (bucket *HostnameBucket) FindHostname(cnameFrom string) *BucketHostname {
+	for _, hostname := range bucket.Hostnames.Items {
+		if hostname.CnameFrom == cnameFrom {
+			return hostname
+		}
+	}
+
+	return nil
+}
+
+// Patch adds and removes hostnames on a network
+//
+// The change is activated immediately; the returned HostnameActivation can
+// be used to track it, see HostnameActivation.PollStatus().
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#patchpropertyhostnames
+// Endpoint: PATCH /papi/v1/properties/{propertyId}/hostnames{?contractId,groupId}
+func (bucket *HostnameBucket) Patch(change *HostnameBucketChange) (*HostnameActivation, error) {
+	req, err := client.NewJSONRequest(
+		Config,
+		"PATCH",
+		fmt.Sprintf(
+			"/papi/v1/properties/%s/hostnames?contractId=%s&groupId=%s",
+			bucket.PropertyID,
+			bucket.ContractID,
+			bucket.GroupID,
+		),
+		change,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	var location client.JSONBody
+	if err = client.BodyJSON(res, &location); err != nil {
+		return nil, err
+	}
+
+	link, ok := location["activationLink"].(string)
+	if !ok {
+		return nil, fmt.Errorf("hostname patch response is missing \"activationLink\"")
+	}
+
+	linkURL, err := url.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+
+	activation := NewHostnameActivation(nil)
+	activation.PropertyID = bucket.PropertyID
+	activation.HostnameActivationID = path.Base(linkURL.Path)
+	activation.Network = change.Network
+
+	property := NewProperty(NewProperties())
+	property.PropertyID = bucket.PropertyID
+	property.ContractID = bucket.ContractID
+	property.GroupID = bucket.GroupID
+
+	if _, err := activation.GetHostnameActivation(property); err != nil {
+		return nil, err
+	}
+
+	return activation, nil
+}
+
+// HostnameActivations is a collection of hostname bucket activations
+type HostnameActivations struct {
+	client.Resource
+	AccountID           string `json:"accountId"`
+	ContractID          string `json:"contractId"`
+	GroupID             string `json:"groupId"`
+	HostnameActivations struct {
+		Items      []*HostnameActivation `json:"items"`
+		TotalItems int                   `json:"totalItems"`
+	} `json:"hostnameActivations"`
+}
+
+// NewHostnameActivations creates a new HostnameActivations
+func NewHostnameActivations() *HostnameActivations {
+	activations := &HostnameActivations{}
+	activations.Init()
+
+	return activations
+}
+
+// GetHostnameActivations retrieves hostname activations for a given property,
+// optionally limited to a network
+//
+// See: Property.GetHostnameActivations()
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#gethostnameactivations
+// Endpoint: GET /papi/v1/properties/{propertyId}/hostname-activations{?contractId,groupId,network}
+func (activations *HostnameActivations) GetHostnameActivations(property *Property, network NetworkValue) error {
+	query := url.Values{}
+	query.Set("contractId", property.ContractID)
+	query.Set("groupId", property.GroupID)
+	if network != "" {
+		query.Set("network", string(network))
+	}
+
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf(
+			"/papi/v1/properties/%s/hostname-activations?%s",
+			property.PropertyID,
+			query.Encode(),
+		),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	return client.BodyJSON(res, activations)
+}
+
+// GetPendingActivations returns activations that have not yet completed,
+// optionally limited to a network
+func (activations *HostnameActivations) GetPendingActivations(network NetworkValue) []*HostnameActivation {
+	var pending []*HostnameActivation
+	for _, activation := range activations.HostnameActivations.Items {
+		if network != "" && activation.Network != network {
+			continue
+		}
+
+		switch activation.Status {
+		case StatusActive, StatusAborted, StatusFailed, StatusDeactivated, StatusInactive:
+			continue
+		}
+
+		pending = append(pending, activation)
+	}
+
+	return pending
+}
+
+// HostnameActivation represents a hostname bucket activation
+type HostnameActivation struct {
+	client.Resource
+	parent               *HostnameActivations
+	HostnameActivationID string                      `json:"hostnameActivationId"`
+	ActivationType       ActivationValue             `json:"activationType"`
+	PropertyID           string                      `json:"propertyId"`
+	PropertyName         string                      `json:"propertyName"`
+	Network              NetworkValue                `json:"network"`
+	Status               StatusValue                 `json:"status"`
+	Note                 string                      `json:"note,omitempty"`
+	NotifyEmails         []string                    `json:"notifyEmails,omitempty"`
+	SubmitDate           string                      `json:"submitDate,omitempty"`
+	UpdateDate           string                      `json:"updateDate,omitempty"`
+	Hostnames            []*HostnameActivationChange `json:"hostnames,omitempty"`
+	StatusChange         chan bool                   `json:"-"`
+}
+
+// HostnameActivationChange represents a hostname added or removed by a HostnameActivation
+type HostnameActivationChange struct {
+	Action         string `json:"action"`
+	CnameFrom      string `json:"cnameFrom"`
+	CnameTo        string `json:"cnameTo,omitempty"`
+	EdgeHostnameID string `json:"edgeHostnameId,omitempty"`
+}
+
+// NewHostnameActivation creates a new HostnameActivation
+func NewHostnameActivation(parent *HostnameActivations) *HostnameActivation {
+	activation := &HostnameActivation{parent: parent}
+	activation.Init()
+
+	return activation
+}
+
+func (activation *HostnameActivation) Init() {
+	activation.Complete = make(chan bool, 1)
+	activation.StatusChange = make(chan bool, 1)
+}
+
+// GetHostnameActivation populates the HostnameActivation, including the
+// hostnames it changes
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#gethostnameactivation
+// Endpoint: GET /papi/v1/properties/{propertyId}/hostname-activations/{hostnameActivationId}{?contractId,groupId,includeHostnames}
+func (activation *HostnameActivation) GetHostnameActivation(property *Property) (time.Duration, error) {
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf(
+			"/papi/v1/properties/%s/hostname-activations/%s?contractId=%s&groupId=%s&includeHostnames=true",
+			property.PropertyID,
+			activation.HostnameActivationID,
+			property.ContractID,
+			property.GroupID,
+		),
+		nil,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return 0, err
+	}
+
+	if client.IsError(res) {
+		return 0, client.NewAPIError(res)
+	}
+
+	response := &struct {
+		HostnameActivations struct {
+			Items []*HostnameActivation `json:"items"`
+		} `json:"hostnameActivations"`
+	}{}
+	if err := client.BodyJSON(res, response); err != nil {
+		return 0, err
+	}
+
+	if len(response.HostnameActivations.Items) == 0 {
+		return 0, fmt.Errorf("hostname activation \"%s\" not found", activation.HostnameActivationID)
+	}
+
+	newActivation := response.HostnameActivations.Items[0]
+	activation.ActivationType = newActivation.ActivationType
+	activation.PropertyID = newActivation.PropertyID
+	activation.PropertyName = newActivation.PropertyName
+	activation.Network = newActivation.Network
+	activation.Status = newActivation.Status
+	activation.Note = newActivation.Note
+	activation.NotifyEmails = newActivation.NotifyEmails
+	activation.SubmitDate = newActivation.SubmitDate
+	activation.UpdateDate = newActivation.UpdateDate
+	activation.Hostnames = newActivation.Hostnames
+
+	return time.Duration(30 * time.Second), nil
+}
+
+// PollStatus will responsibly poll till the hostname activation is active or an error occurs
+//
+// The HostnameActivation.StatusChange is a channel that can be used to
+// block on status changes, see Activation.PollStatus().
+func (activation *HostnameActivation) PollStatus(property *Property) bool {
+	currentStatus := activation.Status
+	var retry time.Duration = 0
+
+	for currentStatus != StatusActive {
+		time.Sleep(retry)
+
+		var err error
+		retry, err = activation.GetHostnameActivation(property)
+		if err != nil {
+			activation.StatusChange <- false
+			return false
+		}
+
+		if activation.Status == StatusFailed || activation.Status == StatusAborted {
+			activation.StatusChange <- false
+			return false
+		}
+
+		if currentStatus != activation.Status {
+			currentStatus = activation.Status
+			activation.StatusChange <- true
+		}
+	}
+
+	return true
+}
+
+// Cancel a hostname activation in progress
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#deletehostnameactivation
+// Endpoint: DELETE /papi/v1/properties/{propertyId}/hostname-activations/{hostnameActivationId}{?contractId,groupId}
+func (activation *HostnameActivation) Cancel(property *Property) error {
+	req, err := client.NewRequest(
+		Config,
+		"DELETE",
+		fmt.Sprintf(
+			"/papi/v1/properties/%s/hostname-activations/%s?contractId=%s&groupId=%s",
+			property.PropertyID,
+			activation.HostnameActivationID,
+			property.ContractID,
+			property.GroupID,
+		),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	activation.Status = StatusAborted
+
+	return nil
+}
+
+// hostnameBucketPageSize is the number of hostnames requested per page
+const hostnameBucketPageSize = 500
@@ -0,0 +1,76 @@
+package papi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestHostnameBucket_Patch(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Patch("/papi/v1/properties/prp_1/hostnames").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		JSON(`{
+			"network": "STAGING",
+			"add": [{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_1", "cnameFrom": "www.example.com", "certProvisioningType": "CPS_MANAGED"}],
+			"remove": ["old.example.com"]
+		}`).
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activationLink": "/papi/v1/properties/prp_1/hostname-activations/atv_1?contractId=ctr_1&groupId=grp_1"}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/hostname-activations/atv_1").
+		MatchParam("includeHostnames", "true").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"hostnameActivations": {
+				"items": [
+					{
+						"hostnameActivationId": "atv_1",
+						"activationType": "ACTIVATE",
+						"propertyId": "prp_1",
+						"propertyName": "example.com",
+						"network": "STAGING",
+						"status": "PENDING",
+						"hostnames": [
+							{"action": "ADD", "cnameFrom": "www.example.com", "edgeHostnameId": "ehn_1"},
+							{"action": "REMOVE", "cnameFrom": "old.example.com"}
+						]
+					}
+				]
+			}
+		}`)
+
+	Init(config)
+
+	bucket := NewHostnameBucket()
+	bucket.PropertyID = "prp_1"
+	bucket.ContractID = "ctr_1"
+	bucket.GroupID = "grp_1"
+
+	change := NewHostnameBucketChange(NetworkStaging)
+	change.AddHostname("www.example.com", "ehn_1", CertProvisioningTypeCPSManaged)
+	change.RemoveHostname("old.example.com")
+
+	activation, err := bucket.Patch(change)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "atv_1", activation.HostnameActivationID)
+	assert.Equal(t, StatusPending, activation.Status)
+	assert.Len(t, activation.Hostnames, 2)
+
+	activations := NewHostnameActivations()
+	activations.HostnameActivations.Items = []*HostnameActivation{activation}
+	assert.Len(t, activations.GetPendingActivations(NetworkStaging), 1)
+	assert.Len(t, activations.GetPendingActivations(NetworkProduction), 0)
+}
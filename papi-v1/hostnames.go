@@ -150,6 +150,8 @@ type Hostname struct {
 	CnameFrom        string         `json:"cnameFrom"`
 	CnameTo          string         `json:"cnameTo,omitempty"`
 	CertEnrollmentId string         `json:"certEnrollmentId,omitempty"`
+	// CertProvisioningType is CertProvisioningTypeCPSManaged or CertProvisioningTypeDefault
+	CertProvisioningType string `json:"certProvisioningType,omitempty"`
 }
 
 // NewHostname creates a new Hostname
@@ -167,3 +169,10 @@ const (
 	// CnameTypeEdgeHostname Hostname.CnameType value EDGE_HOSTNAME
 	CnameTypeEdgeHostname CnameTypeValue = "EDGE_HOSTNAME"
 )
+
+const (
+	// CertProvisioningTypeCPSManaged Hostname.CertProvisioningType value CPS_MANAGED
+	CertProvisioningTypeCPSManaged = "CPS_MANAGED"
+	// CertProvisioningTypeDefault Hostname.CertProvisioningType value DEFAULT
+	CertProvisioningTypeDefault = "DEFAULT"
+)
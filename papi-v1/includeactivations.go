@@ -0,0 +1,352 @@
+package papi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// IncludeActivations is a collection of include activations
+type IncludeActivations struct {
+	client.Resource
+	AccountID   string `json:"accountId"`
+	ContractID  string `json:"contractId"`
+	GroupID     string `json:"groupId"`
+	Activations struct {
+		Items []*IncludeActivation `json:"items"`
+	} `json:"activations"`
+}
+
+// NewIncludeActivations creates a new IncludeActivations
+func NewIncludeActivations() *IncludeActivations {
+	activations := &IncludeActivations{}
+	activations.Init()
+
+	return activations
+}
+
+// GetActivations retrieves activation data for a given include
+//
+// See: Include.GetActivations()
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincludeactivations
+// Endpoint: GET /papi/v1/includes/{includeId}/activations{?contractId,groupId}
+func (activations *IncludeActivations) GetActivations(include *Include) error {
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf(
+			"/papi/v1/includes/%s/activations?contractId=%s&groupId=%s",
+			include.IncludeID,
+			include.ContractID,
+			include.GroupID,
+		),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	if err = client.BodyJSON(res, activations); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetLatestActivation gets the latest activation for the specified network
+//
+// Default to NetworkProduction. Pass in a status to check for, defaults to StatusActive
+//
+// This can return an activation OR a deactivation. Check activation.ActivationType and activation.Status for what you're looking for
+func (activations *IncludeActivations) GetLatestActivation(network NetworkValue, status StatusValue) (*IncludeActivation, error) {
+	if network == "" {
+		network = NetworkProduction
+	}
+
+	if status == "" {
+		status = StatusActive
+	}
+
+	var latest *IncludeActivation
+	for _, activation := range activations.Activations.Items {
+		if activation.Network == network && activation.Status == status && (latest == nil || activation.IncludeVersion > latest.IncludeVersion) {
+			latest = activation
+		}
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("No activation found (network: %s, status: %s)", network, status)
+	}
+
+	return latest, nil
+}
+
+// IncludeActivation represents an include activation resource
+type IncludeActivation struct {
+	client.Resource
+	parent                 *IncludeActivations
+	ActivationID           string                      `json:"activationId,omitempty"`
+	ActivationType         ActivationValue             `json:"activationType,omitempty"`
+	AcknowledgeAllWarnings bool                        `json:"acknowledgeAllWarnings,omitempty"`
+	AcknowledgeWarnings    []string                    `json:"acknowledgeWarnings,omitempty"`
+	ComplianceRecord       *ActivationComplianceRecord `json:"complianceRecord,omitempty"`
+	IgnoreHTTPErrors       bool                        `json:"ignoreHttpErrors,omitempty"`
+	IncludeName            string                      `json:"includeName,omitempty"`
+	IncludeID              string                      `json:"includeId,omitempty"`
+	IncludeType            IncludeTypeValue            `json:"includeType,omitempty"`
+	IncludeVersion         int                         `json:"includeVersion"`
+	Network                NetworkValue                `json:"network"`
+	Status                 StatusValue                 `json:"status,omitempty"`
+	SubmitDate             string                      `json:"submitDate,omitempty"`
+	UpdateDate             string                      `json:"updateDate,omitempty"`
+	Note                   string                      `json:"note,omitempty"`
+	NotifyEmails           []string                    `json:"notifyEmails"`
+	StatusChange           chan bool                   `json:"-"`
+}
+
+// NewIncludeActivation creates a new IncludeActivation
+func NewIncludeActivation(parent *IncludeActivations) *IncludeActivation {
+	activation := &IncludeActivation{parent: parent}
+	activation.Init()
+
+	return activation
+}
+
+func (activation *IncludeActivation) Init() {
+	activation.Complete = make(chan bool, 1)
+	activation.StatusChange = make(chan bool, 1)
+}
+
+// GetActivation populates the IncludeActivation resource
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincludeactivation
+// Endpoint: GET /papi/v1/includes/{includeId}/activations/{activationId}{?contractId,groupId}
+func (activation *IncludeActivation) GetActivation(include *Include) (time.Duration, error) {
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf(
+			"/papi/v1/includes/%s/activations/%s?contractId=%s&groupId=%s",
+			include.IncludeID,
+			activation.ActivationID,
+			include.ContractID,
+			include.GroupID,
+		),
+		nil,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return 0, err
+	}
+
+	if client.IsError(res) {
+		return 0, client.NewAPIError(res)
+	}
+
+	activations := NewIncludeActivations()
+	if err := client.BodyJSON(res, activations); err != nil {
+		return 0, err
+	}
+
+	if len(activations.Activations.Items) == 0 {
+		return 0, fmt.Errorf("include activation \"%s\" not found", activation.ActivationID)
+	}
+
+	activation.update(activations.Activations.Items[0])
+
+	return time.Duration(30 * time.Second), nil
+}
+
+func (activation *IncludeActivation) update(from *IncludeActivation) {
+	activation.ActivationID = from.ActivationID
+	activation.ActivationType = from.ActivationType
+	activation.AcknowledgeWarnings = from.AcknowledgeWarnings
+	activation.ComplianceRecord = from.ComplianceRecord
+	activation.IgnoreHTTPErrors = from.IgnoreHTTPErrors
+	activation.IncludeName = from.IncludeName
+	activation.IncludeID = from.IncludeID
+	activation.IncludeType = from.IncludeType
+	activation.IncludeVersion = from.IncludeVersion
+	activation.Network = from.Network
+	activation.Status = from.Status
+	activation.SubmitDate = from.SubmitDate
+	activation.UpdateDate = from.UpdateDate
+	activation.Note = from.Note
+	activation.NotifyEmails = from.NotifyEmails
+}
+
+// Save activates a given include version
+//
+// If acknowledgeWarnings is true, all activation warnings are acknowledged.
+//
+// See: Include.Activate()
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#postincludeactivation
+// Endpoint: POST /papi/v1/includes/{includeId}/activations{?contractId,groupId}
+func (activation *IncludeActivation) Save(include *Include, acknowledgeWarnings bool) error {
+	if activation.ComplianceRecord == nil && activation.Network == NetworkProduction {
+		activation.ComplianceRecord = &ActivationComplianceRecord{
+			NoncomplianceReason: "NO_PRODUCTION_TRAFFIC",
+		}
+	}
+
+	if activation.ActivationType == "" {
+		activation.ActivationType = ActivationTypeActivate
+	}
+
+	activation.AcknowledgeAllWarnings = acknowledgeWarnings
+
+	req, err := client.NewJSONRequest(
+		Config,
+		"POST",
+		fmt.Sprintf(
+			"/papi/v1/includes/%s/activations?contractId=%s&groupId=%s",
+			include.IncludeID,
+			include.ContractID,
+			include.GroupID,
+		),
+		activation,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	var location client.JSONBody
+	if err = client.BodyJSON(res, &location); err != nil {
+		return err
+	}
+
+	link, ok := location["activationLink"].(string)
+	if !ok {
+		return fmt.Errorf("include activation response is missing \"activationLink\"")
+	}
+
+	req, err = client.NewRequest(Config, "GET", link, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err = client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	activations := NewIncludeActivations()
+	if err := client.BodyJSON(res, activations); err != nil {
+		return err
+	}
+
+	if len(activations.Activations.Items) == 0 {
+		return fmt.Errorf("include activation response is empty")
+	}
+
+	activation.update(activations.Activations.Items[0])
+
+	return nil
+}
+
+// PollStatus will responsibly poll till the include is active or an error occurs
+//
+// The IncludeActivation.StatusChange is a channel that can be used to
+// block on status changes. If a new valid status is returned, true will
+// be sent to the channel, otherwise, false will be sent.
+//
+// See: Activation.PollStatus()
+func (activation *IncludeActivation) PollStatus(include *Include) bool {
+	currentStatus := activation.Status
+	var retry time.Duration = 0
+
+	for currentStatus != StatusActive {
+		time.Sleep(retry)
+
+		var err error
+		retry, err = activation.GetActivation(include)
+		if err != nil {
+			activation.StatusChange <- false
+			return false
+		}
+
+		if activation.Network == NetworkStaging && retry > time.Minute {
+			retry = time.Minute
+		}
+
+		if currentStatus != activation.Status {
+			currentStatus = activation.Status
+			activation.StatusChange <- true
+		}
+
+		if currentStatus == StatusFailed || currentStatus == StatusAborted {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Cancel an include activation in progress
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#deleteincludeactivation
+// Endpoint: DELETE /papi/v1/includes/{includeId}/activations/{activationId}{?contractId,groupId}
+func (activation *IncludeActivation) Cancel(include *Include) error {
+	req, err := client.NewRequest(
+		Config,
+		"DELETE",
+		fmt.Sprintf(
+			"/papi/v1/includes/%s/activations/%s?contractId=%s&groupId=%s",
+			include.IncludeID,
+			activation.ActivationID,
+			include.ContractID,
+			include.GroupID,
+		),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	activations := NewIncludeActivations()
+	if err := client.BodyJSON(res, activations); err != nil {
+		return err
+	}
+
+	if len(activations.Activations.Items) != 0 {
+		activation.update(activations.Activations.Items[0])
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package papi
+
+import (
+	"fmt"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// IncludeRules is the rule tree of an include version
+type IncludeRules struct {
+	client.Resource
+	AccountID      string           `json:"accountId"`
+	ContractID     string           `json:"contractId"`
+	GroupID        string           `json:"groupId"`
+	IncludeID      string           `json:"includeId"`
+	IncludeName    string           `json:"includeName"`
+	IncludeType    IncludeTypeValue `json:"includeType"`
+	IncludeVersion int              `json:"includeVersion"`
+	Etag           string           `json:"etag"`
+	RuleFormat     string           `json:"ruleFormat"`
+	Rule           *Rule            `json:"rules"`
+	Errors         []*RuleErrors    `json:"errors,omitempty"`
+}
+
+// NewIncludeRules creates a new IncludeRules
+func NewIncludeRules() *IncludeRules {
+	rules := &IncludeRules{}
+	rules.Rule = NewRule()
+	rules.Rule.Name = "default"
+	rules.Init()
+
+	return rules
+}
+
+// PreMarshalJSON is called before JSON marshaling
+//
+// See: jsonhooks-v1/json.Marshal()
+func (rules *IncludeRules) PreMarshalJSON() error {
+	rules.Errors = nil
+	return nil
+}
+
+// GetRules populates IncludeRules with rule data for a given include version
+//
+// If version is 0, the latest version of the include is used.
+//
+// See: Include.GetRules()
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincluderuletree
+// Endpoint: GET /papi/v1/includes/{includeId}/versions/{includeVersion}/rules{?contractId,groupId}
+func (rules *IncludeRules) GetRules(include *Include, version int) error {
+	if version == 0 {
+		version = include.LatestVersion
+	}
+
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf(
+			"/papi/v1/includes/%s/versions/%d/rules?contractId=%s&groupId=%s",
+			include.IncludeID,
+			version,
+			include.ContractID,
+			include.GroupID,
+		),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	if err = client.BodyJSON(res, rules); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Save updates the rule tree of an include version
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#putincluderuletree
+// Endpoint: PUT /papi/v1/includes/{includeId}/versions/{includeVersion}/rules{?contractId,groupId}
+func (rules *IncludeRules) Save() error {
+	rules.Errors = []*RuleErrors{}
+
+	req, err := client.NewJSONRequest(
+		Config,
+		"PUT",
+		fmt.Sprintf(
+			"/papi/v1/includes/%s/versions/%d/rules?contractId=%s&groupId=%s",
+			rules.IncludeID,
+			rules.IncludeVersion,
+			rules.ContractID,
+			rules.GroupID,
+		),
+		rules,
+	)
+	if err != nil {
+		return err
+	}
+
+	if rules.RuleFormat != "" && rules.RuleFormat != "latest" {
+		req.Header.Set("Content-Type", fmt.Sprintf("application/vnd.akamai.papirules.%s+json", rules.RuleFormat))
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	if err = client.BodyJSON(res, rules); err != nil {
+		return err
+	}
+
+	if len(rules.Errors) != 0 {
+		return ErrorMap[ErrInvalidRules]
+	}
+
+	return nil
+}
+
+// NewIncludeBehavior creates an "include" behavior referencing an include,
+// for use in a property rule tree
+func NewIncludeBehavior(includeID string) *Behavior {
+	behavior := NewBehavior()
+	behavior.Name = "include"
+	behavior.Options = OptionValue{"id": includeID}
+
+	return behavior
+}
+
+// FindIncludeReferences lists the IDs of includes referenced by a property rule tree
+func (rules *Rules) FindIncludeReferences() []string {
+	var includeIDs []string
+	seen := map[string]bool{}
+
+	var walk func(rule *Rule)
+	walk = func(rule *Rule) {
+		for _, behavior := range rule.Behaviors {
+			if behavior.Name != "include" {
+				continue
+			}
+
+			includeID, ok := behavior.Options["id"].(string)
+			if ok && !seen[includeID] {
+				seen[includeID] = true
+				includeIDs = append(includeIDs, includeID)
+			}
+		}
+
+		for _, child := range rule.Children {
+			walk(child)
+		}
+	}
+
+	if rules.Rule != nil {
+		walk(rules.Rule)
+	}
+
+	return includeIDs
+}
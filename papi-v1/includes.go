@@ -0,0 +1,447 @@
+package papi
+
+import (
+	"fmt"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// Includes is a collection of PAPI Include resources
+//
+// Includes are rule tree fragments versioned and activated independently of
+// the properties that reference them using the "include" behavior.
+type Includes struct {
+	client.Resource
+	Includes struct {
+		Items []*Include `json:"items"`
+	} `json:"includes"`
+}
+
+// NewIncludes creates a new Includes
+func NewIncludes() *Includes {
+	includes := &Includes{}
+	includes.Init()
+
+	return includes
+}
+
+// PostUnmarshalJSON is called after JSON unmarshaling into Includes
+//
+// See: jsonhooks-v1/jsonhooks.Unmarshal()
+func (includes *Includes) PostUnmarshalJSON() error {
+	includes.Init()
+
+	for key := range includes.Includes.Items {
+		includes.Includes.Items[key].parent = includes
+		includes.Includes.Items[key].Init()
+	}
+
+	includes.Complete <- true
+
+	return nil
+}
+
+// GetIncludes populates Includes with include data
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincludes
+// Endpoint: GET /papi/v1/includes{?contractId,groupId}
+func (includes *Includes) GetIncludes(contractID string, groupID string) error {
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf(
+			"/papi/v1/includes?contractId=%s&groupId=%s",
+			contractID,
+			groupID,
+		),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	if err = client.BodyJSON(res, includes); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddInclude adds an include to the collection, if the include already exists
+// in the collection it will be replaced.
+func (includes *Includes) AddInclude(newInclude *Include) {
+	if newInclude.IncludeID != "" {
+		for key, include := range includes.Includes.Items {
+			if include.IncludeID == newInclude.IncludeID {
+				includes.Includes.Items[key] = newInclude
+				return
+			}
+		}
+	}
+
+	newInclude.parent = includes
+
+	includes.Includes.Items = append(includes.Includes.Items, newInclude)
+}
+
+// FindInclude finds an include by ID or name within the collection
+func (includes *Includes) FindInclude(idOrName string) (*Include, error) {
+	for _, include := range includes.Includes.Items {
+		if include.IncludeID == idOrName || include.IncludeName == idOrName {
+			return include, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Unable to find include: \"%s\"", idOrName)
+}
+
+// NewInclude creates a new include associated with the collection
+func (includes *Includes) NewInclude(contractID string, groupID string) *Include {
+	include := NewInclude(includes)
+	include.ContractID = contractID
+	include.GroupID = groupID
+
+	includes.AddInclude(include)
+
+	return include
+}
+
+// Include represents a PAPI Include
+type Include struct {
+	client.Resource
+	parent            *Includes
+	AccountID         string            `json:"accountId,omitempty"`
+	ContractID        string            `json:"contractId,omitempty"`
+	GroupID           string            `json:"groupId,omitempty"`
+	AssetID           string            `json:"assetId,omitempty"`
+	IncludeID         string            `json:"includeId,omitempty"`
+	IncludeName       string            `json:"includeName"`
+	IncludeType       IncludeTypeValue  `json:"includeType"`
+	LatestVersion     int               `json:"latestVersion,omitempty"`
+	StagingVersion    int               `json:"stagingVersion,omitempty"`
+	ProductionVersion int               `json:"productionVersion,omitempty"`
+	ProductID         string            `json:"productId,omitempty"`
+	RuleFormat        string            `json:"ruleFormat,omitempty"`
+	CloneFrom         *CloneIncludeFrom `json:"cloneFrom,omitempty"`
+}
+
+// CloneIncludeFrom represents the include version an include is cloned from
+type CloneIncludeFrom struct {
+	IncludeID            string `json:"includeId"`
+	Version              int    `json:"version"`
+	CloneFromVersionEtag string `json:"cloneFromVersionEtag,omitempty"`
+}
+
+// NewInclude creates a new Include
+func NewInclude(parent *Includes) *Include {
+	include := &Include{parent: parent}
+	include.Init()
+
+	return include
+}
+
+// GetInclude populates an Include
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getinclude
+// Endpoint: GET /papi/v1/includes/{includeId}{?contractId,groupId}
+func (include *Include) GetInclude() error {
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf(
+			"/papi/v1/includes/%s?contractId=%s&groupId=%s",
+			include.IncludeID,
+			include.ContractID,
+			include.GroupID,
+		),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	newIncludes := NewIncludes()
+	if err := client.BodyJSON(res, newIncludes); err != nil {
+		return err
+	}
+
+	if len(newIncludes.Includes.Items) == 0 {
+		return fmt.Errorf("Unable to find include: \"%s\"", include.IncludeID)
+	}
+
+	include.update(newIncludes.Includes.Items[0])
+
+	return nil
+}
+
+func (include *Include) update(from *Include) {
+	include.AccountID = from.AccountID
+	include.ContractID = from.ContractID
+	include.GroupID = from.GroupID
+	include.AssetID = from.AssetID
+	include.IncludeID = from.IncludeID
+	include.IncludeName = from.IncludeName
+	include.IncludeType = from.IncludeType
+	include.LatestVersion = from.LatestVersion
+	include.StagingVersion = from.StagingVersion
+	include.ProductionVersion = from.ProductionVersion
+}
+
+// GetVersions retrieves all versions for the include
+//
+// See: IncludeVersions.GetVersions()
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincludeversions
+// Endpoint: GET /papi/v1/includes/{includeId}/versions{?contractId,groupId}
+func (include *Include) GetVersions() (*IncludeVersions, error) {
+	versions := NewIncludeVersions()
+	if err := versions.GetVersions(include); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// GetLatestVersion gets the latest version, optionally of a given network
+//
+// See: IncludeVersions.GetLatestVersion()
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getlatestincludeversion
+// Endpoint: GET /papi/v1/includes/{includeId}/versions/latest{?contractId,groupId,activatedOn}
+func (include *Include) GetLatestVersion(activatedOn NetworkValue) (*IncludeVersion, error) {
+	versions := NewIncludeVersions()
+	versions.IncludeID = include.IncludeID
+	versions.ContractID = include.ContractID
+	versions.GroupID = include.GroupID
+
+	return versions.GetLatestVersion(activatedOn)
+}
+
+// GetRules retrieves the rules of an include version
+//
+// If version is 0, the latest version is used.
+//
+// See: IncludeRules.GetRules()
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincluderuletree
+// Endpoint: GET /papi/v1/includes/{includeId}/versions/{includeVersion}/rules{?contractId,groupId}
+func (include *Include) GetRules(version int) (*IncludeRules, error) {
+	rules := NewIncludeRules()
+	if err := rules.GetRules(include, version); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// GetActivations retrieves activations of the include
+//
+// See: IncludeActivations.GetActivations()
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincludeactivations
+// Endpoint: GET /papi/v1/includes/{includeId}/activations{?contractId,groupId}
+func (include *Include) GetActivations() (*IncludeActivations, error) {
+	activations := NewIncludeActivations()
+	if err := activations.GetActivations(include); err != nil {
+		return nil, err
+	}
+
+	return activations, nil
+}
+
+// Activate activates an include version
+//
+// See: IncludeActivation.Save()
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#postincludeactivation
+// Endpoint: POST /papi/v1/includes/{includeId}/activations{?contractId,groupId}
+func (include *Include) Activate(activation *IncludeActivation, acknowledgeWarnings bool) error {
+	return activation.Save(include, acknowledgeWarnings)
+}
+
+// GetParentProperties lists the properties whose rules reference an include version
+//
+// If version is 0, the latest version is used.
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincludeparents
+// Endpoint: GET /papi/v1/includes/{includeId}/versions/{includeVersion}/parents{?contractId,groupId}
+func (include *Include) GetParentProperties(version int) ([]*IncludeParent, error) {
+	if version == 0 {
+		version = include.LatestVersion
+	}
+
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf(
+			"/papi/v1/includes/%s/versions/%d/parents?contractId=%s&groupId=%s",
+			include.IncludeID,
+			version,
+			include.ContractID,
+			include.GroupID,
+		),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	parents := &struct {
+		Properties struct {
+			Items []*IncludeParent `json:"items"`
+		} `json:"properties"`
+	}{}
+	if err = client.BodyJSON(res, parents); err != nil {
+		return nil, err
+	}
+
+	return parents.Properties.Items, nil
+}
+
+// Save creates an include, optionally cloned from another include
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#postincludes
+// Endpoint: POST /papi/v1/includes{?contractId,groupId}
+func (include *Include) Save() error {
+	req, err := client.NewJSONRequest(
+		Config,
+		"POST",
+		fmt.Sprintf(
+			"/papi/v1/includes?contractId=%s&groupId=%s",
+			include.ContractID,
+			include.GroupID,
+		),
+		include,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	var location client.JSONBody
+	if err = client.BodyJSON(res, &location); err != nil {
+		return err
+	}
+
+	link, ok := location["includeLink"].(string)
+	if !ok {
+		return fmt.Errorf("include creation response is missing \"includeLink\"")
+	}
+
+	req, err = client.NewRequest(Config, "GET", link, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err = client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	includes := NewIncludes()
+	if err = client.BodyJSON(res, includes); err != nil {
+		return err
+	}
+
+	if len(includes.Includes.Items) == 0 {
+		return fmt.Errorf("include creation response is empty")
+	}
+
+	include.update(includes.Includes.Items[0])
+
+	return nil
+}
+
+// Delete an include
+//
+// An include can only be deleted if it is not active and not referenced by
+// any property.
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#deleteinclude
+// Endpoint: DELETE /papi/v1/includes/{includeId}{?contractId,groupId}
+func (include *Include) Delete() error {
+	req, err := client.NewRequest(
+		Config,
+		"DELETE",
+		fmt.Sprintf(
+			"/papi/v1/includes/%s?contractId=%s&groupId=%s",
+			include.IncludeID,
+			include.ContractID,
+			include.GroupID,
+		),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	return nil
+}
+
+// IncludeParent is a property referencing an include
+//
+// See: Include.GetParentProperties()
+type IncludeParent struct {
+	AccountID                        string `json:"accountId"`
+	ContractID                       string `json:"contractId"`
+	GroupID                          string `json:"groupId"`
+	PropertyID                       string `json:"propertyId"`
+	PropertyName                     string `json:"propertyName"`
+	StagingVersion                   int    `json:"stagingVersion,omitempty"`
+	ProductionVersion                int    `json:"productionVersion,omitempty"`
+	IsIncludeUsedInStagingVersion    bool   `json:"isIncludeUsedInStagingVersion"`
+	IsIncludeUsedInProductionVersion bool   `json:"isIncludeUsedInProductionVersion"`
+}
+
+// IncludeTypeValue is used to create an "enum" of possible Include.IncludeType values
+type IncludeTypeValue string
+
+const (
+	// IncludeTypeMicroservices Include.IncludeType value MICROSERVICES
+	IncludeTypeMicroservices IncludeTypeValue = "MICROSERVICES"
+	// IncludeTypeCommonSettings Include.IncludeType value COMMON_SETTINGS
+	IncludeTypeCommonSettings IncludeTypeValue = "COMMON_SETTINGS"
+)
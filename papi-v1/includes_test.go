@@ -0,0 +1,119 @@
+package papi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestInclude_Save(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/papi/v1/includes").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		JSON(`{"contractId": "ctr_1", "groupId": "grp_1", "includeName": "shared-security", "includeType": "MICROSERVICES", "productId": "prd_Fresca", "ruleFormat": "v2020-11-02"}`).
+		HeaderPresent("Authorization").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"includeLink": "/papi/v1/includes/inc_1?contractId=ctr_1&groupId=grp_1"}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/includes/inc_1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"includes": {
+				"items": [
+					{
+						"accountId": "act_1",
+						"contractId": "ctr_1",
+						"groupId": "grp_1",
+						"includeId": "inc_1",
+						"includeName": "shared-security",
+						"includeType": "MICROSERVICES",
+						"latestVersion": 1
+					}
+				]
+			}
+		}`)
+
+	Init(config)
+
+	include := NewIncludes().NewInclude("ctr_1", "grp_1")
+	include.IncludeName = "shared-security"
+	include.IncludeType = IncludeTypeMicroservices
+	include.ProductID = "prd_Fresca"
+	include.RuleFormat = "v2020-11-02"
+
+	err := include.Save()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "inc_1", include.IncludeID)
+	assert.Equal(t, "act_1", include.AccountID)
+	assert.Equal(t, 1, include.LatestVersion)
+}
+
+func TestInclude_GetParentProperties(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/includes/inc_1/versions/3/parents").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"properties": {
+				"items": [
+					{
+						"accountId": "act_1",
+						"contractId": "ctr_1",
+						"groupId": "grp_1",
+						"propertyId": "prp_1",
+						"propertyName": "www.example.com",
+						"stagingVersion": 4,
+						"productionVersion": 3,
+						"isIncludeUsedInStagingVersion": true,
+						"isIncludeUsedInProductionVersion": false
+					}
+				]
+			}
+		}`)
+
+	Init(config)
+
+	include := NewInclude(NewIncludes())
+	include.IncludeID = "inc_1"
+	include.ContractID = "ctr_1"
+	include.GroupID = "grp_1"
+	include.LatestVersion = 3
+
+	parents, err := include.GetParentProperties(0)
+
+	assert.NoError(t, err)
+	assert.Len(t, parents, 1)
+	assert.Equal(t, "prp_1", parents[0].PropertyID)
+	assert.True(t, parents[0].IsIncludeUsedInStagingVersion)
+	assert.False(t, parents[0].IsIncludeUsedInProductionVersion)
+}
+
+func TestRules_FindIncludeReferences(t *testing.T) {
+	rules := NewRules()
+	rules.Rule.AddBehavior(NewIncludeBehavior("inc_1"))
+
+	child := NewRule()
+	child.Name = "Static Content"
+	child.AddBehavior(NewIncludeBehavior("inc_2"))
+	child.AddBehavior(NewIncludeBehavior("inc_1"))
+	rules.Rule.AddChildRule(child)
+
+	assert.Equal(t, []string{"inc_1", "inc_2"}, rules.FindIncludeReferences())
+}
@@ -0,0 +1,342 @@
+package papi
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+// IncludeVersions contains a collection of Include Versions
+type IncludeVersions struct {
+	client.Resource
+	IncludeID   string           `json:"includeId"`
+	IncludeName string           `json:"includeName"`
+	IncludeType IncludeTypeValue `json:"includeType"`
+	AccountID   string           `json:"accountId"`
+	ContractID  string           `json:"contractId"`
+	GroupID     string           `json:"groupId"`
+	Versions    struct {
+		Items []*IncludeVersion `json:"items"`
+	} `json:"versions"`
+}
+
+// NewIncludeVersions creates a new IncludeVersions
+func NewIncludeVersions() *IncludeVersions {
+	versions := &IncludeVersions{}
+	versions.Init()
+
+	return versions
+}
+
+// PostUnmarshalJSON is called after JSON unmarshaling into IncludeVersions
+//
+// See: jsonhooks-v1/jsonhooks.Unmarshal()
+func (versions *IncludeVersions) PostUnmarshalJSON() error {
+	versions.Init()
+
+	for key := range versions.Versions.Items {
+		versions.Versions.Items[key].parent = versions
+	}
+	versions.Complete <- true
+
+	return nil
+}
+
+// AddVersion adds or replaces a version within the collection
+func (versions *IncludeVersions) AddVersion(version *IncludeVersion) {
+	if version.IncludeVersion != 0 {
+		for key, v := range versions.Versions.Items {
+			if v.IncludeVersion == version.IncludeVersion {
+				versions.Versions.Items[key] = version
+				return
+			}
+		}
+	}
+
+	versions.Versions.Items = append(versions.Versions.Items, version)
+}
+
+// FindVersion finds a version by number within the collection
+func (versions *IncludeVersions) FindVersion(includeVersion int) (*IncludeVersion, error) {
+	for _, version := range versions.Versions.Items {
+		if version.IncludeVersion == includeVersion {
+			return version, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Unable to find version: %d", includeVersion)
+}
+
+// GetVersions retrieves all versions for a given include
+//
+// See: Include.GetVersions()
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincludeversions
+// Endpoint: GET /papi/v1/includes/{includeId}/versions{?contractId,groupId}
+func (versions *IncludeVersions) GetVersions(include *Include) error {
+	if include == nil {
+		return errors.New("You must provide an include")
+	}
+
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf(
+			"/papi/v1/includes/%s/versions?contractId=%s&groupId=%s",
+			include.IncludeID,
+			include.ContractID,
+			include.GroupID,
+		),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	if err = client.BodyJSON(res, versions); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetLatestVersion retrieves the latest IncludeVersion, optionally of a given network
+//
+// See: Include.GetLatestVersion()
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getlatestincludeversion
+// Endpoint: GET /papi/v1/includes/{includeId}/versions/latest{?contractId,groupId,activatedOn}
+func (versions *IncludeVersions) GetLatestVersion(activatedOn NetworkValue) (*IncludeVersion, error) {
+	query := fmt.Sprintf("?contractId=%s&groupId=%s", versions.ContractID, versions.GroupID)
+	if activatedOn != "" {
+		query += "&activatedOn=" + string(activatedOn)
+	}
+
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf(
+			"/papi/v1/includes/%s/versions/latest%s",
+			versions.IncludeID,
+			query,
+		),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	newVersions := NewIncludeVersions()
+	if err := client.BodyJSON(res, newVersions); err != nil {
+		return nil, err
+	}
+
+	if len(newVersions.Versions.Items) == 0 {
+		return nil, fmt.Errorf("No version found for include: \"%s\"", versions.IncludeID)
+	}
+
+	version := newVersions.Versions.Items[0]
+	version.parent = versions
+
+	return version, nil
+}
+
+// NewVersion creates a new version associated with the IncludeVersions collection
+func (versions *IncludeVersions) NewVersion(createFromVersion *IncludeVersion, useEtagStrict bool) *IncludeVersion {
+	if createFromVersion == nil {
+		var err error
+		createFromVersion, err = versions.GetLatestVersion("")
+		if err != nil {
+			return nil
+		}
+	}
+
+	version := NewIncludeVersion(versions)
+	version.CreateFromVersion = createFromVersion.IncludeVersion
+
+	versions.Versions.Items = append(versions.Versions.Items, version)
+
+	if useEtagStrict {
+		version.CreateFromVersionEtag = createFromVersion.Etag
+	}
+
+	return version
+}
+
+// IncludeVersion represents an Include Version
+type IncludeVersion struct {
+	client.Resource
+	parent                *IncludeVersions
+	IncludeVersion        int         `json:"includeVersion,omitempty"`
+	UpdatedByUser         string      `json:"updatedByUser,omitempty"`
+	UpdatedDate           time.Time   `json:"updatedDate,omitempty"`
+	ProductionStatus      StatusValue `json:"productionStatus,omitempty"`
+	StagingStatus         StatusValue `json:"stagingStatus,omitempty"`
+	Etag                  string      `json:"etag,omitempty"`
+	ProductID             string      `json:"productId,omitempty"`
+	Note                  string      `json:"note,omitempty"`
+	CreateFromVersion     int         `json:"createFromVersion,omitempty"`
+	CreateFromVersionEtag string      `json:"createFromVersionEtag,omitempty"`
+	RuleFormat            string      `json:"ruleFormat,omitempty"`
+}
+
+// NewIncludeVersion creates a new IncludeVersion
+func NewIncludeVersion(parent *IncludeVersions) *IncludeVersion {
+	version := &IncludeVersion{parent: parent}
+	version.Init()
+
+	return version
+}
+
+// GetVersion populates an IncludeVersion
+//
+// If getVersion is 0, the latest version of the include is used.
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincludeversion
+// Endpoint: GET /papi/v1/includes/{includeId}/versions/{includeVersion}{?contractId,groupId}
+func (version *IncludeVersion) GetVersion(include *Include, getVersion int) error {
+	if getVersion == 0 {
+		getVersion = include.LatestVersion
+	}
+
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf(
+			"/papi/v1/includes/%s/versions/%d?contractId=%s&groupId=%s",
+			include.IncludeID,
+			getVersion,
+			include.ContractID,
+			include.GroupID,
+		),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	newVersions := NewIncludeVersions()
+	if err := client.BodyJSON(res, newVersions); err != nil {
+		return err
+	}
+
+	if len(newVersions.Versions.Items) == 0 {
+		return fmt.Errorf("Unable to find version: %d", getVersion)
+	}
+
+	version.update(newVersions.Versions.Items[0])
+
+	return nil
+}
+
+func (version *IncludeVersion) update(from *IncludeVersion) {
+	version.IncludeVersion = from.IncludeVersion
+	version.UpdatedByUser = from.UpdatedByUser
+	version.UpdatedDate = from.UpdatedDate
+	version.ProductionStatus = from.ProductionStatus
+	version.StagingStatus = from.StagingStatus
+	version.Etag = from.Etag
+	version.ProductID = from.ProductID
+	version.Note = from.Note
+	version.RuleFormat = from.RuleFormat
+}
+
+// Save creates a new include version
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#postincludeversions
+// Endpoint: POST /papi/v1/includes/{includeId}/versions{?contractId,groupId}
+func (version *IncludeVersion) Save() error {
+	if version.IncludeVersion != 0 {
+		return fmt.Errorf("version (%d) already exists", version.IncludeVersion)
+	}
+
+	req, err := client.NewJSONRequest(
+		Config,
+		"POST",
+		fmt.Sprintf(
+			"/papi/v1/includes/%s/versions?contractId=%s&groupId=%s",
+			version.parent.IncludeID,
+			version.parent.ContractID,
+			version.parent.GroupID,
+		),
+		version,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	var location client.JSONBody
+	if err = client.BodyJSON(res, &location); err != nil {
+		return err
+	}
+
+	link, ok := location["versionLink"].(string)
+	if !ok {
+		return fmt.Errorf("include version creation response is missing \"versionLink\"")
+	}
+
+	req, err = client.NewRequest(Config, "GET", link, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err = client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	versions := NewIncludeVersions()
+	if err = client.BodyJSON(res, versions); err != nil {
+		return err
+	}
+
+	if len(versions.Versions.Items) == 0 {
+		return fmt.Errorf("include version creation response is empty")
+	}
+
+	version.update(versions.Versions.Items[0])
+
+	version.parent.AddVersion(version)
+
+	return nil
+}
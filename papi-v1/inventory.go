@@ -0,0 +1,678 @@
+package papi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Inventory is a cross-reference index of the properties in an account
+//
+// It answers which properties use a given hostname, edge hostname, CP code or
+// origin. An Inventory is built by crawling the account, see BuildInventory(),
+// and can be saved to and loaded from a local file.
+type Inventory struct {
+	BuiltAt    time.Time                     `json:"builtAt"`
+	Properties map[string]*InventoryProperty `json:"properties"`
+
+	mutex         sync.RWMutex
+	hostnames     map[string][]string
+	edgeHostnames map[string][]string
+	cpCodes       map[int][]string
+	origins       map[string][]string
+}
+
+// InventoryProperty is the indexed data of a property
+//
+// The top-level fields describe the latest version. Production and Staging
+// describe the versions active on each network, if any.
+type InventoryProperty struct {
+	AccountID       string            `json:"accountId"`
+	ContractID      string            `json:"contractId"`
+	GroupID         string            `json:"groupId"`
+	PropertyID      string            `json:"propertyId"`
+	PropertyName    string            `json:"propertyName"`
+	PropertyVersion int               `json:"propertyVersion"`
+	Etag            string            `json:"etag"`
+	Hostnames       []string          `json:"hostnames,omitempty"`
+	EdgeHostnames   []string          `json:"edgeHostnames,omitempty"`
+	CpCodes         []int             `json:"cpCodes,omitempty"`
+	Origins         []string          `json:"origins,omitempty"`
+	Production      *InventoryVersion `json:"production,omitempty"`
+	Staging         *InventoryVersion `json:"staging,omitempty"`
+}
+
+// InventoryVersion is the indexed data of a property version
+type InventoryVersion struct {
+	PropertyVersion int      `json:"propertyVersion"`
+	Hostnames       []string `json:"hostnames,omitempty"`
+	EdgeHostnames   []string `json:"edgeHostnames,omitempty"`
+	CpCodes         []int    `json:"cpCodes,omitempty"`
+	Origins         []string `json:"origins,omitempty"`
+}
+
+// NewInventory creates a new, empty Inventory
+func NewInventory() *Inventory {
+	inventory := &Inventory{Properties: map[string]*InventoryProperty{}}
+	inventory.reindex()
+
+	return inventory
+}
+
+// BuildInventory crawls every property in the account and indexes the
+// hostnames and rules of its latest version and of its versions active on
+// the production and staging networks
+//
+// At most concurrency properties are retrieved at the same time, defaults to 1.
+func BuildInventory(concurrency int) (*Inventory, error) {
+	inventory := NewInventory()
+	if err := inventory.Refresh(concurrency); err != nil {
+		return nil, err
+	}
+
+	return inventory, nil
+}
+
+// LoadInventory reads an Inventory previously written with Inventory.Save()
+func LoadInventory(path string) (*Inventory, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := NewInventory()
+	if err := json.Unmarshal(data, inventory); err != nil {
+		return nil, err
+	}
+
+	if inventory.Properties == nil {
+		inventory.Properties = map[string]*InventoryProperty{}
+	}
+	inventory.reindex()
+
+	return inventory, nil
+}
+
+// Save writes the Inventory to a local file as JSON
+func (inventory *Inventory) Save(path string) error {
+	inventory.mutex.RLock()
+	data, err := json.MarshalIndent(inventory, "", "    ")
+	inventory.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Refresh crawls the account and updates the Inventory
+//
+// Properties whose latest version and rule tree etag are unchanged since they
+// were indexed are not retrieved again, nor are already indexed active
+// versions, as activated versions cannot be modified. Properties no longer in
+// the account are removed. Properties that cannot be retrieved keep their
+// previous entry, and the first error encountered is returned once the crawl
+// completes.
+//
+// At most concurrency properties are retrieved at the same time, defaults to 1.
+func (inventory *Inventory) Refresh(concurrency int) error {
+	properties, err := listAccountProperties()
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	for _, property := range properties {
+		seen[property.PropertyID] = true
+	}
+
+	err = inventory.indexProperties(properties, concurrency)
+
+	inventory.mutex.Lock()
+	for propertyID := range inventory.Properties {
+		if !seen[propertyID] {
+			delete(inventory.Properties, propertyID)
+		}
+	}
+	inventory.BuiltAt = time.Now().UTC()
+	inventory.mutex.Unlock()
+
+	inventory.reindex()
+
+	return err
+}
+
+// RefreshHostname re-indexes the properties currently serving a hostname
+//
+// The properties are found using Search(), so hostnames added to a property
+// since the Inventory was built are picked up without a full crawl.
+func (inventory *Inventory) RefreshHostname(hostname string) error {
+	results, err := Search(SearchByHostname, hostname)
+	if err != nil {
+		return err
+	}
+
+	if results == nil {
+		return nil
+	}
+
+	var properties []*Property
+	indexed := map[string]bool{}
+	for _, item := range results.Versions.Items {
+		if indexed[item.PropertyID] {
+			continue
+		}
+		indexed[item.PropertyID] = true
+
+		property := NewProperty(NewProperties())
+		property.PropertyID = item.PropertyID
+		if err := property.GetProperty(); err != nil {
+			return err
+		}
+		properties = append(properties, property)
+	}
+
+	return inventory.indexProperties(properties, 1)
+}
+
+// AddProperty indexes the hostnames and rules of the latest version of a property
+//
+// This is used by Refresh(), and can be used to index property data
+// retrieved by other means. Active versions already indexed for the property
+// are kept, see AddActiveVersion().
+func (inventory *Inventory) AddProperty(property *Property, hostnames *Hostnames, rules *Rules) {
+	latest := newInventoryVersion(property.LatestVersion, hostnames, rules)
+	entry := &InventoryProperty{
+		AccountID:       property.AccountID,
+		ContractID:      property.ContractID,
+		GroupID:         property.GroupID,
+		PropertyID:      property.PropertyID,
+		PropertyName:    property.PropertyName,
+		PropertyVersion: latest.PropertyVersion,
+		Hostnames:       latest.Hostnames,
+		EdgeHostnames:   latest.EdgeHostnames,
+		CpCodes:         latest.CpCodes,
+		Origins:         latest.Origins,
+	}
+
+	if rules != nil {
+		entry.Etag = normalizeEtag(rules.Etag)
+	}
+
+	inventory.mutex.Lock()
+	defer inventory.mutex.Unlock()
+
+	if existing, ok := inventory.Properties[entry.PropertyID]; ok {
+		entry.Production = existing.Production
+		entry.Staging = existing.Staging
+	}
+	inventory.put(entry)
+}
+
+// AddActiveVersion indexes the hostnames and rules of the version of a
+// property active on a network
+//
+// The property must have been added with AddProperty(). A version of 0
+// records that no version is active on the network.
+func (inventory *Inventory) AddActiveVersion(propertyID string, network NetworkValue, version int, hostnames *Hostnames, rules *Rules) error {
+	var active *InventoryVersion
+	if version != 0 {
+		active = newInventoryVersion(version, hostnames, rules)
+	}
+
+	return inventory.setActiveVersion(propertyID, network, active)
+}
+
+// setActiveVersion replaces the version of a property active on a network
+func (inventory *Inventory) setActiveVersion(propertyID string, network NetworkValue, active *InventoryVersion) error {
+	inventory.mutex.Lock()
+	defer inventory.mutex.Unlock()
+
+	existing, ok := inventory.Properties[propertyID]
+	if !ok {
+		return fmt.Errorf("property %s is not in the inventory", propertyID)
+	}
+
+	entry := *existing
+	switch network {
+	case NetworkProduction:
+		entry.Production = active
+	case NetworkStaging:
+		entry.Staging = active
+	default:
+		return fmt.Errorf("unknown network %s", network)
+	}
+	inventory.put(&entry)
+
+	return nil
+}
+
+// FindByHostname returns the properties serving a hostname
+func (inventory *Inventory) FindByHostname(hostname string) []*InventoryProperty {
+	inventory.mutex.RLock()
+	defer inventory.mutex.RUnlock()
+
+	return inventory.lookup(inventory.hostnames[normalizeInventoryHostname(hostname)])
+}
+
+// FindByEdgeHostname returns the properties with hostnames pointing to an edge hostname
+func (inventory *Inventory) FindByEdgeHostname(edgeHostname string) []*InventoryProperty {
+	inventory.mutex.RLock()
+	defer inventory.mutex.RUnlock()
+
+	return inventory.lookup(inventory.edgeHostnames[normalizeInventoryHostname(edgeHostname)])
+}
+
+// FindByCpCode returns the properties using a CP code
+//
+// cpCodeID may be given with or without the "cpc_" prefix
+func (inventory *Inventory) FindByCpCode(cpCodeID string) []*InventoryProperty {
+	var id int
+	if _, err := fmt.Sscanf(strings.TrimPrefix(cpCodeID, "cpc_"), "%d", &id); err != nil {
+		return nil
+	}
+
+	inventory.mutex.RLock()
+	defer inventory.mutex.RUnlock()
+
+	return inventory.lookup(inventory.cpCodes[id])
+}
+
+// FindByOrigin returns the properties using an origin hostname
+func (inventory *Inventory) FindByOrigin(origin string) []*InventoryProperty {
+	inventory.mutex.RLock()
+	defer inventory.mutex.RUnlock()
+
+	return inventory.lookup(inventory.origins[normalizeInventoryHostname(origin)])
+}
+
+func (inventory *Inventory) lookup(propertyIDs []string) []*InventoryProperty {
+	var properties []*InventoryProperty
+	for _, propertyID := range propertyIDs {
+		if property, ok := inventory.Properties[propertyID]; ok {
+			properties = append(properties, property)
+		}
+	}
+
+	return properties
+}
+
+// reindex rebuilds the lookup maps from Inventory.Properties
+//
+// It is used after bulk changes, single properties are indexed by put().
+func (inventory *Inventory) reindex() {
+	inventory.mutex.Lock()
+	defer inventory.mutex.Unlock()
+
+	inventory.hostnames = map[string][]string{}
+	inventory.edgeHostnames = map[string][]string{}
+	inventory.cpCodes = map[int][]string{}
+	inventory.origins = map[string][]string{}
+
+	propertyIDs := make([]string, 0, len(inventory.Properties))
+	for propertyID := range inventory.Properties {
+		propertyIDs = append(propertyIDs, propertyID)
+	}
+	sort.Strings(propertyIDs)
+
+	for _, propertyID := range propertyIDs {
+		inventory.index(inventory.Properties[propertyID])
+	}
+}
+
+// put replaces the entry of a property and updates the lookup maps for it
+//
+// The caller must hold the write lock.
+func (inventory *Inventory) put(entry *InventoryProperty) {
+	if existing, ok := inventory.Properties[entry.PropertyID]; ok {
+		inventory.unindex(existing)
+	}
+
+	inventory.Properties[entry.PropertyID] = entry
+	inventory.index(entry)
+}
+
+// index adds a property to the lookup maps, keeping each list sorted
+func (inventory *Inventory) index(entry *InventoryProperty) {
+	for _, version := range entry.versions() {
+		for _, hostname := range version.Hostnames {
+			inventory.hostnames[hostname] = insertPropertyID(inventory.hostnames[hostname], entry.PropertyID)
+		}
+		for _, edgeHostname := range version.EdgeHostnames {
+			inventory.edgeHostnames[edgeHostname] = insertPropertyID(inventory.edgeHostnames[edgeHostname], entry.PropertyID)
+		}
+		for _, cpCode := range version.CpCodes {
+			inventory.cpCodes[cpCode] = insertPropertyID(inventory.cpCodes[cpCode], entry.PropertyID)
+		}
+		for _, origin := range version.Origins {
+			inventory.origins[origin] = insertPropertyID(inventory.origins[origin], entry.PropertyID)
+		}
+	}
+}
+
+// unindex removes a property from the lookup maps
+func (inventory *Inventory) unindex(entry *InventoryProperty) {
+	for _, version := range entry.versions() {
+		for _, hostname := range version.Hostnames {
+			inventory.hostnames[hostname] = removePropertyID(inventory.hostnames[hostname], entry.PropertyID)
+		}
+		for _, edgeHostname := range version.EdgeHostnames {
+			inventory.edgeHostnames[edgeHostname] = removePropertyID(inventory.edgeHostnames[edgeHostname], entry.PropertyID)
+		}
+		for _, cpCode := range version.CpCodes {
+			inventory.cpCodes[cpCode] = removePropertyID(inventory.cpCodes[cpCode], entry.PropertyID)
+		}
+		for _, origin := range version.Origins {
+			inventory.origins[origin] = removePropertyID(inventory.origins[origin], entry.PropertyID)
+		}
+	}
+}
+
+// versions returns the latest version and the active versions of a property
+func (entry *InventoryProperty) versions() []*InventoryVersion {
+	versions := []*InventoryVersion{{
+		PropertyVersion: entry.PropertyVersion,
+		Hostnames:       entry.Hostnames,
+		EdgeHostnames:   entry.EdgeHostnames,
+		CpCodes:         entry.CpCodes,
+		Origins:         entry.Origins,
+	}}
+
+	for _, active := range []*InventoryVersion{entry.Production, entry.Staging} {
+		if active != nil {
+			versions = append(versions, active)
+		}
+	}
+
+	return versions
+}
+
+// indexProperties retrieves and indexes properties using at most
+// concurrency workers, skipping properties that have not changed
+func (inventory *Inventory) indexProperties(properties []*Property, concurrency int) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var firstErr error
+	var errMutex sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan *Property)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for property := range queue {
+				if err := inventory.indexProperty(property); err != nil {
+					errMutex.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("unable to index property %s: %s", property.PropertyID, err)
+					}
+					errMutex.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, property := range properties {
+		queue <- property
+	}
+	close(queue)
+	wg.Wait()
+
+	return firstErr
+}
+
+func (inventory *Inventory) indexProperty(property *Property) error {
+	inventory.mutex.RLock()
+	existing, ok := inventory.Properties[property.PropertyID]
+	inventory.mutex.RUnlock()
+
+	unchanged := false
+	if ok && existing.PropertyVersion == property.LatestVersion && existing.Etag != "" {
+		etag, err := property.GetRulesDigest()
+		if err != nil {
+			return err
+		}
+
+		unchanged = normalizeEtag(etag) == existing.Etag
+	}
+
+	if !unchanged {
+		hostnames, rules, err := getInventoryVersion(property, property.LatestVersion)
+		if err != nil {
+			return err
+		}
+
+		inventory.AddProperty(property, hostnames, rules)
+	}
+
+	active := map[NetworkValue]int{
+		NetworkProduction: property.ProductionVersion,
+		NetworkStaging:    property.StagingVersion,
+	}
+	for network, version := range active {
+		if ok && existing.activeVersion(network) == version {
+			continue
+		}
+
+		if version == 0 || version == property.LatestVersion {
+			var active *InventoryVersion
+			if version != 0 {
+				active = inventory.latestVersion(property.PropertyID)
+			}
+
+			if err := inventory.setActiveVersion(property.PropertyID, network, active); err != nil {
+				return err
+			}
+			continue
+		}
+
+		hostnames, rules, err := getInventoryVersion(property, version)
+		if err != nil {
+			return err
+		}
+
+		if err := inventory.AddActiveVersion(property.PropertyID, network, version, hostnames, rules); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// latestVersion returns the indexed latest version of a property
+func (inventory *Inventory) latestVersion(propertyID string) *InventoryVersion {
+	inventory.mutex.RLock()
+	defer inventory.mutex.RUnlock()
+
+	entry, ok := inventory.Properties[propertyID]
+	if !ok {
+		return nil
+	}
+
+	return entry.versions()[0]
+}
+
+// activeVersion returns the indexed version active on a network, 0 if none
+func (entry *InventoryProperty) activeVersion(network NetworkValue) int {
+	active := entry.Production
+	if network == NetworkStaging {
+		active = entry.Staging
+	}
+
+	if active == nil {
+		return 0
+	}
+
+	return active.PropertyVersion
+}
+
+// getInventoryVersion retrieves the hostnames and rules of a property version
+func getInventoryVersion(property *Property, propertyVersion int) (*Hostnames, *Rules, error) {
+	versionProperty := *property
+	versionProperty.LatestVersion = propertyVersion
+
+	rules, err := versionProperty.GetRules()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	version := NewVersion(NewVersions())
+	version.PropertyVersion = propertyVersion
+
+	hostnames, err := property.GetHostnames(version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return hostnames, rules, nil
+}
+
+// newInventoryVersion collects the hostnames, edge hostnames, CP codes and
+// origins of a property version
+func newInventoryVersion(propertyVersion int, hostnames *Hostnames, rules *Rules) *InventoryVersion {
+	version := &InventoryVersion{PropertyVersion: propertyVersion}
+
+	if hostnames != nil {
+		for _, hostname := range hostnames.Hostnames.Items {
+			version.Hostnames = appendUnique(version.Hostnames, normalizeInventoryHostname(hostname.CnameFrom))
+			if hostname.CnameTo != "" {
+				version.EdgeHostnames = appendUnique(version.EdgeHostnames, normalizeInventoryHostname(hostname.CnameTo))
+			}
+		}
+	}
+
+	if rules != nil && rules.Rule != nil {
+		indexRule(version, rules.Rule)
+	}
+
+	sort.Strings(version.Hostnames)
+	sort.Strings(version.EdgeHostnames)
+	sort.Ints(version.CpCodes)
+	sort.Strings(version.Origins)
+
+	return version
+}
+
+// listAccountProperties lists every property of every contract and group
+func listAccountProperties() ([]*Property, error) {
+	contracts, err := GetContracts()
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := GetGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	var properties []*Property
+	listed := map[string]bool{}
+	for _, group := range groups.Groups.Items {
+		for _, contractID := range group.ContractIDs {
+			contract, err := contracts.FindContract(contractID)
+			if err != nil {
+				continue
+			}
+
+			groupProperties, err := GetProperties(contract, group)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, property := range groupProperties.Properties.Items {
+				if listed[property.PropertyID] {
+					continue
+				}
+				listed[property.PropertyID] = true
+				properties = append(properties, property)
+			}
+		}
+	}
+
+	return properties, nil
+}
+
+// indexRule collects CP codes and origins from the behaviors of a rule and its children
+func indexRule(entry *InventoryVersion, rule *Rule) {
+	for _, behavior := range rule.Behaviors {
+		switch behavior.Name {
+		case "cpCode":
+			if value, ok := behavior.Options["value"].(map[string]interface{}); ok {
+				if id, ok := value["id"].(float64); ok {
+					entry.CpCodes = appendUniqueInt(entry.CpCodes, int(id))
+				}
+			}
+		case "origin":
+			if hostname, ok := behavior.Options["hostname"].(string); ok && hostname != "" {
+				entry.Origins = appendUnique(entry.Origins, normalizeInventoryHostname(hostname))
+			} else if netStorage, ok := behavior.Options["netStorage"].(map[string]interface{}); ok {
+				if domain, ok := netStorage["downloadDomainName"].(string); ok && domain != "" {
+					entry.Origins = appendUnique(entry.Origins, normalizeInventoryHostname(domain))
+				}
+			}
+		}
+	}
+
+	for _, child := range rule.Children {
+		indexRule(entry, child)
+	}
+}
+
+func normalizeInventoryHostname(hostname string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(hostname), "."))
+}
+
+func normalizeEtag(etag string) string {
+	return strings.Trim(strings.TrimPrefix(etag, "W/"), "\"")
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
+
+func appendUniqueInt(values []int, value int) []int {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
+
+// insertPropertyID adds a property ID to a sorted list, if not already present
+func insertPropertyID(propertyIDs []string, propertyID string) []string {
+	i := sort.SearchStrings(propertyIDs, propertyID)
+	if i < len(propertyIDs) && propertyIDs[i] == propertyID {
+		return propertyIDs
+	}
+
+	propertyIDs = append(propertyIDs, "")
+	copy(propertyIDs[i+1:], propertyIDs[i:])
+	propertyIDs[i] = propertyID
+
+	return propertyIDs
+}
+
+// removePropertyID removes a property ID from a sorted list
+func removePropertyID(propertyIDs []string, propertyID string) []string {
+	i := sort.SearchStrings(propertyIDs, propertyID)
+	if i == len(propertyIDs) || propertyIDs[i] != propertyID {
+		return propertyIDs
+	}
+
+	return append(propertyIDs[:i], propertyIDs[i+1:]...)
+}
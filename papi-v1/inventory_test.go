@@ -0,0 +1,190 @@
+package papi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func newInventoryTestData(t *testing.T, propertyID string, hostname string, cpCode int, origin string) (*Property, *Hostnames, *Rules) {
+	property := NewProperty(NewProperties())
+	property.PropertyID = propertyID
+	property.PropertyName = propertyID + ".example.com"
+	property.ContractID = "ctr_1"
+	property.GroupID = "grp_1"
+	property.LatestVersion = 2
+
+	hostnames := NewHostnames()
+	item := hostnames.NewHostname()
+	item.CnameFrom = hostname
+	item.CnameTo = "example.com.edgesuite.net"
+
+	rules := NewRules()
+	rules.Etag = "\"etag-" + propertyID + "\""
+	err := json.Unmarshal([]byte(`{
+		"name": "default",
+		"behaviors": [
+			{"name": "origin", "options": {"originType": "CUSTOMER", "hostname": "`+origin+`"}},
+			{"name": "cpCode", "options": {"value": {"id": `+strconv.Itoa(cpCode)+`}}}
+		],
+		"children": [
+			{"name": "Images", "behaviors": [{"name": "cpCode", "options": {"value": {"id": 9}}}]}
+		]
+	}`), rules.Rule)
+	assert.NoError(t, err)
+
+	return property, hostnames, rules
+}
+
+func TestInventory_AddProperty(t *testing.T) {
+	inventory := NewInventory()
+	inventory.AddProperty(newInventoryTestData(t, "prp_1", "www.example.com", 1, "origin.example.com"))
+	inventory.AddProperty(newInventoryTestData(t, "prp_2", "api.example.com", 2, "Origin.Example.com."))
+
+	found := inventory.FindByHostname("WWW.example.com.")
+	assert.Len(t, found, 1)
+	assert.Equal(t, "prp_1", found[0].PropertyID)
+	assert.Equal(t, "etag-prp_1", found[0].Etag)
+	assert.Equal(t, []int{1, 9}, found[0].CpCodes)
+
+	assert.Len(t, inventory.FindByOrigin("origin.example.com"), 2)
+	assert.Len(t, inventory.FindByCpCode("cpc_9"), 2)
+	assert.Len(t, inventory.FindByCpCode("2"), 1)
+	assert.Len(t, inventory.FindByCpCode("bogus"), 0)
+	assert.Len(t, inventory.FindByEdgeHostname("example.com.edgesuite.net"), 2)
+}
+
+func TestInventory_SaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "inventory")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	inventory := NewInventory()
+	inventory.AddProperty(newInventoryTestData(t, "prp_1", "www.example.com", 1, "origin.example.com"))
+
+	path := filepath.Join(dir, "inventory.json")
+	assert.NoError(t, inventory.Save(path))
+
+	loaded, err := LoadInventory(path)
+	assert.NoError(t, err)
+	assert.Equal(t, inventory.Properties, loaded.Properties)
+	assert.Len(t, loaded.FindByHostname("www.example.com"), 1)
+}
+
+func TestInventory_AddProperty_Replaces(t *testing.T) {
+	inventory := NewInventory()
+	inventory.AddProperty(newInventoryTestData(t, "prp_1", "www.example.com", 1, "origin.example.com"))
+	inventory.AddProperty(newInventoryTestData(t, "prp_2", "www.example.com", 2, "origin.example.com"))
+	inventory.AddProperty(newInventoryTestData(t, "prp_1", "api.example.com", 3, "origin.example.com"))
+
+	found := inventory.FindByHostname("www.example.com")
+	assert.Len(t, found, 1)
+	assert.Equal(t, "prp_2", found[0].PropertyID)
+	assert.Len(t, inventory.FindByHostname("api.example.com"), 1)
+	assert.Len(t, inventory.FindByCpCode("1"), 0)
+	assert.Len(t, inventory.FindByCpCode("9"), 2)
+	assert.Len(t, inventory.FindByOrigin("origin.example.com"), 2)
+}
+
+func TestInventory_AddActiveVersion(t *testing.T) {
+	inventory := NewInventory()
+	property, hostnames, rules := newInventoryTestData(t, "prp_1", "www.example.com", 1, "origin.example.com")
+	inventory.AddProperty(property, hostnames, rules)
+
+	_, activeHostnames, activeRules := newInventoryTestData(t, "prp_1", "old.example.com", 5, "old-origin.example.com")
+	err := inventory.AddActiveVersion("prp_1", NetworkProduction, 1, activeHostnames, activeRules)
+	assert.NoError(t, err)
+
+	found := inventory.FindByHostname("old.example.com")
+	assert.Len(t, found, 1)
+	assert.Equal(t, 1, found[0].Production.PropertyVersion)
+	assert.Len(t, inventory.FindByHostname("www.example.com"), 1)
+	assert.Len(t, inventory.FindByCpCode("5"), 1)
+
+	// Indexing a new latest version keeps the active version
+	inventory.AddProperty(property, hostnames, rules)
+	assert.Len(t, inventory.FindByHostname("old.example.com"), 1)
+
+	err = inventory.AddActiveVersion("prp_1", NetworkProduction, 0, nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, inventory.FindByHostname("old.example.com"), 0)
+	assert.Len(t, inventory.FindByOrigin("old-origin.example.com"), 0)
+
+	err = inventory.AddActiveVersion("prp_2", NetworkStaging, 1, activeHostnames, activeRules)
+	assert.Error(t, err)
+}
+
+func TestInventory_IndexProperty_ActiveVersions(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions/3/rules").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": 3, "etag": "etag-3", "rules": {"name": "default"}}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions/3/hostnames/").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"hostnames": {"items": [{"cnameFrom": "new.example.com", "cnameTo": "new.example.com.edgesuite.net"}]}}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions/1/rules").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": 1, "rules": {"name": "default"}}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/versions/1/hostnames/").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"hostnames": {"items": [{"cnameFrom": "old.example.com", "cnameTo": "old.example.com.edgesuite.net"}]}}`)
+
+	Init(config)
+
+	property := NewProperty(NewProperties())
+	property.Contract = NewContract(NewContracts())
+	property.Contract.ContractID = "ctr_1"
+	property.Group = NewGroup(NewGroups())
+	property.Group.GroupID = "grp_1"
+	property.PropertyID = "prp_1"
+	property.LatestVersion = 3
+	property.StagingVersion = 3
+	property.ProductionVersion = 1
+
+	inventory := NewInventory()
+	err := inventory.indexProperty(property)
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	found := inventory.FindByEdgeHostname("old.example.com.edgesuite.net")
+	assert.Len(t, found, 1)
+	assert.Equal(t, 1, found[0].Production.PropertyVersion)
+	assert.Equal(t, 3, found[0].Staging.PropertyVersion)
+	assert.Equal(t, []string{"new.example.com"}, found[0].Staging.Hostnames)
+
+	// Active versions already indexed are not retrieved again
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Head("/papi/v1/properties/prp_1/versions/3/rules").
+		Reply(200).
+		SetHeader("Etag", "\"etag-3\"")
+
+	err = inventory.indexProperty(property)
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,377 @@
+package papi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LintCheck is a rule tree check run by a Linter
+//
+// CheckRule is called for every rule in the tree, parents before children.
+type LintCheck interface {
+	Name() string
+	CheckRule(context *LintRuleContext) []*LintFinding
+}
+
+// LintTreeCheck is implemented by checks that report on the tree as a whole
+//
+// Start is called at the beginning of every Linter.Lint() call, and the
+// returned LintTreeRun is given every rule in place of CheckRule, so that
+// state is never shared between concurrent calls.
+type LintTreeCheck interface {
+	LintCheck
+	Start() LintTreeRun
+}
+
+// LintTreeRun is the state of a LintTreeCheck for a single rule tree
+//
+// Finish is called once all rules have been passed to CheckRule.
+type LintTreeRun interface {
+	CheckRule(context *LintRuleContext) []*LintFinding
+	Finish() []*LintFinding
+}
+
+// LintRuleContext is a rule as seen by a LintCheck
+type LintRuleContext struct {
+	Rules *Rules
+	Rule  *Rule
+	// Path is the rule names from the default rule joined by "/", e.g. "/Static Content/Images"
+	Path string
+	// Pointer is the JSON Pointer of the rule, e.g. "/rules/children/0/children/1"
+	Pointer string
+	// Ancestors are the parents of Rule, starting with the default rule
+	Ancestors []*Rule
+	// Criteria are the criteria of the ancestors followed by those of Rule,
+	// all of which must match for the behaviors of Rule to apply
+	Criteria []*Criteria
+}
+
+// HasCriteria reports whether a criteria of the given name applies to the rule
+func (context *LintRuleContext) HasCriteria(name string) bool {
+	for _, criteria := range context.Criteria {
+		if criteria.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Finding creates a finding for the rule
+func (context *LintRuleContext) Finding(severity LintSeverityValue, format string, args ...interface{}) *LintFinding {
+	return &LintFinding{
+		Severity: severity,
+		Path:     context.Path,
+		Pointer:  context.Pointer,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// LintFinding is a problem found by a LintCheck
+type LintFinding struct {
+	Check    string            `json:"check"`
+	Severity LintSeverityValue `json:"severity"`
+	Path     string            `json:"path"`
+	Pointer  string            `json:"pointer"`
+	Message  string            `json:"message"`
+}
+
+// String formats a finding as a single line
+func (finding *LintFinding) String() string {
+	return fmt.Sprintf("%s: %s: %s [%s]", finding.Severity, finding.Path, finding.Message, finding.Check)
+}
+
+// Linter runs LintChecks over rule trees
+type Linter struct {
+	Checks []LintCheck
+}
+
+// NewLinter creates a new Linter
+func NewLinter(checks ...LintCheck) *Linter {
+	return &Linter{Checks: checks}
+}
+
+// AddCheck adds a check to the Linter
+func (linter *Linter) AddCheck(check LintCheck) {
+	linter.Checks = append(linter.Checks, check)
+}
+
+// Lint runs every check over a rule tree
+//
+// Findings are returned in rule order, followed by findings from
+// LintTreeCheck.Finish(). Findings without a Check name are given the name of
+// the check that reported them.
+func (linter *Linter) Lint(rules *Rules) *LintResult {
+	result := &LintResult{}
+	if rules == nil || rules.Rule == nil {
+		return result
+	}
+
+	runs := make([]LintTreeRun, len(linter.Checks))
+	for key, check := range linter.Checks {
+		if treeCheck, ok := check.(LintTreeCheck); ok {
+			runs[key] = treeCheck.Start()
+		}
+	}
+
+	var walk func(rule *Rule, path string, pointer string, ancestors []*Rule, criteria []*Criteria)
+	walk = func(rule *Rule, path string, pointer string, ancestors []*Rule, criteria []*Criteria) {
+		ruleCriteria := append(append([]*Criteria{}, criteria...), rule.Criteria...)
+		context := &LintRuleContext{
+			Rules:     rules,
+			Rule:      rule,
+			Path:      path,
+			Pointer:   pointer,
+			Ancestors: ancestors,
+			Criteria:  ruleCriteria,
+		}
+
+		for key, check := range linter.Checks {
+			if runs[key] != nil {
+				result.add(check, runs[key].CheckRule(context))
+			} else {
+				result.add(check, check.CheckRule(context))
+			}
+		}
+
+		childAncestors := append(append([]*Rule{}, ancestors...), rule)
+		for key, child := range rule.Children {
+			childPath := strings.TrimSuffix(path, "/") + "/" + child.Name
+			childPointer := pointer + "/children/" + strconv.Itoa(key)
+			walk(child, childPath, childPointer, childAncestors, ruleCriteria)
+		}
+	}
+
+	walk(rules.Rule, "/", "/rules", nil, nil)
+
+	for key, check := range linter.Checks {
+		if runs[key] != nil {
+			result.add(check, runs[key].Finish())
+		}
+	}
+
+	return result
+}
+
+// LintResult is the outcome of Linter.Lint()
+type LintResult struct {
+	Findings []*LintFinding `json:"findings"`
+}
+
+func (result *LintResult) add(check LintCheck, findings []*LintFinding) {
+	for _, finding := range findings {
+		if finding.Check == "" {
+			finding.Check = check.Name()
+		}
+		result.Findings = append(result.Findings, finding)
+	}
+}
+
+// HasErrors reports whether any finding has LintSeverityError
+func (result *LintResult) HasErrors() bool {
+	return len(result.AtLeast(LintSeverityError)) != 0
+}
+
+// AtLeast returns the findings of the given severity or higher
+func (result *LintResult) AtLeast(severity LintSeverityValue) []*LintFinding {
+	var findings []*LintFinding
+	for _, finding := range result.Findings {
+		if finding.Severity.rank() >= severity.rank() {
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+// LintCheckFunc creates a LintCheck from a function
+func LintCheckFunc(name string, check func(context *LintRuleContext) []*LintFinding) LintCheck {
+	return &lintCheckFunc{name: name, check: check}
+}
+
+type lintCheckFunc struct {
+	name  string
+	check func(context *LintRuleContext) []*LintFinding
+}
+
+func (check *lintCheckFunc) Name() string {
+	return check.name
+}
+
+func (check *lintCheckFunc) CheckRule(context *LintRuleContext) []*LintFinding {
+	return check.check(context)
+}
+
+// NewRequireBehaviorCheck reports a rule tree without a given behavior
+//
+// Only behaviors that apply to every request count, i.e. those in rules
+// where neither the rule nor its ancestors have criteria.
+func NewRequireBehaviorCheck(behaviorName string, severity LintSeverityValue) LintCheck {
+	return &requireBehaviorCheck{behaviorName: behaviorName, severity: severity}
+}
+
+type requireBehaviorCheck struct {
+	behaviorName string
+	severity     LintSeverityValue
+}
+
+func (check *requireBehaviorCheck) Name() string {
+	return "require-" + check.behaviorName
+}
+
+// CheckRule is unused, rules are passed to the LintTreeRun returned by Start()
+func (check *requireBehaviorCheck) CheckRule(context *LintRuleContext) []*LintFinding {
+	return nil
+}
+
+func (check *requireBehaviorCheck) Start() LintTreeRun {
+	return &requireBehaviorRun{check: check}
+}
+
+type requireBehaviorRun struct {
+	check *requireBehaviorCheck
+	found bool
+}
+
+func (run *requireBehaviorRun) CheckRule(context *LintRuleContext) []*LintFinding {
+	if len(context.Criteria) != 0 {
+		return nil
+	}
+
+	for _, behavior := range context.Rule.Behaviors {
+		if behavior.Name == run.check.behaviorName {
+			run.found = true
+		}
+	}
+
+	return nil
+}
+
+func (run *requireBehaviorRun) Finish() []*LintFinding {
+	if run.found {
+		return nil
+	}
+
+	return []*LintFinding{{
+		Severity: run.check.severity,
+		Path:     "/",
+		Pointer:  "/rules",
+		Message:  fmt.Sprintf("the rule tree has no unconditional %s behavior", run.check.behaviorName),
+	}}
+}
+
+// NewZeroTTLCachingCheck reports caching behaviors with a TTL of zero in rules
+// matching any of the given criteria, e.g. "path" or "fileExtension"
+func NewZeroTTLCachingCheck(severity LintSeverityValue, criteriaNames ...string) LintCheck {
+	return LintCheckFunc("zero-ttl-caching", func(context *LintRuleContext) []*LintFinding {
+		matched := false
+		for _, name := range criteriaNames {
+			if context.HasCriteria(name) {
+				matched = true
+			}
+		}
+
+		if !matched {
+			return nil
+		}
+
+		var findings []*LintFinding
+		for _, behavior := range context.Rule.Behaviors {
+			if behavior.Name != "caching" {
+				continue
+			}
+
+			cachingBehavior, _ := behavior.Options["behavior"].(string)
+			if cachingBehavior == "NO_STORE" || cachingBehavior == "BYPASS_CACHE" {
+				continue
+			}
+
+			if ttl, ok := behavior.Options["ttl"].(string); ok && isZeroTTL(ttl) {
+				findings = append(findings, context.Finding(severity, "caching has a TTL of %s", ttl))
+			}
+		}
+
+		return findings
+	})
+}
+
+// NewOriginTLSCheck reports customer origins not using TLS with certificate
+// verification
+func NewOriginTLSCheck(severity LintSeverityValue) LintCheck {
+	return LintCheckFunc("origin-tls", func(context *LintRuleContext) []*LintFinding {
+		var findings []*LintFinding
+		for _, behavior := range context.Rule.Behaviors {
+			if behavior.Name != "origin" {
+				continue
+			}
+
+			if originType, _ := behavior.Options["originType"].(string); originType != "" && originType != "CUSTOMER" {
+				continue
+			}
+
+			if httpsPort, _ := behavior.Options["httpsPort"].(float64); httpsPort == 0 {
+				findings = append(findings, context.Finding(severity, "origin has no httpsPort"))
+			}
+
+			if verificationMode, _ := behavior.Options["verificationMode"].(string); verificationMode == "" {
+				findings = append(findings, context.Finding(severity, "origin has no certificate verificationMode"))
+			}
+		}
+
+		return findings
+	})
+}
+
+// NewSureRouteTestObjectCheck reports enabled sureRoute behaviors without a test object
+func NewSureRouteTestObjectCheck(severity LintSeverityValue) LintCheck {
+	return LintCheckFunc("sureroute-test-object", func(context *LintRuleContext) []*LintFinding {
+		var findings []*LintFinding
+		for _, behavior := range context.Rule.Behaviors {
+			if behavior.Name != "sureRoute" {
+				continue
+			}
+
+			if enabled, _ := behavior.Options["enabled"].(bool); !enabled {
+				continue
+			}
+
+			if testObjectURL, _ := behavior.Options["testObjectUrl"].(string); testObjectURL == "" {
+				findings = append(findings, context.Finding(severity, "sureRoute has no testObjectUrl"))
+			}
+		}
+
+		return findings
+	})
+}
+
+// isZeroTTL reports whether a duration such as "0s", "0m" or "0" is zero
+func isZeroTTL(ttl string) bool {
+	value := strings.TrimRight(strings.TrimSpace(ttl), "smhd")
+	number, err := strconv.Atoi(value)
+
+	return err == nil && number == 0
+}
+
+// LintSeverityValue is used to create an "enum" of possible LintFinding.Severity values
+type LintSeverityValue string
+
+const (
+	// LintSeverityInfo LintFinding.Severity value INFO
+	LintSeverityInfo LintSeverityValue = "INFO"
+	// LintSeverityWarning LintFinding.Severity value WARNING
+	LintSeverityWarning LintSeverityValue = "WARNING"
+	// LintSeverityError LintFinding.Severity value ERROR
+	LintSeverityError LintSeverityValue = "ERROR"
+)
+
+func (severity LintSeverityValue) rank() int {
+	switch severity {
+	case LintSeverityError:
+		return 2
+	case LintSeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
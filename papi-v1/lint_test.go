@@ -0,0 +1,113 @@
+package papi
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinter_Lint(t *testing.T) {
+	rules := NewRules()
+	err := json.Unmarshal([]byte(`{
+		"name": "default",
+		"behaviors": [
+			{"name": "origin", "options": {"originType": "CUSTOMER", "hostname": "origin.example.com", "httpPort": 80}},
+			{"name": "sureRoute", "options": {"enabled": true, "testObjectUrl": ""}}
+		],
+		"children": [
+			{
+				"name": "Static Content",
+				"criteria": [{"name": "fileExtension", "options": {"matchOperator": "IS_ONE_OF", "values": ["css", "js"]}}],
+				"children": [
+					{
+						"name": "Images",
+						"behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "0s"}}]
+					}
+				]
+			},
+			{
+				"name": "Dynamic Content",
+				"behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "0s"}}]
+			}
+		]
+	}`), rules.Rule)
+	assert.NoError(t, err)
+
+	linter := NewLinter(
+		NewRequireBehaviorCheck("cpCode", LintSeverityError),
+		NewZeroTTLCachingCheck(LintSeverityWarning, "path", "fileExtension"),
+		NewOriginTLSCheck(LintSeverityError),
+		NewSureRouteTestObjectCheck(LintSeverityWarning),
+	)
+
+	result := linter.Lint(rules)
+
+	assert.Len(t, result.Findings, 5)
+	assert.Equal(t, "origin-tls", result.Findings[0].Check)
+	assert.Equal(t, "/", result.Findings[0].Path)
+	assert.Equal(t, "sureroute-test-object", result.Findings[2].Check)
+
+	assert.Equal(t, "zero-ttl-caching", result.Findings[3].Check)
+	assert.Equal(t, "/Static Content/Images", result.Findings[3].Path)
+	assert.Equal(t, "/rules/children/0/children/0", result.Findings[3].Pointer)
+
+	assert.Equal(t, "require-cpCode", result.Findings[4].Check)
+	assert.True(t, result.HasErrors())
+	assert.Len(t, result.AtLeast(LintSeverityError), 3)
+
+	rules.Rule.AddBehavior(NewBehavior())
+	rules.Rule.Behaviors[len(rules.Rule.Behaviors)-1].Name = "cpCode"
+	result = linter.Lint(rules)
+	assert.Len(t, result.Findings, 4)
+}
+
+func TestRequireBehaviorCheck_Conditional(t *testing.T) {
+	rules := NewRules()
+	err := json.Unmarshal([]byte(`{
+		"name": "default",
+		"children": [
+			{
+				"name": "Images",
+				"criteria": [{"name": "fileExtension", "options": {"matchOperator": "IS_ONE_OF", "values": ["jpg"]}}],
+				"children": [{"name": "Large", "behaviors": [{"name": "cpCode", "options": {"value": {"id": 1}}}]}]
+			}
+		]
+	}`), rules.Rule)
+	assert.NoError(t, err)
+
+	linter := NewLinter(NewRequireBehaviorCheck("cpCode", LintSeverityError))
+	result := linter.Lint(rules)
+	assert.Len(t, result.Findings, 1)
+	assert.Equal(t, "require-cpCode", result.Findings[0].Check)
+
+	// Behaviors in child rules without criteria still apply to every request
+	rules.Rule.Children = append(rules.Rule.Children, &Rule{
+		Name:      "Default CP Code",
+		Behaviors: []*Behavior{{Name: "cpCode"}},
+	})
+	assert.Len(t, linter.Lint(rules).Findings, 0)
+}
+
+func TestRequireBehaviorCheck_Concurrent(t *testing.T) {
+	without := NewRules()
+	with := NewRules()
+	with.Rule.Behaviors = []*Behavior{{Name: "cpCode"}}
+
+	linter := NewLinter(NewRequireBehaviorCheck("cpCode", LintSeverityError))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.Len(t, linter.Lint(without).Findings, 1)
+		}()
+		go func() {
+			defer wg.Done()
+			assert.Len(t, linter.Lint(with).Findings, 0)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,153 @@
+package papi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PatchOperation represents a single RFC 6902 JSON Patch operation
+//
+// See: https://tools.ietf.org/html/rfc6902
+type PatchOperation struct {
+	Op    PatchOpValue `json:"op"`
+	Path  string       `json:"path"`
+	From  string       `json:"from,omitempty"`
+	Value interface{}  `json:"value,omitempty"`
+}
+
+// NewPatchOperation creates a new PatchOperation
+func NewPatchOperation(op PatchOpValue, path string, value interface{}) *PatchOperation {
+	return &PatchOperation{Op: op, Path: path, Value: value}
+}
+
+// PatchOpValue is used to create an "enum" of possible PatchOperation.Op values
+type PatchOpValue string
+
+const (
+	// PatchOpAdd PatchOperation.Op value add
+	PatchOpAdd PatchOpValue = "add"
+	// PatchOpRemove PatchOperation.Op value remove
+	PatchOpRemove PatchOpValue = "remove"
+	// PatchOpReplace PatchOperation.Op value replace
+	PatchOpReplace PatchOpValue = "replace"
+	// PatchOpMove PatchOperation.Op value move
+	PatchOpMove PatchOpValue = "move"
+	// PatchOpCopy PatchOperation.Op value copy
+	PatchOpCopy PatchOpValue = "copy"
+	// PatchOpTest PatchOperation.Op value test
+	PatchOpTest PatchOpValue = "test"
+)
+
+// DiffRules generates the JSON Patch operations that transform the rule tree
+// of original into the rule tree of modified
+//
+// Objects are compared key by key and arrays index by index; elements added
+// to or removed from the end of an array become add and remove operations,
+// any other change becomes a replace operation. Paths are relative to the
+// rule tree document, e.g. /rules/children/0/behaviors/1/options/ttl
+//
+// See: Rules.Patch()
+func DiffRules(original *Rules, modified *Rules) ([]*PatchOperation, error) {
+	from, err := toJSONValue(original.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := toJSONValue(modified.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	patches := []*PatchOperation{}
+	diffJSONValue("/rules", from, to, &patches)
+
+	return patches, nil
+}
+
+func toJSONValue(data interface{}) (interface{}, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func diffJSONValue(path string, from interface{}, to interface{}, patches *[]*PatchOperation) {
+	switch fromValue := from.(type) {
+	case map[string]interface{}:
+		if toValue, ok := to.(map[string]interface{}); ok {
+			diffJSONObject(path, fromValue, toValue, patches)
+			return
+		}
+	case []interface{}:
+		if toValue, ok := to.([]interface{}); ok {
+			diffJSONArray(path, fromValue, toValue, patches)
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*patches = append(*patches, NewPatchOperation(PatchOpReplace, path, to))
+	}
+}
+
+func diffJSONObject(path string, from map[string]interface{}, to map[string]interface{}, patches *[]*PatchOperation) {
+	keys := make([]string, 0, len(from)+len(to))
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyPath := path + "/" + escapeJSONPointer(key)
+		fromValue, inFrom := from[key]
+		toValue, inTo := to[key]
+
+		switch {
+		case !inTo:
+			*patches = append(*patches, NewPatchOperation(PatchOpRemove, keyPath, nil))
+		case !inFrom:
+			*patches = append(*patches, NewPatchOperation(PatchOpAdd, keyPath, toValue))
+		default:
+			diffJSONValue(keyPath, fromValue, toValue, patches)
+		}
+	}
+}
+
+func diffJSONArray(path string, from []interface{}, to []interface{}, patches *[]*PatchOperation) {
+	common := len(from)
+	if len(to) < common {
+		common = len(to)
+	}
+
+	for i := 0; i < common; i++ {
+		diffJSONValue(path+"/"+strconv.Itoa(i), from[i], to[i], patches)
+	}
+
+	for i := common; i < len(to); i++ {
+		*patches = append(*patches, NewPatchOperation(PatchOpAdd, path+"/"+strconv.Itoa(i), to[i]))
+	}
+
+	// Remove from the end so earlier indexes remain valid
+	for i := len(from) - 1; i >= common; i-- {
+		*patches = append(*patches, NewPatchOperation(PatchOpRemove, path+"/"+strconv.Itoa(i), nil))
+	}
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
@@ -0,0 +1,72 @@
+package papi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestDiffRules(t *testing.T) {
+	original := NewRules()
+	original.Rule.AddBehavior(&Behavior{Name: "origin", Options: OptionValue{"hostname": "old.example.com"}})
+	original.Rule.AddBehavior(&Behavior{Name: "cpCode", Options: OptionValue{"value": OptionValue{"id": 1}}})
+	child := NewRule()
+	child.Name = "Static/Images"
+	original.Rule.AddChildRule(child)
+
+	modified := NewRules()
+	modified.Rule.AddBehavior(&Behavior{Name: "origin", Options: OptionValue{"hostname": "new.example.com"}})
+	modified.Rule.Comments = "Rotated origin"
+
+	patches, err := DiffRules(original, modified)
+	assert.NoError(t, err)
+	assert.Equal(t, []*PatchOperation{
+		{Op: PatchOpReplace, Path: "/rules/behaviors/0/options/hostname", Value: "new.example.com"},
+		{Op: PatchOpRemove, Path: "/rules/behaviors/1"},
+		{Op: PatchOpRemove, Path: "/rules/children"},
+		{Op: PatchOpAdd, Path: "/rules/comments", Value: "Rotated origin"},
+	}, patches)
+
+	patches, err = DiffRules(modified, modified)
+	assert.NoError(t, err)
+	assert.Empty(t, patches)
+}
+
+func TestRules_Patch(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Patch("/papi/v1/properties/prp_1/versions/2/rules").
+		MatchHeader("Content-Type", "application/json-patch\\+json").
+		MatchHeader("If-Match", "\"a9dfe78cf93090516bde891d009eaf57\"").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_1",
+			"propertyVersion": 2,
+			"etag": "f2b5a7bb4e2bbe87c4a7f1f3b6c8a6a1",
+			"ruleFormat": "v2018-02-27",
+			"rules": {
+				"name": "default",
+				"comments": "Rotated origin"
+			}
+		}`)
+
+	Init(config)
+
+	rules := NewRules()
+	rules.PropertyID = "prp_1"
+	rules.PropertyVersion = 2
+	rules.Etag = "a9dfe78cf93090516bde891d009eaf57"
+
+	err := rules.Patch([]*PatchOperation{
+		NewPatchOperation(PatchOpAdd, "/rules/comments", "Rotated origin"),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "f2b5a7bb4e2bbe87c4a7f1f3b6c8a6a1", rules.Etag)
+	assert.Equal(t, "Rotated origin", rules.Rule.Comments)
+}
@@ -327,6 +327,39 @@ func (property *Property) GetHostnames(version *Version) (*Hostnames, error) {
 	return hostnames, nil
 }
 
+// GetHostnameBucket retrieves the hostname bucket of a property
+//
+// See: HostnameBucket.GetHostnames()
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getpropertyhostnames
+// Endpoint: GET /papi/v1/properties/{propertyId}/hostnames{?contractId,groupId,offset,limit}
+func (property *Property) GetHostnameBucket() (*HostnameBucket, error) {
+	bucket := NewHostnameBucket()
+	bucket.PropertyID = property.PropertyID
+	bucket.ContractID = property.ContractID
+	bucket.GroupID = property.GroupID
+
+	if err := bucket.GetHostnames(); err != nil {
+		return nil, err
+	}
+
+	return bucket, nil
+}
+
+// GetHostnameActivations retrieves hostname bucket activations for a property,
+// optionally limited to a network
+//
+// See: HostnameActivations.GetHostnameActivations()
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#gethostnameactivations
+// Endpoint: GET /papi/v1/properties/{propertyId}/hostname-activations{?contractId,groupId,network}
+func (property *Property) GetHostnameActivations(network NetworkValue) (*HostnameActivations, error) {
+	activations := NewHostnameActivations()
+	if err := activations.GetHostnameActivations(property, network); err != nil {
+		return nil, err
+	}
+
+	return activations, nil
+}
+
 // PostUnmarshalJSON is called after JSON unmarshaling into EdgeHostnames
 //
 // See: jsonhooks-v1/jsonhooks.Unmarshal()
@@ -437,6 +470,72 @@ func (property *Property) Activate(activation *Activation, acknowledgeWarnings b
 	return activation.Save(property, acknowledgeWarnings)
 }
 
+// rollbackNoteFormat is the note of versions and activations created by
+// Property.Rollback(), used to recognise rollbacks in the activation history
+const rollbackNoteFormat = "Rollback of %s to version %d"
+
+// Rollback reverts a network to the last known good version of a property
+//
+// The most recent version successfully activated on the network, other than
+// the version currently active, is used to create a new version which is then
+// activated on the same network using fast push. The activation is returned
+// for tracking, see Activation.PollStatus().
+//
+// If acknowledgeWarnings is true, warnings returned for the activation are
+// acknowledged.
+//
+// See: Activations.GetPreviousActivation()
+// See: Property.Activate()
+func (property *Property) Rollback(network NetworkValue, notifyEmails []string, acknowledgeWarnings bool) (*Activation, error) {
+	if network == "" {
+		network = NetworkProduction
+	}
+
+	versions, err := property.GetVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var currentVersion int
+	if active := versions.GetActiveVersion(network); active != nil {
+		currentVersion = active.PropertyVersion
+	}
+
+	activations, err := property.GetActivations()
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := activations.GetPreviousActivation(network, currentVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	lastKnownGood, err := versions.FindVersion(previous.PropertyVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	version := versions.NewVersion(lastKnownGood, true)
+	version.Note = fmt.Sprintf(rollbackNoteFormat, network, lastKnownGood.PropertyVersion)
+	if err := version.Save(); err != nil {
+		return nil, err
+	}
+
+	activation := NewActivation(NewActivations())
+	activation.PropertyVersion = version.PropertyVersion
+	activation.Network = network
+	activation.FastPush = true
+	activation.Note = version.Note
+	activation.NotifyEmails = notifyEmails
+
+	if err := property.Activate(activation, acknowledgeWarnings); err != nil {
+		return nil, err
+	}
+
+	return activation, nil
+}
+
 // Delete a property
 //
 // API Docs: https://developer.akamai.com/api/luna/papi/resources.html#removeaproperty
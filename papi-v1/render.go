@@ -0,0 +1,453 @@
+package papi
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RenderRulesOutline renders a rule tree as an indented outline
+//
+// Each rule is followed by its criteria, prefixed with "if", and its
+// behaviors, with their options as key=value pairs:
+//
+//	default
+//	    origin hostname=origin.example.com httpPort=80
+//	  Static Content
+//	      if fileExtension matchOperator=IS_ONE_OF values=[css, js]
+//	      caching behavior=MAX_AGE ttl=1d
+func RenderRulesOutline(rules *Rules) string {
+	return renderOutline(renderRules(rules, rules), false)
+}
+
+// RenderRulesDiffOutline renders the changes between two rule trees as an
+// indented outline
+//
+// Lines are prefixed with "+" when added, "-" when removed and "~" for rules
+// whose criteria, behaviors or child order changed. Criteria and behaviors
+// are compared in order, so moving one is shown as a removal and an addition.
+// Rules are matched by name within their parent.
+func RenderRulesDiffOutline(from *Rules, to *Rules) string {
+	return renderOutline(renderRules(from, to), true)
+}
+
+// RenderRulesMarkdown renders a rule tree as a Markdown report
+func RenderRulesMarkdown(rules *Rules) string {
+	return renderMarkdown(rules, renderRules(rules, rules), false)
+}
+
+// RenderRulesDiffMarkdown renders the changes between two rule trees as a
+// Markdown report
+//
+// See: RenderRulesDiffOutline()
+func RenderRulesDiffMarkdown(from *Rules, to *Rules) string {
+	return renderMarkdown(to, renderRules(from, to), true)
+}
+
+// RenderRulesDOT renders the rule hierarchy as a Graphviz DOT digraph
+func RenderRulesDOT(rules *Rules) string {
+	return renderDOT(renderRules(rules, rules), false)
+}
+
+// RenderRulesDiffDOT renders the rule hierarchy of two rule trees as a
+// Graphviz DOT digraph, with added rules in green, removed rules in red and
+// changed rules in orange
+//
+// See: RenderRulesDiffOutline()
+func RenderRulesDiffDOT(from *Rules, to *Rules) string {
+	return renderDOT(renderRules(from, to), true)
+}
+
+// renderChange is the difference of a rendered rule or line between two rule trees
+type renderChange string
+
+const (
+	renderUnchanged renderChange = " "
+	renderAdded     renderChange = "+"
+	renderRemoved   renderChange = "-"
+	renderChanged   renderChange = "~"
+)
+
+type renderedLine struct {
+	change renderChange
+	text   string
+}
+
+type renderedRule struct {
+	id          int
+	parentID    int
+	depth       int
+	path        string
+	name        string
+	change      renderChange
+	mustSatisfy RuleCriteriaMustSatisfyValue
+	criteria    []*renderedLine
+	behaviors   []*renderedLine
+}
+
+// renderRules flattens two rule trees, in order, into rendered rules
+// annotated with their changes
+func renderRules(from *Rules, to *Rules) []*renderedRule {
+	var rendered []*renderedRule
+
+	var walk func(fromRule *Rule, toRule *Rule, path string, parentID int, depth int)
+	walk = func(fromRule *Rule, toRule *Rule, path string, parentID int, depth int) {
+		rule := toRule
+		if rule == nil {
+			rule = fromRule
+		}
+
+		item := &renderedRule{
+			id:          len(rendered),
+			parentID:    parentID,
+			depth:       depth,
+			path:        path,
+			name:        rule.Name,
+			mustSatisfy: rule.CriteriaMustSatisfy,
+		}
+		rendered = append(rendered, item)
+
+		var fromCriteria, toCriteria, fromBehaviors, toBehaviors []string
+		var fromChildren, toChildren []*Rule
+		if fromRule != nil {
+			fromCriteria, fromBehaviors = renderRuleLines(fromRule)
+			fromChildren = fromRule.Children
+		}
+		if toRule != nil {
+			toCriteria, toBehaviors = renderRuleLines(toRule)
+			toChildren = toRule.Children
+		}
+
+		item.criteria = diffRenderedLines(fromCriteria, toCriteria)
+		item.behaviors = diffRenderedLines(fromBehaviors, toBehaviors)
+
+		// Match children by name, noting whether matched children were reordered
+		matches := make([]*Rule, len(toChildren))
+		matched := make([]bool, len(fromChildren))
+		reordered := false
+		last := -1
+		for toKey, toChild := range toChildren {
+			for key, candidate := range fromChildren {
+				if !matched[key] && candidate.Name == toChild.Name {
+					matched[key] = true
+					matches[toKey] = candidate
+					if key < last {
+						reordered = true
+					}
+					last = key
+					break
+				}
+			}
+		}
+
+		switch {
+		case fromRule == nil:
+			item.change = renderAdded
+		case toRule == nil:
+			item.change = renderRemoved
+		case fromRule.CriteriaMustSatisfy != toRule.CriteriaMustSatisfy, reordered:
+			item.change = renderChanged
+		default:
+			item.change = renderUnchanged
+			for _, line := range append(append([]*renderedLine{}, item.criteria...), item.behaviors...) {
+				if line.change != renderUnchanged {
+					item.change = renderChanged
+				}
+			}
+		}
+
+		for toKey, toChild := range toChildren {
+			walk(matches[toKey], toChild, strings.TrimSuffix(path, "/")+"/"+toChild.Name, item.id, depth+1)
+		}
+
+		for key, fromChild := range fromChildren {
+			if !matched[key] {
+				walk(fromChild, nil, strings.TrimSuffix(path, "/")+"/"+fromChild.Name, item.id, depth+1)
+			}
+		}
+	}
+
+	var fromRule, toRule *Rule
+	if from != nil {
+		fromRule = from.Rule
+	}
+	if to != nil {
+		toRule = to.Rule
+	}
+
+	if fromRule != nil || toRule != nil {
+		walk(fromRule, toRule, "/", -1, 0)
+	}
+
+	return rendered
+}
+
+// renderRuleLines formats the criteria and behaviors of a rule
+func renderRuleLines(rule *Rule) ([]string, []string) {
+	var criteria, behaviors []string
+	for _, c := range rule.Criteria {
+		criteria = append(criteria, strings.TrimSpace(c.Name+" "+renderOptions(c.Options)))
+	}
+	for _, behavior := range rule.Behaviors {
+		behaviors = append(behaviors, strings.TrimSpace(behavior.Name+" "+renderOptions(behavior.Options)))
+	}
+
+	return criteria, behaviors
+}
+
+// diffRenderedLines compares the from and to lines in order, keeping their
+// longest common subsequence unchanged; at each difference the to lines are
+// marked as added, followed by the from lines marked as removed, so that
+// reordered lines are reported as changes
+func diffRenderedLines(from []string, to []string) []*renderedLine {
+	// common[i][j] is the length of the longest common subsequence of from[i:] and to[j:]
+	common := make([][]int, len(from)+1)
+	for i := range common {
+		common[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			switch {
+			case from[i] == to[j]:
+				common[i][j] = common[i+1][j+1] + 1
+			case common[i+1][j] >= common[i][j+1]:
+				common[i][j] = common[i+1][j]
+			default:
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	var lines, removed []*renderedLine
+	i, j := 0, 0
+	for i < len(from) || j < len(to) {
+		switch {
+		case i < len(from) && j < len(to) && from[i] == to[j]:
+			lines = append(lines, removed...)
+			removed = nil
+			lines = append(lines, &renderedLine{change: renderUnchanged, text: to[j]})
+			i++
+			j++
+		case j < len(to) && (i == len(from) || common[i][j+1] >= common[i+1][j]):
+			lines = append(lines, &renderedLine{change: renderAdded, text: to[j]})
+			j++
+		default:
+			removed = append(removed, &renderedLine{change: renderRemoved, text: from[i]})
+			i++
+		}
+	}
+
+	return append(lines, removed...)
+}
+
+// renderOptions formats options as space separated key=value pairs, sorted by
+// key, omitting empty values
+func renderOptions(options OptionValue) string {
+	keys := make([]string, 0, len(options))
+	for key, value := range options {
+		if value == nil || value == "" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+renderOptionValue(options[key]))
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+func renderOptionValue(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		if strings.ContainsAny(value, " \t\"") {
+			return strconv.Quote(value)
+		}
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			values = append(values, renderOptionValue(item))
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	case map[string]interface{}:
+		return "{" + renderOptions(OptionValue(value)) + "}"
+	case OptionValue:
+		return "{" + renderOptions(value) + "}"
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func renderOutline(rendered []*renderedRule, diff bool) string {
+	var out strings.Builder
+
+	writeLine := func(change renderChange, depth int, text string) {
+		if diff {
+			out.WriteString(string(change) + " ")
+		}
+		out.WriteString(strings.Repeat("  ", depth) + text + "\n")
+	}
+
+	for _, rule := range rendered {
+		name := rule.name
+		if rule.mustSatisfy == RuleCriteriaMustSatisfyAny && len(rule.criteria) > 1 {
+			name += " (any)"
+		}
+		writeLine(rule.change, rule.depth, name)
+
+		for _, line := range rule.criteria {
+			writeLine(inheritChange(rule.change, line.change), rule.depth+2, "if "+line.text)
+		}
+		for _, line := range rule.behaviors {
+			writeLine(inheritChange(rule.change, line.change), rule.depth+2, line.text)
+		}
+	}
+
+	return out.String()
+}
+
+func renderMarkdown(rules *Rules, rendered []*renderedRule, diff bool) string {
+	var out strings.Builder
+
+	if rules != nil && rules.PropertyID != "" {
+		fmt.Fprintf(&out, "# Rules: %s v%d", rules.PropertyID, rules.PropertyVersion)
+	} else {
+		out.WriteString("# Rules")
+	}
+	if rules != nil && rules.RuleFormat != "" {
+		fmt.Fprintf(&out, " (%s)", rules.RuleFormat)
+	}
+	out.WriteString("\n")
+
+	for _, rule := range rendered {
+		if diff && rule.change == renderUnchanged {
+			continue
+		}
+
+		fmt.Fprintf(&out, "\n## %s", rule.path)
+		if diff {
+			fmt.Fprintf(&out, " (%s)", markdownChangeNames[rule.change])
+		}
+		out.WriteString("\n")
+
+		writeLines := func(title string, lines []*renderedLine) {
+			if len(lines) == 0 {
+				return
+			}
+
+			fmt.Fprintf(&out, "\n**%s**\n\n", title)
+			for _, line := range lines {
+				change := inheritChange(rule.change, line.change)
+				if diff && change != renderUnchanged {
+					fmt.Fprintf(&out, "- (%s) %s\n", change, markdownLine(line.text))
+				} else {
+					fmt.Fprintf(&out, "- %s\n", markdownLine(line.text))
+				}
+			}
+		}
+
+		criteriaTitle := "Criteria (match all)"
+		if rule.mustSatisfy == RuleCriteriaMustSatisfyAny {
+			criteriaTitle = "Criteria (match any)"
+		}
+		writeLines(criteriaTitle, rule.criteria)
+		writeLines("Behaviors", rule.behaviors)
+	}
+
+	return out.String()
+}
+
+var markdownChangeNames = map[renderChange]string{
+	renderUnchanged: "unchanged",
+	renderAdded:     "added",
+	renderRemoved:   "removed",
+	renderChanged:   "changed",
+}
+
+// markdownEscaper escapes option values that Markdown would otherwise render
+// as emphasis or table cells
+var markdownEscaper = strings.NewReplacer("\\", "\\\\", "*", "\\*", "_", "\\_", "|", "\\|")
+
+// markdownLine formats a rendered line with the criteria or behavior name as
+// code, followed by its escaped options
+func markdownLine(text string) string {
+	parts := strings.SplitN(text, " ", 2)
+	if len(parts) == 1 {
+		return "`" + parts[0] + "`"
+	}
+
+	return "`" + parts[0] + "` " + markdownEscaper.Replace(parts[1])
+}
+
+func renderDOT(rendered []*renderedRule, diff bool) string {
+	var out strings.Builder
+
+	out.WriteString("digraph rules {\n")
+	out.WriteString("  node [shape=box, fontname=\"Helvetica\"];\n")
+
+	for _, rule := range rendered {
+		label := rule.name
+		var criteria, behaviors []string
+		for _, line := range rule.criteria {
+			if inheritChange(rule.change, line.change) != renderRemoved || rule.change == renderRemoved {
+				criteria = append(criteria, strings.SplitN(line.text, " ", 2)[0])
+			}
+		}
+		for _, line := range rule.behaviors {
+			if inheritChange(rule.change, line.change) != renderRemoved || rule.change == renderRemoved {
+				behaviors = append(behaviors, strings.SplitN(line.text, " ", 2)[0])
+			}
+		}
+		if len(criteria) != 0 {
+			label += "\nif " + strings.Join(criteria, ", ")
+		}
+		if len(behaviors) != 0 {
+			label += "\n" + strings.Join(behaviors, ", ")
+		}
+
+		attributes := "label=" + dotQuote(label)
+		if diff {
+			switch rule.change {
+			case renderAdded:
+				attributes += ", color=\"green\""
+			case renderRemoved:
+				attributes += ", color=\"red\", style=\"dashed\""
+			case renderChanged:
+				attributes += ", color=\"orange\""
+			}
+		}
+
+		fmt.Fprintf(&out, "  r%d [%s];\n", rule.id, attributes)
+		if rule.parentID >= 0 {
+			fmt.Fprintf(&out, "  r%d -> r%d;\n", rule.parentID, rule.id)
+		}
+	}
+
+	out.WriteString("}\n")
+
+	return out.String()
+}
+
+func dotQuote(value string) string {
+	value = strings.Replace(value, "\\", "\\\\", -1)
+	value = strings.Replace(value, "\"", "\\\"", -1)
+	value = strings.Replace(value, "\n", "\\n", -1)
+
+	return "\"" + value + "\""
+}
+
+// inheritChange applies the change of an added or removed rule to its lines
+func inheritChange(ruleChange renderChange, lineChange renderChange) renderChange {
+	if ruleChange == renderAdded || ruleChange == renderRemoved {
+		return ruleChange
+	}
+
+	return lineChange
+}
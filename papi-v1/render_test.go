@@ -0,0 +1,141 @@
+package papi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRenderTestRules(t *testing.T, ruleJSON string) *Rules {
+	rules := NewRules()
+	rules.PropertyID = "prp_1"
+	rules.PropertyVersion = 3
+	rules.RuleFormat = "v2018-02-27"
+	assert.NoError(t, json.Unmarshal([]byte(ruleJSON), rules.Rule))
+
+	return rules
+}
+
+const renderFromJSON = `{
+	"name": "default",
+	"behaviors": [{"name": "origin", "options": {"hostname": "origin.example.com", "httpPort": 80, "originSni": true}}],
+	"children": [
+		{
+			"name": "Static Content",
+			"criteria": [{"name": "fileExtension", "options": {"matchOperator": "IS_ONE_OF", "values": ["css", "js"]}}],
+			"behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "1d"}}]
+		},
+		{"name": "Legacy", "behaviors": [{"name": "downstreamCache", "options": {"behavior": "BUST"}}]}
+	]
+}`
+
+const renderToJSON = `{
+	"name": "default",
+	"behaviors": [{"name": "origin", "options": {"hostname": "origin.example.com", "httpPort": 80, "originSni": true}}],
+	"children": [
+		{
+			"name": "Static Content",
+			"criteria": [{"name": "fileExtension", "options": {"matchOperator": "IS_ONE_OF", "values": ["css", "js"]}}],
+			"behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "7d"}}]
+		},
+		{"name": "API", "behaviors": [{"name": "cpCode", "options": {"value": {"id": 12345}}}]}
+	]
+}`
+
+func TestRenderRulesOutline(t *testing.T) {
+	rules := newRenderTestRules(t, renderFromJSON)
+
+	assert.Equal(t, `default
+    origin hostname=origin.example.com httpPort=80 originSni=true
+  Static Content
+      if fileExtension matchOperator=IS_ONE_OF values=[css, js]
+      caching behavior=MAX_AGE ttl=1d
+  Legacy
+      downstreamCache behavior=BUST
+`, RenderRulesOutline(rules))
+}
+
+func TestRenderRulesDiffOutline(t *testing.T) {
+	from := newRenderTestRules(t, renderFromJSON)
+	to := newRenderTestRules(t, renderToJSON)
+
+	assert.Equal(t, `  default
+      origin hostname=origin.example.com httpPort=80 originSni=true
+~   Static Content
+        if fileExtension matchOperator=IS_ONE_OF values=[css, js]
++       caching behavior=MAX_AGE ttl=7d
+-       caching behavior=MAX_AGE ttl=1d
++   API
++       cpCode value={id=12345}
+-   Legacy
+-       downstreamCache behavior=BUST
+`, RenderRulesDiffOutline(from, to))
+}
+
+func TestRenderRulesMarkdown(t *testing.T) {
+	from := newRenderTestRules(t, renderFromJSON)
+	to := newRenderTestRules(t, renderToJSON)
+
+	markdown := RenderRulesDiffMarkdown(from, to)
+	assert.Contains(t, markdown, "# Rules: prp_1 v3 (v2018-02-27)\n")
+	assert.Contains(t, markdown, "## /Static Content (changed)\n")
+	assert.Contains(t, markdown, "- (+) `caching` behavior=MAX\\_AGE ttl=7d\n")
+	assert.Contains(t, markdown, "## /Legacy (removed)\n")
+	assert.NotContains(t, markdown, "## / (")
+
+	markdown = RenderRulesMarkdown(to)
+	assert.Contains(t, markdown, "**Criteria (match all)**\n\n- `fileExtension` matchOperator=IS\\_ONE\\_OF values=[css, js]\n")
+}
+
+func TestRenderRulesDOT(t *testing.T) {
+	from := newRenderTestRules(t, renderFromJSON)
+	to := newRenderTestRules(t, renderToJSON)
+
+	dot := RenderRulesDOT(from)
+	assert.Contains(t, dot, "r1 [label=\"Static Content\\nif fileExtension\\ncaching\"];\n")
+	assert.Contains(t, dot, "r0 -> r2;\n")
+
+	dot = RenderRulesDiffDOT(from, to)
+	assert.Contains(t, dot, "r2 [label=\"API\\ncpCode\", color=\"green\"];\n")
+	assert.Contains(t, dot, "r3 [label=\"Legacy\\ndownstreamCache\", color=\"red\", style=\"dashed\"];\n")
+}
+
+func TestRenderRulesDiffOutline_Reordered(t *testing.T) {
+	from := newRenderTestRules(t, `{
+		"name": "default",
+		"behaviors": [{"name": "origin"}, {"name": "caching"}, {"name": "gzipResponse"}],
+		"children": [{"name": "Images"}, {"name": "Scripts"}]
+	}`)
+	to := newRenderTestRules(t, `{
+		"name": "default",
+		"behaviors": [{"name": "caching"}, {"name": "origin"}, {"name": "gzipResponse"}],
+		"children": [{"name": "Scripts"}, {"name": "Images"}]
+	}`)
+
+	assert.Equal(t, `~ default
++     caching
+      origin
+-     caching
+      gzipResponse
+    Scripts
+    Images
+`, RenderRulesDiffOutline(from, to))
+
+	to = newRenderTestRules(t, `{
+		"name": "default",
+		"behaviors": [{"name": "origin"}, {"name": "caching"}, {"name": "gzipResponse"}],
+		"children": [{"name": "Scripts"}, {"name": "Images"}]
+	}`)
+	assert.Equal(t, "~ default\n", strings.SplitAfter(RenderRulesDiffOutline(from, to), "\n")[0])
+}
+
+func TestRenderRulesMarkdown_Escaped(t *testing.T) {
+	rules := newRenderTestRules(t, `{
+		"name": "default",
+		"behaviors": [{"name": "modifyOutgoingResponseHeader", "options": {"customHeaderName": "X_Cache|Key", "newHeaderValue": "*"}}]
+	}`)
+
+	assert.Contains(t, RenderRulesMarkdown(rules), "- `modifyOutgoingResponseHeader` customHeaderName=X\\_Cache\\|Key newHeaderValue=\\*\n")
+}
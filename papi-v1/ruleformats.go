@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"sort"
+	"strings"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
 	"github.com/xeipuuv/gojsonschema"
@@ -74,6 +75,23 @@ func (ruleFormats *RuleFormats) GetLatest() (string, error) {
 // API Docs: https://developer.akamai.com/api/luna/papi/resources.html#getaruleformatsschema
 // Endpoint: /papi/v1/schemas/products/{productId}/{ruleFormat}
 func (ruleFormats *RuleFormats) GetSchema(product string, ruleFormat string) (*gojsonschema.Schema, error) {
+	schemaBytes, err := ruleFormats.GetSchemaJSON(product, ruleFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := gojsonschema.NewBytesLoader(schemaBytes)
+	schema, err := gojsonschema.NewSchema(loader)
+
+	return schema, err
+}
+
+// GetSchemaJSON fetches the raw JSON schema for a given product and rule format
+//
+// See: RuleFormats.GetSchema()
+// API Docs: https://developer.akamai.com/api/luna/papi/resources.html#getaruleformatsschema
+// Endpoint: /papi/v1/schemas/products/{productId}/{ruleFormat}
+func (ruleFormats *RuleFormats) GetSchemaJSON(product string, ruleFormat string) ([]byte, error) {
 	req, err := client.NewRequest(
 		Config,
 		"GET",
@@ -97,10 +115,36 @@ func (ruleFormats *RuleFormats) GetSchema(product string, ruleFormat string) (*g
 		return nil, client.NewAPIError(res)
 	}
 
-	schemaBytes, _ := ioutil.ReadAll(res.Body)
-	schemaBody := string(schemaBytes)
-	loader := gojsonschema.NewStringLoader(schemaBody)
-	schema, err := gojsonschema.NewSchema(loader)
+	return ioutil.ReadAll(res.Body)
+}
 
-	return schema, err
+// maxSchemaRefDepth limits $ref resolution for self-referencing schemas
+const maxSchemaRefDepth = 8
+
+// ResolveSchemaRef follows local $ref pointers within a rule format schema
+//
+// value is a node of schema, as returned by GetSchemaJSON() and decoded with
+// encoding/json. The node is returned as is if it is not a local reference or
+// the reference cannot be followed.
+func ResolveSchemaRef(schema map[string]interface{}, value interface{}) map[string]interface{} {
+	node, _ := value.(map[string]interface{})
+	for depth := 0; depth < maxSchemaRefDepth; depth++ {
+		ref, ok := node["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return node
+		}
+
+		var current interface{} = schema
+		for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+			parent, ok := current.(map[string]interface{})
+			if !ok {
+				return node
+			}
+			current = parent[token]
+		}
+		node, _ = current.(map[string]interface{})
+	}
+
+	return node
 }
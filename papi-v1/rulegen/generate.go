@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+)
+
+const papiImportPath = "github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+
+// maxTypeDepth limits the nesting of generated types for self-referencing schemas
+const maxTypeDepth = 8
+
+// reservedNames are the generated method names, option fields using them get
+// an "Option" suffix
+var reservedNames = map[string]bool{
+	"Name":       true,
+	"ToBehavior": true,
+	"ToCriteria": true,
+}
+
+// catalogEntry is a behavior or criterion found in the schema catalog
+type catalogEntry struct {
+	Name    string
+	Options map[string]interface{}
+}
+
+// generator emits typed option structs from a rule format schema
+type generator struct {
+	schema  map[string]interface{}
+	source  string
+	pkgName string
+	buf     bytes.Buffer
+	// types maps each generated type name to what it was generated for
+	types map[string]string
+	// nested are the object options waiting for their struct to be emitted
+	nested []*nestedType
+}
+
+// nestedType is an object option generated as its own struct
+type nestedType struct {
+	Name       string
+	Doc        string
+	Properties map[string]interface{}
+	Depth      int
+}
+
+// generate reads a PAPI rule format schema and returns the formatted Go
+// source of a package with a typed struct for every behavior and criterion
+func generate(schemaJSON []byte, source string, pkgName string) ([]byte, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(schemaJSON, &schema); err != nil {
+		return nil, fmt.Errorf("unable to parse schema: %s", err)
+	}
+
+	g := &generator{schema: schema, source: source, pkgName: pkgName, types: map[string]string{}}
+
+	behaviors, err := g.catalog("behaviors")
+	if err != nil {
+		return nil, err
+	}
+
+	criteria, err := g.catalog("criteria")
+	if err != nil {
+		return nil, err
+	}
+
+	g.printf("// Code generated by rulegen; DO NOT EDIT.\n")
+	g.printf("// Source: %s\n\n", source)
+	g.printf("// Package %s provides typed options for PAPI behaviors and criteria\n", pkgName)
+	g.printf("package %s\n\n", pkgName)
+	g.printf("import (\n\t\"fmt\"\n\n\tpapi %q\n)\n\n", papiImportPath)
+
+	for _, entry := range behaviors {
+		if err := g.emit(entry, "Behavior"); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, entry := range criteria {
+		if err := g.emit(entry, "Criteria"); err != nil {
+			return nil, err
+		}
+	}
+
+	return format.Source(g.buf.Bytes())
+}
+
+func (g *generator) printf(formatter string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, formatter, args...)
+}
+
+// catalog returns the entries of definitions.catalog.{kind}, sorted by name
+func (g *generator) catalog(kind string) ([]*catalogEntry, error) {
+	definitions, _ := g.schema["definitions"].(map[string]interface{})
+	catalog, _ := definitions["catalog"].(map[string]interface{})
+	items, ok := catalog[kind].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema has no definitions.catalog.%s", kind)
+	}
+
+	entries := make([]*catalogEntry, 0, len(items))
+	for name, item := range items {
+		node := g.resolve(item)
+		properties, _ := node["properties"].(map[string]interface{})
+		options := g.resolve(properties["options"])
+		optionProperties, _ := options["properties"].(map[string]interface{})
+
+		entries = append(entries, &catalogEntry{Name: name, Options: optionProperties})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries, nil
+}
+
+// resolve follows $ref pointers within the schema document
+func (g *generator) resolve(value interface{}) map[string]interface{} {
+	return papi.ResolveSchemaRef(g.schema, value)
+}
+
+func (g *generator) emit(entry *catalogEntry, kind string) error {
+	typeName := exportName(entry.Name) + kind
+	constructor := "papi.New" + kind + "WithOptions"
+	kindName := strings.ToLower(kind)
+	description := fmt.Sprintf("the %s %s", entry.Name, kindName)
+
+	if err := g.declare(typeName, description); err != nil {
+		return err
+	}
+
+	g.printf("// %s represents the options of %s\n", typeName, description)
+	if err := g.emitStruct(typeName, description, entry.Options, 0); err != nil {
+		return err
+	}
+
+	g.printf("// Name returns the %s name\n", kindName)
+	g.printf("func (options *%s) Name() string {\n\treturn %q\n}\n\n", typeName, entry.Name)
+
+	g.printf("// To%s creates a *papi.%s from the options\n", kind, kind)
+	g.printf("func (options *%s) To%s() (*papi.%s, error) {\n", typeName, kind, kind)
+	g.printf("\treturn %s(%q, options)\n}\n\n", constructor, entry.Name)
+
+	g.printf("// %sFrom%s populates %s from a *papi.%s\n", typeName, kind, typeName, kind)
+	g.printf("func %sFrom%s(%s *papi.%s) (*%s, error) {\n", typeName, kind, kindName, kind, typeName)
+	g.printf("\tif %s.Name != %q {\n", kindName, entry.Name)
+	g.printf("\t\treturn nil, fmt.Errorf(\"expected %s %%q, got %%q\", %q, %s.Name)\n\t}\n\n", kindName, entry.Name, kindName)
+	g.printf("\toptions := &%s{}\n", typeName)
+	g.printf("\tif err := %s.GetOptions(options); err != nil {\n\t\treturn nil, err\n\t}\n\n", kindName)
+	g.printf("\treturn options, nil\n}\n\n")
+
+	for len(g.nested) > 0 {
+		nested := g.nested[0]
+		g.nested = g.nested[1:]
+
+		g.printf("// %s represents %s\n", nested.Name, nested.Doc)
+		if err := g.emitStruct(nested.Name, nested.Doc, nested.Properties, nested.Depth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// declare records a generated type name, failing if another schema name
+// already generated it
+func (g *generator) declare(typeName string, description string) error {
+	if existing, ok := g.types[typeName]; ok {
+		return fmt.Errorf("%s and %s both generate type %s", existing, description, typeName)
+	}
+	g.types[typeName] = description
+
+	return nil
+}
+
+// emitStruct emits a struct with a field for every option
+//
+// Every field is omitted from JSON when unset, scalar fields are pointers so
+// that false and 0 can be told apart from a missing option.
+func (g *generator) emitStruct(typeName string, description string, properties map[string]interface{}, depth int) error {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := map[string]string{}
+
+	g.printf("type %s struct {\n", typeName)
+	for _, name := range names {
+		fieldName := exportName(name)
+		if reservedNames[fieldName] {
+			fieldName += "Option"
+		}
+
+		if existing, ok := fields[fieldName]; ok {
+			return fmt.Errorf("options %q and %q of %s both generate field %s.%s", existing, name, description, typeName, fieldName)
+		}
+		fields[fieldName] = name
+
+		node := g.resolve(properties[name])
+		goType, err := g.goType(node, depth, typeName+fieldName, fmt.Sprintf("the %s option of %s", name, description), true)
+		if err != nil {
+			return err
+		}
+
+		if values := enumValues(node); len(values) > 0 {
+			g.printf("\t// One of: %s\n", strings.Join(values, ", "))
+		}
+
+		g.printf("\t%s %s `json:%q`\n", fieldName, goType, name+",omitempty")
+	}
+	g.printf("}\n\n")
+
+	return nil
+}
+
+// goType maps a schema node to a Go type
+//
+// Objects with properties become a struct named typeName, emitted once the
+// current type is complete. optional makes scalars and structs pointers.
+func (g *generator) goType(node map[string]interface{}, depth int, typeName string, description string, optional bool) (string, error) {
+	if depth > maxTypeDepth {
+		return "interface{}", nil
+	}
+
+	pointer := ""
+	if optional {
+		pointer = "*"
+	}
+
+	switch node["type"] {
+	case "string":
+		return "string", nil
+	case "integer":
+		return pointer + "int", nil
+	case "number":
+		return pointer + "float64", nil
+	case "boolean":
+		return pointer + "bool", nil
+	case "object":
+		properties, _ := node["properties"].(map[string]interface{})
+		if len(properties) == 0 {
+			return "map[string]interface{}", nil
+		}
+
+		if err := g.declare(typeName, description); err != nil {
+			return "", err
+		}
+		g.nested = append(g.nested, &nestedType{Name: typeName, Doc: description, Properties: properties, Depth: depth + 1})
+
+		return pointer + typeName, nil
+	case "array":
+		items := g.resolve(node["items"])
+		if items == nil {
+			return "[]interface{}", nil
+		}
+
+		itemType, err := g.goType(items, depth+1, typeName+"Item", "an item of "+description, false)
+		if err != nil {
+			return "", err
+		}
+
+		return "[]" + itemType, nil
+	}
+
+	if _, ok := node["type"]; !ok && len(enumValues(node)) > 0 {
+		return "string", nil
+	}
+
+	return "interface{}", nil
+}
+
+// enumValues returns the quoted values of a string enum
+func enumValues(node map[string]interface{}) []string {
+	enum, _ := node["enum"].([]interface{})
+	values := make([]string, 0, len(enum))
+	for _, value := range enum {
+		str, ok := value.(string)
+		if !ok {
+			return nil
+		}
+		values = append(values, fmt.Sprintf("%q", str))
+	}
+
+	return values
+}
+
+// exportName converts a camelCase schema name to an exported Go identifier
+func exportName(name string) string {
+	var result []rune
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		result = append(result, r)
+	}
+
+	if len(result) == 0 || unicode.IsDigit(result[0]) {
+		result = append([]rune("X"), result...)
+	}
+
+	return string(result)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	schemaJSON, err := ioutil.ReadFile("testdata/schema.json")
+	assert.NoError(t, err)
+
+	code, err := generate(schemaJSON, "testdata/schema.json", "ruleoptions")
+	assert.NoError(t, err)
+
+	source := string(code)
+	assert.Contains(t, source, "package ruleoptions")
+	assert.Contains(t, source, "type CachingBehavior struct")
+	assert.Contains(t, source, "type CpCodeBehavior struct")
+	assert.Contains(t, source, "type OriginBehavior struct")
+	assert.Contains(t, source, "type PathCriteria struct")
+	assert.Contains(t, source, "Hostname            string   `json:\"hostname,omitempty\"`")
+	assert.Contains(t, source, "HttpPort            *int     `json:\"httpPort,omitempty\"`")
+	assert.Contains(t, source, "CustomValidCnValues []string `json:\"customValidCnValues,omitempty\"`")
+	assert.Contains(t, source, "Value *CpCodeBehaviorValue `json:\"value,omitempty\"`")
+	assert.Contains(t, source, "type CpCodeBehaviorValue struct {\n\tId *int `json:\"id,omitempty\"`\n}")
+	assert.Contains(t, source, "// One of: \"CUSTOMER\", \"NET_STORAGE\"")
+	assert.Contains(t, source, "func (options *OriginBehavior) ToBehavior() (*papi.Behavior, error)")
+	assert.Contains(t, source, "return papi.NewBehaviorWithOptions(\"origin\", options)")
+	assert.Contains(t, source, "func PathCriteriaFromCriteria(criteria *papi.Criteria) (*PathCriteria, error)")
+}
+
+func TestGenerate_FieldCollision(t *testing.T) {
+	schemaJSON := []byte(`{"definitions": {"catalog": {
+		"behaviors": {
+			"origin": {"properties": {"options": {"properties": {
+				"foo_bar": {"type": "string"},
+				"fooBar": {"type": "string"}
+			}}}}
+		},
+		"criteria": {}
+	}}}`)
+
+	_, err := generate(schemaJSON, "schema.json", "ruleoptions")
+	assert.EqualError(t, err, `options "fooBar" and "foo_bar" of the origin behavior both generate field OriginBehavior.FooBar`)
+}
+
+func TestGenerate_TypeCollision(t *testing.T) {
+	schemaJSON := []byte(`{"definitions": {"catalog": {
+		"behaviors": {
+			"foo_bar": {"properties": {"options": {"properties": {}}}},
+			"fooBar": {"properties": {"options": {"properties": {}}}}
+		},
+		"criteria": {}
+	}}}`)
+
+	_, err := generate(schemaJSON, "schema.json", "ruleoptions")
+	assert.EqualError(t, err, "the fooBar behavior and the foo_bar behavior both generate type FooBarBehavior")
+}
+
+// roundTripTest is compiled with the generated package, it converts options
+// to the typed structs and back
+const roundTripTest = `package ruleoptions
+
+import (
+	"encoding/json"
+	"testing"
+
+	papi "github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+)
+
+func assertOptions(t *testing.T, expected papi.OptionValue, actual papi.OptionValue) {
+	expectedJSON, _ := json.Marshal(expected)
+	actualJSON, _ := json.Marshal(actual)
+	if string(expectedJSON) != string(actualJSON) {
+		t.Errorf("expected %s, got %s", expectedJSON, actualJSON)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	behaviors := []papi.OptionValue{
+		{"value": map[string]interface{}{"id": 12345}},
+		{"hostname": "origin.example.com", "httpPort": 0, "originType": "CUSTOMER", "customValidCnValues": []string{"{{Origin Hostname}}"}},
+		{"behavior": "MAX_AGE", "mustRevalidate": false, "ttl": "1d"},
+	}
+
+	behavior := papi.NewBehavior()
+	behavior.Name = "cpCode"
+	behavior.Options = behaviors[0]
+	cpCode, err := CpCodeBehaviorFromBehavior(behavior)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cpCode.Value == nil || cpCode.Value.Id == nil || *cpCode.Value.Id != 12345 {
+		t.Errorf("unexpected cpCode value %+v", cpCode.Value)
+	}
+	converted, err := cpCode.ToBehavior()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOptions(t, behaviors[0], converted.Options)
+
+	behavior.Name = "origin"
+	behavior.Options = behaviors[1]
+	origin, err := OriginBehaviorFromBehavior(behavior)
+	if err != nil {
+		t.Fatal(err)
+	}
+	converted, err = origin.ToBehavior()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOptions(t, behaviors[1], converted.Options)
+
+	behavior.Name = "caching"
+	behavior.Options = behaviors[2]
+	caching, err := CachingBehaviorFromBehavior(behavior)
+	if err != nil {
+		t.Fatal(err)
+	}
+	converted, err = caching.ToBehavior()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOptions(t, behaviors[2], converted.Options)
+
+	criteria := papi.NewCriteria()
+	criteria.Name = "path"
+	criteria.Options = papi.OptionValue{"matchOperator": "MATCHES_ONE_OF", "values": []string{"/images/*"}, "matchCaseSensitive": false}
+	path, err := PathCriteriaFromCriteria(criteria)
+	if err != nil {
+		t.Fatal(err)
+	}
+	convertedCriteria, err := path.ToCriteria()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOptions(t, criteria.Options, convertedCriteria.Options)
+
+	if _, err := PathCriteriaFromCriteria(&papi.Criteria{Name: "hostname"}); err == nil {
+		t.Error("expected an error for another criteria")
+	}
+}
+`
+
+func TestGenerate_RoundTrip(t *testing.T) {
+	goBinary, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	schemaJSON, err := ioutil.ReadFile("testdata/schema.json")
+	assert.NoError(t, err)
+
+	code, err := generate(schemaJSON, "testdata/schema.json", "ruleoptions")
+	assert.NoError(t, err)
+
+	dir, err := ioutil.TempDir("testdata", "ruleoptions")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "options.go"), code, 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "options_test.go"), []byte(roundTripTest), 0644))
+
+	output, err := exec.Command(goBinary, "test", "./"+filepath.ToSlash(dir)).CombinedOutput()
+	assert.NoError(t, err, string(output))
+}
+
+func TestExportName(t *testing.T) {
+	assert.Equal(t, "CpCode", exportName("cpCode"))
+	assert.Equal(t, "MatchCaseSensitive", exportName("matchCaseSensitive"))
+	assert.Equal(t, "X3dParty", exportName("3dParty"))
+	assert.Equal(t, "FooBar", exportName("foo-bar"))
+}
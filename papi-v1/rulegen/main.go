@@ -0,0 +1,85 @@
+// Command rulegen generates typed behavior and criteria options from a PAPI
+// rule format schema.
+//
+// The schema is read from a file:
+//
+//	rulegen -schema schema.json -package ruleoptions -out ruleoptions/options.go
+//
+// or fetched using RuleFormats.GetSchemaJSON():
+//
+//	rulegen -product prd_Site_Accel -format v2018-02-27 -section papi -out ruleoptions/options.go
+//
+// Every behavior and criterion becomes a struct (e.g. OriginBehavior,
+// PathCriteria) that converts to and from *papi.Behavior and *papi.Criteria
+// using ToBehavior()/ToCriteria() and OriginBehaviorFromBehavior() etc.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+)
+
+func main() {
+	schemaFile := flag.String("schema", "", "path to a rule format schema file")
+	product := flag.String("product", "", "product ID to fetch the schema for")
+	ruleFormat := flag.String("format", "latest", "rule format to fetch the schema for")
+	edgerc := flag.String("edgerc", "~/.edgerc", "path to the .edgerc file")
+	section := flag.String("section", "default", ".edgerc section")
+	pkgName := flag.String("package", "ruleoptions", "name of the generated package")
+	out := flag.String("out", "", "output file, defaults to stdout")
+	flag.Parse()
+
+	var schemaJSON []byte
+	var source string
+	var err error
+
+	switch {
+	case *schemaFile != "":
+		source = *schemaFile
+		schemaJSON, err = ioutil.ReadFile(*schemaFile)
+	case *product != "":
+		source = fmt.Sprintf("/papi/v1/schemas/products/%s/%s", *product, *ruleFormat)
+		schemaJSON, err = fetchSchema(*edgerc, *section, *product, *ruleFormat)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fail(err)
+	}
+
+	code, err := generate(schemaJSON, source, *pkgName)
+	if err != nil {
+		fail(err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(code)
+		return
+	}
+
+	if err := ioutil.WriteFile(*out, code, 0644); err != nil {
+		fail(err)
+	}
+}
+
+func fetchSchema(edgerc string, section string, product string, ruleFormat string) ([]byte, error) {
+	config, err := edgegrid.Init(edgerc, section)
+	if err != nil {
+		return nil, err
+	}
+
+	papi.Init(config)
+
+	return papi.NewRuleFormats().GetSchemaJSON(product, ruleFormat)
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "rulegen: %s\n", err)
+	os.Exit(1)
+}
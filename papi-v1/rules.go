@@ -1,6 +1,7 @@
 package papi
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -148,6 +149,80 @@ func (rules *Rules) Save() error {
 	return nil
 }
 
+// Patch applies RFC 6902 JSON Patch operations to a rule tree for a property
+//
+// If Rules.Etag is set it is sent as If-Match, so the patch fails if the rule
+// tree was modified since it was retrieved.
+//
+// See: DiffRules()
+// API Docs: https://developer.akamai.com/api/luna/papi/resources.html#patchpropertyversionrules
+// Endpoint: PATCH /papi/v1/properties/{propertyId}/versions/{propertyVersion}/rules{?contractId,groupId}
+func (rules *Rules) Patch(patches []*PatchOperation) error {
+	rules.Errors = []*RuleErrors{}
+
+	req, err := client.NewJSONRequest(
+		Config,
+		"PATCH",
+		fmt.Sprintf(
+			"/papi/v1/properties/%s/versions/%d/rules?contractId=%s&groupId=%s",
+			rules.PropertyID,
+			rules.PropertyVersion,
+			rules.ContractID,
+			rules.GroupID,
+		),
+		patches,
+	)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json-patch+json")
+	if rules.Etag != "" {
+		req.Header.Set("If-Match", fmt.Sprintf("\"%s\"", strings.Trim(rules.Etag, "\"")))
+	}
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	if err = client.BodyJSON(res, rules); err != nil {
+		return err
+	}
+
+	if len(rules.Errors) != 0 {
+		return ErrorMap[ErrInvalidRules]
+	}
+
+	return nil
+}
+
+// SaveAsPatch saves the changes made to a rule tree since original was retrieved
+//
+// Only the difference between original and rules is sent, using original.Etag
+// for optimistic concurrency.
+//
+// See: DiffRules()
+// See: Rules.Patch()
+func (rules *Rules) SaveAsPatch(original *Rules) error {
+	patches, err := DiffRules(original, rules)
+	if err != nil {
+		return err
+	}
+
+	if len(patches) == 0 {
+		return nil
+	}
+
+	rules.Etag = original.Etag
+
+	return rules.Patch(patches)
+}
+
 // Freeze pins a properties rule set to a specific rule set version
 func (rules *Rules) Freeze(format string) error {
 	rules.Errors = []*RuleErrors{}
@@ -455,6 +530,26 @@ func NewCriteria() *Criteria {
 	return criteria
 }
 
+// NewCriteriaWithOptions creates a new Criteria with options populated from
+// a typed options struct
+//
+// options is converted using its JSON representation.
+func NewCriteriaWithOptions(name string, options interface{}) (*Criteria, error) {
+	criteria := NewCriteria()
+	criteria.Name = name
+
+	if err := convertOptions(options, &criteria.Options); err != nil {
+		return nil, err
+	}
+
+	return criteria, nil
+}
+
+// GetOptions populates a typed options struct from the criteria options
+func (criteria *Criteria) GetOptions(options interface{}) error {
+	return convertOptions(criteria.Options, options)
+}
+
 // MergeOptions merges the given options with the existing options
 /*func (criteria *Criteria) MergeOptions(newOptions OptionValue) {
 	options := make(map[string]interface{})
@@ -487,6 +582,26 @@ func NewBehavior() *Behavior {
 	return behavior
 }
 
+// NewBehaviorWithOptions creates a new Behavior with options populated from
+// a typed options struct
+//
+// options is converted using its JSON representation.
+func NewBehaviorWithOptions(name string, options interface{}) (*Behavior, error) {
+	behavior := NewBehavior()
+	behavior.Name = name
+
+	if err := convertOptions(options, &behavior.Options); err != nil {
+		return nil, err
+	}
+
+	return behavior, nil
+}
+
+// GetOptions populates a typed options struct from the behavior options
+func (behavior *Behavior) GetOptions(options interface{}) error {
+	return convertOptions(behavior.Options, options)
+}
+
 // MergeOptions merges the given options with the existing options
 func (behavior *Behavior) MergeOptions(newOptions OptionValue) {
 	options := make(map[string]interface{})
@@ -508,6 +623,15 @@ func (behavior *Behavior) MergeOptions(newOptions OptionValue) {
 // to create more complex values.
 type OptionValue map[string]interface{}
 
+func convertOptions(from interface{}, to interface{}) error {
+	body, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, to)
+}
+
 type Variable struct {
 	client.Resource
 	Name        string `json:"name"`
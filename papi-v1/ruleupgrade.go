@@ -0,0 +1,467 @@
+package papi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RuleFormatChanges are the differences between the schemas of two rule formats
+//
+// See: CompareRuleFormats()
+type RuleFormatChanges struct {
+	From             string                    `json:"from"`
+	To               string                    `json:"to"`
+	AddedBehaviors   []string                  `json:"addedBehaviors,omitempty"`
+	RemovedBehaviors []string                  `json:"removedBehaviors,omitempty"`
+	AddedCriteria    []string                  `json:"addedCriteria,omitempty"`
+	RemovedCriteria  []string                  `json:"removedCriteria,omitempty"`
+	Options          []*RuleFormatOptionChange `json:"options,omitempty"`
+}
+
+// RuleFormatOptionChange is a change to an option of a behavior or criteria
+// present in both rule formats
+type RuleFormatOptionChange struct {
+	Kind   RuleFormatKindValue   `json:"kind"`
+	Name   string                `json:"name"`
+	Option string                `json:"option"`
+	Change RuleFormatChangeValue `json:"change"`
+	Detail string                `json:"detail,omitempty"`
+}
+
+// RuleMigration renames a behavior or criteria, and optionally its options,
+// when upgrading a rule tree to a newer rule format
+type RuleMigration struct {
+	Kind    RuleFormatKindValue `json:"kind"`
+	Name    string              `json:"name"`
+	NewName string              `json:"newName,omitempty"`
+	// Options are applied in order, so a rename may use the result of an earlier one
+	Options []*RuleOptionRename `json:"options,omitempty"`
+}
+
+// RuleOptionRename renames an option of a behavior or criteria
+type RuleOptionRename struct {
+	Option    string `json:"option"`
+	NewOption string `json:"newOption"`
+}
+
+var (
+	ruleMigrationsMutex sync.RWMutex
+	ruleMigrations      []*RuleMigration
+)
+
+// RegisterRuleMigration adds migrations applied by every Rules.Upgrade(), in
+// addition to those passed to it
+//
+// It is safe to call concurrently with Rules.Upgrade().
+func RegisterRuleMigration(migrations ...*RuleMigration) {
+	ruleMigrationsMutex.Lock()
+	defer ruleMigrationsMutex.Unlock()
+
+	ruleMigrations = append(ruleMigrations, migrations...)
+}
+
+// RuleMigrations returns the migrations added by RegisterRuleMigration()
+func RuleMigrations() []*RuleMigration {
+	ruleMigrationsMutex.RLock()
+	defer ruleMigrationsMutex.RUnlock()
+
+	return append([]*RuleMigration{}, ruleMigrations...)
+}
+
+// RuleUpgrade is a rule tree migrated to a new rule format
+//
+// See: Rules.Upgrade()
+type RuleUpgrade struct {
+	Rules   *Rules             `json:"-"`
+	Changes *RuleFormatChanges `json:"changes"`
+	// Applied describes each migration applied to the rule tree
+	Applied []string `json:"applied,omitempty"`
+	// Findings are uses of behaviors, criteria and options that were removed
+	// or changed in the new rule format and could not be migrated
+	Findings []*LintFinding `json:"findings,omitempty"`
+}
+
+// CompareRuleFormats compares the schemas of two rule formats for a product
+//
+// See: RuleFormats.GetSchemaJSON()
+func CompareRuleFormats(productID string, fromFormat string, toFormat string) (*RuleFormatChanges, error) {
+	ruleFormats := NewRuleFormats()
+
+	fromSchema, err := ruleFormats.GetSchemaJSON(productID, fromFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	toSchema, err := ruleFormats.GetSchemaJSON(productID, toFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := CompareRuleFormatSchemas(fromSchema, toSchema)
+	if err != nil {
+		return nil, err
+	}
+	changes.From = fromFormat
+	changes.To = toFormat
+
+	return changes, nil
+}
+
+// CompareRuleFormatSchemas compares two rule format JSON schemas
+func CompareRuleFormatSchemas(fromSchema []byte, toSchema []byte) (*RuleFormatChanges, error) {
+	from, err := parseRuleFormatCatalog(fromSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := parseRuleFormatCatalog(toSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &RuleFormatChanges{}
+	changes.AddedBehaviors, changes.RemovedBehaviors = diffCatalogNames(from.behaviors, to.behaviors)
+	changes.AddedCriteria, changes.RemovedCriteria = diffCatalogNames(from.criteria, to.criteria)
+	changes.Options = append(
+		diffCatalogOptions(RuleFormatKindBehavior, from.behaviors, to.behaviors),
+		diffCatalogOptions(RuleFormatKindCriteria, from.criteria, to.criteria)...,
+	)
+
+	return changes, nil
+}
+
+// Upgrade migrates a rule tree to a new rule format
+//
+// The schemas of Rules.RuleFormat and toFormat are compared, migrations and
+// RuleMigrations() are applied, and remaining uses of removed behaviors,
+// criteria and options are reported. The rule tree is modified in place but
+// not saved, see RuleUpgrade.Save().
+func (rules *Rules) Upgrade(productID string, toFormat string, migrations ...*RuleMigration) (*RuleUpgrade, error) {
+	fromFormat := rules.RuleFormat
+	if fromFormat == "" {
+		return nil, fmt.Errorf("the rule tree has no rule format")
+	}
+
+	changes, err := CompareRuleFormats(productID, fromFormat, toFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	return UpgradeRules(rules, changes, migrations...), nil
+}
+
+// UpgradeRules applies migrations to a rule tree and reports remaining uses of
+// behaviors, criteria and options removed or changed by changes
+//
+// See: Rules.Upgrade()
+func UpgradeRules(rules *Rules, changes *RuleFormatChanges, migrations ...*RuleMigration) *RuleUpgrade {
+	upgrade := &RuleUpgrade{Rules: rules, Changes: changes}
+	migrations = append(RuleMigrations(), migrations...)
+
+	var walk func(rule *Rule, path string)
+	walk = func(rule *Rule, path string) {
+		for _, behavior := range rule.Behaviors {
+			behavior.Name, behavior.Options = upgrade.migrate(RuleFormatKindBehavior, behavior.Name, behavior.Options, path, migrations)
+			upgrade.check(RuleFormatKindBehavior, behavior.Name, behavior.Options, path)
+		}
+
+		for _, criteria := range rule.Criteria {
+			criteria.Name, criteria.Options = upgrade.migrate(RuleFormatKindCriteria, criteria.Name, criteria.Options, path, migrations)
+			upgrade.check(RuleFormatKindCriteria, criteria.Name, criteria.Options, path)
+		}
+
+		for _, child := range rule.Children {
+			walk(child, strings.TrimSuffix(path, "/")+"/"+child.Name)
+		}
+	}
+
+	if rules.Rule != nil {
+		walk(rules.Rule, "/")
+	}
+	sort.Strings(upgrade.Applied)
+
+	return upgrade
+}
+
+// HasErrors reports whether the upgraded rule tree uses removed behaviors or criteria
+func (upgrade *RuleUpgrade) HasErrors() bool {
+	for _, finding := range upgrade.Findings {
+		if finding.Severity == LintSeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Save saves the upgraded rule tree, pinned to the new rule format
+//
+// See: Rules.Freeze()
+func (upgrade *RuleUpgrade) Save() error {
+	if upgrade.HasErrors() {
+		return fmt.Errorf("the rule tree uses behaviors or criteria removed in %s", upgrade.Changes.To)
+	}
+
+	upgrade.Rules.RuleFormat = upgrade.Changes.To
+
+	return upgrade.Rules.Freeze(upgrade.Changes.To)
+}
+
+func (upgrade *RuleUpgrade) migrate(kind RuleFormatKindValue, name string, options OptionValue, path string, migrations []*RuleMigration) (string, OptionValue) {
+	for _, migration := range migrations {
+		if migration.Kind != kind || migration.Name != name {
+			continue
+		}
+
+		if migration.NewName != "" && migration.NewName != name {
+			upgrade.Applied = append(upgrade.Applied, fmt.Sprintf("%s: renamed %s %s to %s", path, kind, name, migration.NewName))
+			name = migration.NewName
+		}
+
+		for _, rename := range migration.Options {
+			oldOption, newOption := rename.Option, rename.NewOption
+			value, ok := options[oldOption]
+			if !ok {
+				continue
+			}
+
+			delete(options, oldOption)
+			options[newOption] = value
+			upgrade.Applied = append(upgrade.Applied, fmt.Sprintf("%s: renamed %s %s option %s to %s", path, kind, name, oldOption, newOption))
+		}
+	}
+
+	return name, options
+}
+
+func (upgrade *RuleUpgrade) check(kind RuleFormatKindValue, name string, options OptionValue, path string) {
+	removed := upgrade.Changes.RemovedBehaviors
+	if kind == RuleFormatKindCriteria {
+		removed = upgrade.Changes.RemovedCriteria
+	}
+
+	for _, removedName := range removed {
+		if removedName == name {
+			upgrade.addFinding(LintSeverityError, path, fmt.Sprintf("%s %s was removed in %s", kind, name, upgrade.Changes.To))
+			return
+		}
+	}
+
+	for _, change := range upgrade.Changes.Options {
+		if change.Kind != kind || change.Name != name || change.Change == RuleFormatChangeAdded {
+			continue
+		}
+
+		if _, ok := options[change.Option]; !ok {
+			continue
+		}
+
+		message := fmt.Sprintf("%s %s option %s: %s", kind, name, change.Option, strings.Replace(strings.ToLower(string(change.Change)), "_", " ", -1))
+		if change.Detail != "" {
+			message += " (" + change.Detail + ")"
+		}
+		upgrade.addFinding(LintSeverityWarning, path, message)
+	}
+}
+
+func (upgrade *RuleUpgrade) addFinding(severity LintSeverityValue, path string, message string) {
+	upgrade.Findings = append(upgrade.Findings, &LintFinding{
+		Check:    "rule-format-upgrade",
+		Severity: severity,
+		Path:     path,
+		Message:  message,
+	})
+}
+
+// ruleFormatOption is the type of a behavior or criteria option in a schema
+type ruleFormatOption struct {
+	Type string
+	Enum []string
+}
+
+type ruleFormatCatalog struct {
+	behaviors map[string]map[string]*ruleFormatOption
+	criteria  map[string]map[string]*ruleFormatOption
+}
+
+// parseRuleFormatCatalog reads definitions.catalog of a rule format schema
+func parseRuleFormatCatalog(schemaJSON []byte) (*ruleFormatCatalog, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(schemaJSON, &schema); err != nil {
+		return nil, err
+	}
+
+	definitions, _ := schema["definitions"].(map[string]interface{})
+	catalog, ok := definitions["catalog"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema has no definitions.catalog")
+	}
+
+	read := func(kind string) map[string]map[string]*ruleFormatOption {
+		entries := map[string]map[string]*ruleFormatOption{}
+		items, _ := catalog[kind].(map[string]interface{})
+		for name, item := range items {
+			node := ResolveSchemaRef(schema, item)
+			properties, _ := node["properties"].(map[string]interface{})
+			optionsNode := ResolveSchemaRef(schema, properties["options"])
+			optionProperties, _ := optionsNode["properties"].(map[string]interface{})
+
+			options := map[string]*ruleFormatOption{}
+			for option, optionNode := range optionProperties {
+				resolved := ResolveSchemaRef(schema, optionNode)
+				spec := &ruleFormatOption{}
+				spec.Type, _ = resolved["type"].(string)
+				if enum, ok := resolved["enum"].([]interface{}); ok {
+					for _, value := range enum {
+						spec.Enum = append(spec.Enum, fmt.Sprint(value))
+					}
+					sort.Strings(spec.Enum)
+				}
+				options[option] = spec
+			}
+			entries[name] = options
+		}
+
+		return entries
+	}
+
+	return &ruleFormatCatalog{behaviors: read("behaviors"), criteria: read("criteria")}, nil
+}
+
+func diffCatalogNames(from map[string]map[string]*ruleFormatOption, to map[string]map[string]*ruleFormatOption) ([]string, []string) {
+	var added, removed []string
+	for name := range to {
+		if _, ok := from[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	for name := range from {
+		if _, ok := to[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	return added, removed
+}
+
+func diffCatalogOptions(kind RuleFormatKindValue, from map[string]map[string]*ruleFormatOption, to map[string]map[string]*ruleFormatOption) []*RuleFormatOptionChange {
+	var changes []*RuleFormatOptionChange
+
+	names := make([]string, 0, len(from))
+	for name := range from {
+		if _, ok := to[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fromOptions, toOptions := from[name], to[name]
+
+		options := map[string]bool{}
+		for option := range fromOptions {
+			options[option] = true
+		}
+		for option := range toOptions {
+			options[option] = true
+		}
+
+		sortedOptions := make([]string, 0, len(options))
+		for option := range options {
+			sortedOptions = append(sortedOptions, option)
+		}
+		sort.Strings(sortedOptions)
+
+		for _, option := range sortedOptions {
+			fromOption, inFrom := fromOptions[option]
+			toOption, inTo := toOptions[option]
+			change := &RuleFormatOptionChange{Kind: kind, Name: name, Option: option}
+
+			switch {
+			case !inFrom:
+				change.Change = RuleFormatChangeAdded
+			case !inTo:
+				change.Change = RuleFormatChangeRemoved
+			case fromOption.Type != toOption.Type:
+				change.Change = RuleFormatChangeTypeChanged
+				change.Detail = fmt.Sprintf("%s to %s", fromOption.Type, toOption.Type)
+			case strings.Join(fromOption.Enum, ",") != strings.Join(toOption.Enum, ","):
+				change.Change = RuleFormatChangeEnumChanged
+				change.Detail = describeEnumChange(fromOption.Enum, toOption.Enum)
+			default:
+				continue
+			}
+
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+func describeEnumChange(from []string, to []string) string {
+	added, removed := diffStrings(from, to)
+
+	var parts []string
+	if len(added) != 0 {
+		parts = append(parts, "added "+strings.Join(added, ", "))
+	}
+	if len(removed) != 0 {
+		parts = append(parts, "removed "+strings.Join(removed, ", "))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+func diffStrings(from []string, to []string) ([]string, []string) {
+	inFrom := map[string]bool{}
+	for _, value := range from {
+		inFrom[value] = true
+	}
+	inTo := map[string]bool{}
+	for _, value := range to {
+		inTo[value] = true
+	}
+
+	var added, removed []string
+	for _, value := range to {
+		if !inFrom[value] {
+			added = append(added, value)
+		}
+	}
+	for _, value := range from {
+		if !inTo[value] {
+			removed = append(removed, value)
+		}
+	}
+
+	return added, removed
+}
+
+// RuleFormatKindValue is used to create an "enum" of possible RuleMigration.Kind values
+type RuleFormatKindValue string
+
+// RuleFormatChangeValue is used to create an "enum" of possible RuleFormatOptionChange.Change values
+type RuleFormatChangeValue string
+
+const (
+	// RuleFormatKindBehavior RuleMigration.Kind value behavior
+	RuleFormatKindBehavior RuleFormatKindValue = "behavior"
+	// RuleFormatKindCriteria RuleMigration.Kind value criteria
+	RuleFormatKindCriteria RuleFormatKindValue = "criteria"
+
+	// RuleFormatChangeAdded RuleFormatOptionChange.Change value ADDED
+	RuleFormatChangeAdded RuleFormatChangeValue = "ADDED"
+	// RuleFormatChangeRemoved RuleFormatOptionChange.Change value REMOVED
+	RuleFormatChangeRemoved RuleFormatChangeValue = "REMOVED"
+	// RuleFormatChangeTypeChanged RuleFormatOptionChange.Change value TYPE_CHANGED
+	RuleFormatChangeTypeChanged RuleFormatChangeValue = "TYPE_CHANGED"
+	// RuleFormatChangeEnumChanged RuleFormatOptionChange.Change value ENUM_CHANGED
+	RuleFormatChangeEnumChanged RuleFormatChangeValue = "ENUM_CHANGED"
+)
@@ -0,0 +1,205 @@
+package papi
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+var (
+	ruleUpgradeFromSchema = []byte(`{
+		"definitions": {
+			"catalog": {
+				"behaviors": {
+					"caching": {"properties": {"options": {"properties": {
+						"behavior": {"type": "string", "enum": ["MAX_AGE", "NO_STORE"]},
+						"ttl": {"type": "string"}
+					}}}},
+					"origin": {"properties": {"options": {"$ref": "#/definitions/originOptions"}}},
+					"legacyFeature": {"properties": {"options": {"properties": {}}}}
+				},
+				"criteria": {
+					"path": {"properties": {"options": {"properties": {"values": {"type": "array"}}}}}
+				}
+			},
+			"originOptions": {"properties": {
+				"hostname": {"type": "string"},
+				"httpPort": {"type": "string"}
+			}}
+		}
+	}`)
+	ruleUpgradeToSchema = []byte(`{
+		"definitions": {
+			"catalog": {
+				"behaviors": {
+					"caching": {"properties": {"options": {"properties": {
+						"behavior": {"type": "string", "enum": ["MAX_AGE", "BYPASS_CACHE"]},
+						"ttl": {"type": "string"}
+					}}}},
+					"origin": {"properties": {"options": {"properties": {
+						"hostname": {"type": "string"},
+						"httpPort": {"type": "integer"},
+						"originHostname": {"type": "string"}
+					}}}},
+					"newFeature": {"properties": {"options": {"properties": {}}}}
+				},
+				"criteria": {
+					"path": {"properties": {"options": {"properties": {"values": {"type": "array"}}}}}
+				}
+			}
+		}
+	}`)
+)
+
+func TestCompareRuleFormatSchemas(t *testing.T) {
+	changes, err := CompareRuleFormatSchemas(ruleUpgradeFromSchema, ruleUpgradeToSchema)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"newFeature"}, changes.AddedBehaviors)
+	assert.Equal(t, []string{"legacyFeature"}, changes.RemovedBehaviors)
+	assert.Empty(t, changes.AddedCriteria)
+	assert.Empty(t, changes.RemovedCriteria)
+	assert.Equal(t, []*RuleFormatOptionChange{
+		{Kind: RuleFormatKindBehavior, Name: "caching", Option: "behavior", Change: RuleFormatChangeEnumChanged, Detail: "added BYPASS_CACHE; removed NO_STORE"},
+		{Kind: RuleFormatKindBehavior, Name: "origin", Option: "httpPort", Change: RuleFormatChangeTypeChanged, Detail: "string to integer"},
+		{Kind: RuleFormatKindBehavior, Name: "origin", Option: "originHostname", Change: RuleFormatChangeAdded},
+	}, changes.Options)
+}
+
+func TestUpgradeRules(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Put("/papi/v1/properties/prp_1/versions/2/rules").
+		MatchHeader("Content-Type", "application/vnd.akamai.papirules.v2018-02-27\\+json").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/vnd.akamai.papirules.v2018-02-27+json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": 2, "ruleFormat": "v2018-02-27", "rules": {"name": "default"}}`)
+
+	Init(config)
+
+	changes, err := CompareRuleFormatSchemas(ruleUpgradeFromSchema, ruleUpgradeToSchema)
+	assert.NoError(t, err)
+	changes.From = "v2017-06-19"
+	changes.To = "v2018-02-27"
+
+	rules := NewRules()
+	rules.PropertyID = "prp_1"
+	rules.PropertyVersion = 2
+	rules.RuleFormat = "v2017-06-19"
+	err = json.Unmarshal([]byte(`{
+		"name": "default",
+		"behaviors": [
+			{"name": "origin", "options": {"hostname": "origin.example.com", "httpPort": "80"}},
+			{"name": "legacyFeature", "options": {}}
+		],
+		"children": [
+			{
+				"name": "Static",
+				"behaviors": [{"name": "caching", "options": {"behavior": "NO_STORE"}}]
+			}
+		]
+	}`), rules.Rule)
+	assert.NoError(t, err)
+
+	upgrade := UpgradeRules(rules, changes,
+		&RuleMigration{Kind: RuleFormatKindBehavior, Name: "legacyFeature", NewName: "newFeature"},
+		&RuleMigration{Kind: RuleFormatKindBehavior, Name: "origin", Options: []*RuleOptionRename{{Option: "hostname", NewOption: "originHostname"}}},
+	)
+
+	assert.Equal(t, []string{
+		"/: renamed behavior legacyFeature to newFeature",
+		"/: renamed behavior origin option hostname to originHostname",
+	}, upgrade.Applied)
+	assert.Equal(t, "newFeature", rules.Rule.Behaviors[1].Name)
+	assert.Equal(t, "origin.example.com", rules.Rule.Behaviors[0].Options["originHostname"])
+
+	assert.False(t, upgrade.HasErrors())
+	if assert.Len(t, upgrade.Findings, 2) {
+		assert.Equal(t, "/", upgrade.Findings[0].Path)
+		assert.Equal(t, "behavior origin option httpPort: type changed (string to integer)", upgrade.Findings[0].Message)
+		assert.Equal(t, "/Static", upgrade.Findings[1].Path)
+		assert.Equal(t, LintSeverityWarning, upgrade.Findings[1].Severity)
+	}
+
+	err = upgrade.Save()
+	assert.NoError(t, err)
+	assert.Equal(t, "v2018-02-27", rules.RuleFormat)
+}
+
+func TestRules_Upgrade_RegisteredMigration(t *testing.T) {
+	defer gock.Off()
+	defer func() { ruleMigrations = nil }()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/schemas/products/prd_Fresca/v2017-06-19").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/schema+json").
+		BodyString(string(ruleUpgradeFromSchema))
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/schemas/products/prd_Fresca/v2018-02-27").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/schema+json").
+		BodyString(string(ruleUpgradeToSchema))
+
+	Init(config)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			RegisterRuleMigration(&RuleMigration{Kind: RuleFormatKindCriteria, Name: "path"})
+		}()
+	}
+	RegisterRuleMigration(&RuleMigration{Kind: RuleFormatKindBehavior, Name: "legacyFeature", NewName: "newFeature"})
+	wg.Wait()
+	assert.Len(t, RuleMigrations(), 11)
+
+	rules := NewRules()
+	rules.RuleFormat = "v2017-06-19"
+	err := json.Unmarshal([]byte(`{
+		"name": "default",
+		"behaviors": [{"name": "legacyFeature", "options": {}}]
+	}`), rules.Rule)
+	assert.NoError(t, err)
+
+	upgrade, err := rules.Upgrade("prd_Fresca", "v2018-02-27")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/: renamed behavior legacyFeature to newFeature"}, upgrade.Applied)
+	assert.Equal(t, "newFeature", rules.Rule.Behaviors[0].Name)
+	assert.False(t, upgrade.HasErrors())
+	assert.True(t, gock.IsDone())
+}
+
+func TestUpgradeRules_ChainedOptionRenames(t *testing.T) {
+	rules := NewRules()
+	rules.RuleFormat = "v2017-06-19"
+	err := json.Unmarshal([]byte(`{
+		"name": "default",
+		"behaviors": [{"name": "origin", "options": {"hostname": "origin.example.com", "originHostname": "legacy.example.com"}}]
+	}`), rules.Rule)
+	assert.NoError(t, err)
+
+	UpgradeRules(rules, &RuleFormatChanges{}, &RuleMigration{
+		Kind: RuleFormatKindBehavior,
+		Name: "origin",
+		Options: []*RuleOptionRename{
+			{Option: "originHostname", NewOption: "legacyHostname"},
+			{Option: "hostname", NewOption: "originHostname"},
+		},
+	})
+
+	assert.Equal(t, OptionValue{"originHostname": "origin.example.com", "legacyHostname": "legacy.example.com"}, rules.Rule.Behaviors[0].Options)
+}
@@ -0,0 +1,583 @@
+package papi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ScheduledActivation is a property activation to be run within a change window
+//
+// The activation is submitted no earlier than NotBefore and no later than
+// NotAfter, outside of any blackout. A zero NotBefore or NotAfter leaves that
+// side of the window open.
+type ScheduledActivation struct {
+	JobID               string                         `json:"jobId"`
+	PropertyID          string                         `json:"propertyId"`
+	ContractID          string                         `json:"contractId"`
+	GroupID             string                         `json:"groupId"`
+	PropertyVersion     int                            `json:"propertyVersion"`
+	Network             NetworkValue                   `json:"network"`
+	Note                string                         `json:"note,omitempty"`
+	NotifyEmails        []string                       `json:"notifyEmails"`
+	AcknowledgeWarnings bool                           `json:"acknowledgeWarnings,omitempty"`
+	ComplianceRecord    *ActivationComplianceRecord    `json:"complianceRecord,omitempty"`
+	NotBefore           time.Time                      `json:"notBefore,omitempty"`
+	NotAfter            time.Time                      `json:"notAfter,omitempty"`
+	Status              ScheduledActivationStatusValue `json:"status"`
+	CreatedAt           time.Time                      `json:"createdAt"`
+	StartedAt           time.Time                      `json:"startedAt,omitempty"`
+	FinishedAt          time.Time                      `json:"finishedAt,omitempty"`
+	Outcome             *ScheduledActivationOutcome    `json:"outcome,omitempty"`
+}
+
+// ScheduledActivationOutcome is the result of running a ScheduledActivation
+type ScheduledActivationOutcome struct {
+	ActivationID     string                      `json:"activationId,omitempty"`
+	ActivationStatus StatusValue                 `json:"activationStatus,omitempty"`
+	ComplianceRecord *ActivationComplianceRecord `json:"complianceRecord,omitempty"`
+	SubmitDate       string                      `json:"submitDate,omitempty"`
+	UpdateDate       string                      `json:"updateDate,omitempty"`
+	Error            string                      `json:"error,omitempty"`
+}
+
+// Blackout is a period during which activations are not permitted
+type Blackout struct {
+	Name  string    `json:"name,omitempty"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// BlackoutCalendar is a named set of blackouts
+//
+// An empty Network applies the calendar to both networks.
+type BlackoutCalendar struct {
+	Name      string       `json:"name"`
+	Network   NetworkValue `json:"network,omitempty"`
+	Blackouts []*Blackout  `json:"blackouts"`
+}
+
+// Find returns the blackout of the calendar in effect at a time, if any
+func (calendar *BlackoutCalendar) Find(network NetworkValue, at time.Time) *Blackout {
+	if calendar.Network != "" && calendar.Network != network {
+		return nil
+	}
+
+	for _, blackout := range calendar.Blackouts {
+		if !at.Before(blackout.Start) && at.Before(blackout.End) {
+			return blackout
+		}
+	}
+
+	return nil
+}
+
+// ActivationJobStore persists scheduled activations
+type ActivationJobStore interface {
+	Load() ([]*ScheduledActivation, error)
+	Save(jobs []*ScheduledActivation) error
+}
+
+// FileActivationJobStore persists scheduled activations to a local JSON file
+type FileActivationJobStore struct {
+	Path string
+}
+
+// NewFileActivationJobStore creates a new FileActivationJobStore
+func NewFileActivationJobStore(path string) *FileActivationJobStore {
+	return &FileActivationJobStore{Path: path}
+}
+
+// Load reads the scheduled activations, a missing file has none
+func (store *FileActivationJobStore) Load() ([]*ScheduledActivation, error) {
+	data, err := ioutil.ReadFile(store.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []*ScheduledActivation
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// Save writes the scheduled activations
+//
+// The file is replaced atomically so that an interrupted write does not lose jobs.
+func (store *FileActivationJobStore) Save(jobs []*ScheduledActivation) error {
+	data, err := json.MarshalIndent(jobs, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	tmp := store.Path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, store.Path)
+}
+
+// ActivationScheduler runs property activations within change windows
+//
+// Jobs are persisted to the Store on every change, so a scheduler created
+// from the same store after a restart resumes pending jobs and continues
+// polling activations that were already submitted. Activations are polled
+// once per RunDue call, so Run's interval is also the polling interval.
+//
+//	store := papi.NewFileActivationJobStore("activations.json")
+//	scheduler, err := papi.NewActivationScheduler(store)
+//	scheduler.Blackouts = append(scheduler.Blackouts, freeze)
+//	job, err := scheduler.Schedule(&papi.ScheduledActivation{...})
+//	go scheduler.Run(time.Minute, stop, nil)
+type ActivationScheduler struct {
+	Store     ActivationJobStore
+	Blackouts []*BlackoutCalendar
+	// PollTimeout limits how long after it started a submitted activation is
+	// polled, defaults to 2 hours
+	PollTimeout time.Duration
+
+	mutex sync.Mutex
+	jobs  []*ScheduledActivation
+	// claimed are the jobs being submitted or checked by a RunDue call
+	claimed map[string]bool
+	now     func() time.Time
+	nextID  int64
+}
+
+// NewActivationScheduler creates a new ActivationScheduler, loading its jobs from store
+func NewActivationScheduler(store ActivationJobStore) (*ActivationScheduler, error) {
+	jobs, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ActivationScheduler{
+		Store:       store,
+		PollTimeout: 2 * time.Hour,
+		jobs:        jobs,
+		now:         time.Now,
+	}, nil
+}
+
+// Schedule adds an activation job
+//
+// The JobID, Status and CreatedAt of the job are set by the scheduler.
+func (scheduler *ActivationScheduler) Schedule(job *ScheduledActivation) (*ScheduledActivation, error) {
+	if job.PropertyID == "" || job.PropertyVersion == 0 || job.Network == "" {
+		return nil, fmt.Errorf("property, version and network are required")
+	}
+
+	if !job.NotBefore.IsZero() && !job.NotAfter.IsZero() && !job.NotBefore.Before(job.NotAfter) {
+		return nil, fmt.Errorf("the window closes before it opens")
+	}
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	now := scheduler.now()
+	if !job.NotAfter.IsZero() && job.NotAfter.Before(now) {
+		return nil, fmt.Errorf("the window closed at %s", job.NotAfter.Format(time.RFC3339))
+	}
+
+	id := now.UnixNano()
+	if id <= scheduler.nextID {
+		id = scheduler.nextID + 1
+	}
+	scheduler.nextID = id
+
+	job.JobID = fmt.Sprintf("job_%d", id)
+	job.Status = ScheduledActivationStatusPending
+	job.CreatedAt = now
+	scheduler.jobs = append(scheduler.jobs, job)
+
+	return job, scheduler.save()
+}
+
+// Cancel cancels a pending job
+//
+// Jobs that have been submitted cannot be cancelled, see Activation.Cancel().
+func (scheduler *ActivationScheduler) Cancel(jobID string) error {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	job := scheduler.find(jobID)
+	if job == nil {
+		return fmt.Errorf("job \"%s\" not found", jobID)
+	}
+
+	if job.Status != ScheduledActivationStatusPending {
+		return fmt.Errorf("job \"%s\" is %s", jobID, job.Status)
+	}
+
+	job.Status = ScheduledActivationStatusCancelled
+	job.FinishedAt = scheduler.now()
+
+	return scheduler.save()
+}
+
+// Jobs returns every job, ordered by creation
+func (scheduler *ActivationScheduler) Jobs() []*ScheduledActivation {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	jobs := append([]*ScheduledActivation{}, scheduler.jobs...)
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+
+	return jobs
+}
+
+// FindJob returns a job by ID
+func (scheduler *ActivationScheduler) FindJob(jobID string) (*ScheduledActivation, error) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	job := scheduler.find(jobID)
+	if job == nil {
+		return nil, fmt.Errorf("job \"%s\" not found", jobID)
+	}
+
+	return job, nil
+}
+
+// Permitted reports whether a job may be submitted at a time, and why not
+func (scheduler *ActivationScheduler) Permitted(job *ScheduledActivation, at time.Time) (bool, string) {
+	if !job.NotBefore.IsZero() && at.Before(job.NotBefore) {
+		return false, fmt.Sprintf("window opens at %s", job.NotBefore.Format(time.RFC3339))
+	}
+
+	if !job.NotAfter.IsZero() && at.After(job.NotAfter) {
+		return false, fmt.Sprintf("window closed at %s", job.NotAfter.Format(time.RFC3339))
+	}
+
+	for _, calendar := range scheduler.Blackouts {
+		if blackout := calendar.Find(job.Network, at); blackout != nil {
+			return false, fmt.Sprintf("%s blackout %s until %s", calendar.Name, blackout.Name, blackout.End.Format(time.RFC3339))
+		}
+	}
+
+	return true, ""
+}
+
+// RunDue runs every pending job that is permitted now
+//
+// Pending jobs whose window has closed are expired. Jobs that are permitted
+// are moved to RUNNING and persisted before they are submitted, so that
+// concurrent calls never submit a job twice, and are checked against their
+// window and blackouts again just before submission. Submitted activations,
+// including those submitted before a restart, are checked once per call
+// until they complete or PollTimeout has elapsed since they started. The jobs
+// that were submitted, checked or expired are returned.
+func (scheduler *ActivationScheduler) RunDue() ([]*ScheduledActivation, error) {
+	scheduler.mutex.Lock()
+	var due, submit, check []*ScheduledActivation
+	now := scheduler.now()
+	for _, job := range scheduler.jobs {
+		if scheduler.claimed[job.JobID] {
+			continue
+		}
+
+		switch job.Status {
+		case ScheduledActivationStatusPending:
+			if !job.NotAfter.IsZero() && now.After(job.NotAfter) {
+				job.Status = ScheduledActivationStatusExpired
+				job.FinishedAt = now
+				due = append(due, job)
+				continue
+			}
+
+			if permitted, _ := scheduler.Permitted(job, now); permitted {
+				job.Status = ScheduledActivationStatusRunning
+				job.StartedAt = now
+				job.Outcome = &ScheduledActivationOutcome{}
+				submit = append(submit, job)
+			}
+		case ScheduledActivationStatusRunning:
+			check = append(check, job)
+		}
+	}
+
+	claimed := append(append([]*ScheduledActivation{}, submit...), check...)
+	if scheduler.claimed == nil {
+		scheduler.claimed = map[string]bool{}
+	}
+	for _, job := range claimed {
+		scheduler.claimed[job.JobID] = true
+	}
+
+	err := scheduler.save()
+	if err != nil {
+		for _, job := range submit {
+			scheduler.release(job)
+		}
+	}
+	scheduler.mutex.Unlock()
+
+	defer func() {
+		scheduler.mutex.Lock()
+		for _, job := range claimed {
+			delete(scheduler.claimed, job.JobID)
+		}
+		scheduler.mutex.Unlock()
+	}()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range submit {
+		submitted, err := scheduler.submit(job)
+		if submitted {
+			due = append(due, job)
+		}
+		if err != nil {
+			return due, err
+		}
+	}
+
+	for _, job := range check {
+		due = append(due, job)
+		if err := scheduler.check(job); err != nil {
+			return due, err
+		}
+	}
+
+	return due, nil
+}
+
+// Run calls RunDue every interval until stop is closed
+//
+// Errors persisting jobs are sent to errors if it is not nil.
+func (scheduler *ActivationScheduler) Run(interval time.Duration, stop <-chan struct{}, errors chan<- error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := scheduler.RunDue(); err != nil && errors != nil {
+			errors <- err
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// submit submits a job claimed by RunDue, unless it is no longer permitted
+//
+// A job that is no longer permitted, because a blackout started or its
+// window closed while other jobs were submitted, is returned to PENDING.
+func (scheduler *ActivationScheduler) submit(job *ScheduledActivation) (bool, error) {
+	if permitted, _ := scheduler.Permitted(job, scheduler.now()); !permitted {
+		scheduler.mutex.Lock()
+		defer scheduler.mutex.Unlock()
+
+		scheduler.release(job)
+		return false, scheduler.save()
+	}
+
+	property := scheduler.property(job)
+
+	activation := NewActivation(NewActivations())
+	activation.PropertyVersion = job.PropertyVersion
+	activation.Network = job.Network
+	activation.Note = job.Note
+	activation.NotifyEmails = job.NotifyEmails
+	activation.ComplianceRecord = job.ComplianceRecord
+
+	if err := property.Activate(activation, job.AcknowledgeWarnings); err != nil {
+		return true, scheduler.finish(job, activation, ScheduledActivationStatusFailed, err)
+	}
+
+	return true, scheduler.update(job, activation)
+}
+
+// check retrieves the status of a submitted job's activation
+//
+// A job claimed without an activation ID may have been interrupted after the
+// activation was submitted but before it was saved, so the property's
+// activations are searched for it before the job is failed.
+func (scheduler *ActivationScheduler) check(job *ScheduledActivation) error {
+	activation := NewActivation(NewActivations())
+
+	if job.Outcome == nil || job.Outcome.ActivationID == "" {
+		submitted, err := scheduler.reconcile(job)
+		if err != nil {
+			return err
+		}
+
+		if submitted == nil {
+			return scheduler.finish(job, activation, ScheduledActivationStatusFailed, fmt.Errorf("the activation was interrupted before it was submitted"))
+		}
+
+		return scheduler.update(job, submitted)
+	}
+
+	activation.ActivationID = job.Outcome.ActivationID
+	activation.Network = job.Network
+
+	if _, err := activation.GetActivation(scheduler.property(job)); err != nil {
+		return err
+	}
+
+	return scheduler.update(job, activation)
+}
+
+// reconcile finds the activation submitted for a job since it was claimed
+//
+// nil is returned if no pending or active activation of the job's version
+// was submitted on its network after StartedAt.
+func (scheduler *ActivationScheduler) reconcile(job *ScheduledActivation) (*Activation, error) {
+	activations, err := scheduler.property(job).GetActivations()
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := job.StartedAt.Truncate(time.Second)
+
+	var submitted *Activation
+	var submittedAt time.Time
+	for _, activation := range activations.Activations.Items {
+		if activation.ActivationType != ActivationTypeActivate || activation.Network != job.Network || activation.PropertyVersion != job.PropertyVersion {
+			continue
+		}
+
+		switch activation.Status {
+		case StatusActive, StatusPending, StatusNew, StatusZone1, StatusZone2, StatusZone3:
+		default:
+			continue
+		}
+
+		submitDate, err := time.Parse(time.RFC3339, activation.SubmitDate)
+		if err != nil || submitDate.Before(claimed) {
+			continue
+		}
+
+		if submitted == nil || submitDate.After(submittedAt) {
+			submitted = activation
+			submittedAt = submitDate
+		}
+	}
+
+	return submitted, nil
+}
+
+// update records the current state of a submitted activation, finishing the
+// job once the activation completes or PollTimeout has elapsed
+func (scheduler *ActivationScheduler) update(job *ScheduledActivation, activation *Activation) error {
+	switch activation.Status {
+	case StatusActive:
+		return scheduler.finish(job, activation, ScheduledActivationStatusSucceeded, nil)
+	case StatusFailed, StatusAborted, StatusDeactivated:
+		return scheduler.finish(job, activation, ScheduledActivationStatusFailed, fmt.Errorf("activation %s is %s", activation.ActivationID, activation.Status))
+	}
+
+	if scheduler.now().After(job.StartedAt.Add(scheduler.PollTimeout)) {
+		return scheduler.finish(job, activation, ScheduledActivationStatusTimedOut, fmt.Errorf("activation %s was still %s after %s", activation.ActivationID, activation.Status, scheduler.PollTimeout))
+	}
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	scheduler.record(job, activation)
+
+	return scheduler.save()
+}
+
+// finish records the outcome of a job
+func (scheduler *ActivationScheduler) finish(job *ScheduledActivation, activation *Activation, status ScheduledActivationStatusValue, err error) error {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	scheduler.record(job, activation)
+	if err != nil {
+		job.Outcome.Error = err.Error()
+	}
+
+	job.Status = status
+	job.FinishedAt = scheduler.now()
+
+	return scheduler.save()
+}
+
+// record copies the state of a job's activation to its outcome
+func (scheduler *ActivationScheduler) record(job *ScheduledActivation, activation *Activation) {
+	if job.Outcome == nil {
+		job.Outcome = &ScheduledActivationOutcome{}
+	}
+
+	if activation.ActivationID != "" {
+		job.Outcome.ActivationID = activation.ActivationID
+	}
+	job.Outcome.ActivationStatus = activation.Status
+	job.Outcome.ComplianceRecord = activation.ComplianceRecord
+	job.Outcome.SubmitDate = activation.SubmitDate
+	job.Outcome.UpdateDate = activation.UpdateDate
+}
+
+// release returns a claimed job to PENDING
+func (scheduler *ActivationScheduler) release(job *ScheduledActivation) {
+	job.Status = ScheduledActivationStatusPending
+	job.StartedAt = time.Time{}
+	job.Outcome = nil
+}
+
+func (scheduler *ActivationScheduler) property(job *ScheduledActivation) *Property {
+	property := NewProperty(NewProperties())
+	property.PropertyID = job.PropertyID
+	property.ContractID = job.ContractID
+	property.GroupID = job.GroupID
+	property.Contract = NewContract(NewContracts())
+	property.Contract.ContractID = job.ContractID
+	property.Group = NewGroup(NewGroups())
+	property.Group.GroupID = job.GroupID
+
+	return property
+}
+
+func (scheduler *ActivationScheduler) find(jobID string) *ScheduledActivation {
+	for _, job := range scheduler.jobs {
+		if job.JobID == jobID {
+			return job
+		}
+	}
+
+	return nil
+}
+
+func (scheduler *ActivationScheduler) save() error {
+	return scheduler.Store.Save(scheduler.jobs)
+}
+
+// ScheduledActivationStatusValue is used to create an "enum" of possible ScheduledActivation.Status values
+type ScheduledActivationStatusValue string
+
+const (
+	// ScheduledActivationStatusPending ScheduledActivation.Status value PENDING
+	ScheduledActivationStatusPending ScheduledActivationStatusValue = "PENDING"
+	// ScheduledActivationStatusRunning ScheduledActivation.Status value RUNNING
+	ScheduledActivationStatusRunning ScheduledActivationStatusValue = "RUNNING"
+	// ScheduledActivationStatusSucceeded ScheduledActivation.Status value SUCCEEDED
+	ScheduledActivationStatusSucceeded ScheduledActivationStatusValue = "SUCCEEDED"
+	// ScheduledActivationStatusFailed ScheduledActivation.Status value FAILED
+	ScheduledActivationStatusFailed ScheduledActivationStatusValue = "FAILED"
+	// ScheduledActivationStatusTimedOut ScheduledActivation.Status value TIMED_OUT
+	ScheduledActivationStatusTimedOut ScheduledActivationStatusValue = "TIMED_OUT"
+	// ScheduledActivationStatusExpired ScheduledActivation.Status value EXPIRED
+	ScheduledActivationStatusExpired ScheduledActivationStatusValue = "EXPIRED"
+	// ScheduledActivationStatusCancelled ScheduledActivation.Status value CANCELLED
+	ScheduledActivationStatusCancelled ScheduledActivationStatusValue = "CANCELLED"
+)
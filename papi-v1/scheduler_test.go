@@ -0,0 +1,304 @@
+package papi
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestActivationScheduler_RunDue(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/papi/v1/properties/prp_1/activations").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		JSON(`{"propertyVersion": 3, "network": "PRODUCTION", "notifyEmails": ["ops@example.com"], "complianceRecord": {"noncomplianceReason": "NONE", "ticketId": "CHG-1", "peerReviewedBy": "reviewer@example.com", "unitTested": true}}`).
+		HeaderPresent("Authorization").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activationLink": "/papi/v1/properties/prp_1/activations/atv_1?contractId=ctr_1&groupId=grp_1"}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/activations/atv_1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activations": {"items": [{
+			"activationId": "atv_1",
+			"propertyId": "prp_1",
+			"propertyVersion": 3,
+			"network": "PRODUCTION",
+			"status": "ACTIVE",
+			"submitDate": "2019-06-03T10:00:00Z",
+			"updateDate": "2019-06-03T10:10:00Z",
+			"complianceRecord": {"noncomplianceReason": "NONE", "ticketId": "CHG-1", "peerReviewedBy": "reviewer@example.com", "unitTested": true}
+		}]}}`)
+
+	Init(config)
+
+	dir, err := ioutil.TempDir("", "scheduler")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2019, 6, 3, 10, 0, 0, 0, time.UTC)
+	scheduler, err := NewActivationScheduler(NewFileActivationJobStore(filepath.Join(dir, "jobs.json")))
+	assert.NoError(t, err)
+	scheduler.now = func() time.Time { return now }
+	scheduler.Blackouts = []*BlackoutCalendar{{
+		Name:    "freeze",
+		Network: NetworkProduction,
+		Blackouts: []*Blackout{
+			{Name: "release", Start: now.Add(time.Hour), End: now.Add(3 * time.Hour)},
+		},
+	}}
+
+	compliance := &ActivationComplianceRecord{NoncomplianceReason: "NONE", TicketID: "CHG-1", PeerReviewedBy: "reviewer@example.com", UnitTested: true}
+	permitted, err := scheduler.Schedule(&ScheduledActivation{
+		PropertyID: "prp_1", ContractID: "ctr_1", GroupID: "grp_1", PropertyVersion: 3,
+		Network: NetworkProduction, NotifyEmails: []string{"ops@example.com"}, ComplianceRecord: compliance,
+		NotBefore: now.Add(-time.Minute), NotAfter: now.Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	blackedOut, err := scheduler.Schedule(&ScheduledActivation{
+		PropertyID: "prp_2", PropertyVersion: 1, Network: NetworkProduction,
+		NotBefore: now.Add(90 * time.Minute),
+	})
+	assert.NoError(t, err)
+
+	allowed, reason := scheduler.Permitted(blackedOut, now.Add(2*time.Hour))
+	assert.False(t, allowed)
+	assert.Contains(t, reason, "freeze blackout release")
+
+	ran, err := scheduler.RunDue()
+	assert.NoError(t, err)
+	if assert.Len(t, ran, 1) {
+		assert.Equal(t, permitted.JobID, ran[0].JobID)
+	}
+
+	assert.Equal(t, ScheduledActivationStatusSucceeded, permitted.Status)
+	assert.Equal(t, "atv_1", permitted.Outcome.ActivationID)
+	assert.Equal(t, StatusActive, permitted.Outcome.ActivationStatus)
+	assert.Equal(t, "CHG-1", permitted.Outcome.ComplianceRecord.TicketID)
+	assert.Equal(t, ScheduledActivationStatusPending, blackedOut.Status)
+
+	reloaded, err := NewActivationScheduler(scheduler.Store)
+	assert.NoError(t, err)
+	jobs := reloaded.Jobs()
+	if assert.Len(t, jobs, 2) {
+		assert.Equal(t, ScheduledActivationStatusSucceeded, jobs[0].Status)
+		assert.Equal(t, "reviewer@example.com", jobs[0].Outcome.ComplianceRecord.PeerReviewedBy)
+		assert.Equal(t, ScheduledActivationStatusPending, jobs[1].Status)
+	}
+
+	assert.NoError(t, reloaded.Cancel(blackedOut.JobID))
+	assert.Error(t, reloaded.Cancel(permitted.JobID))
+}
+
+func TestActivationScheduler_RunDue_Concurrent(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/papi/v1/properties/prp_1/activations").
+		HeaderPresent("Authorization").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activationLink": "/papi/v1/properties/prp_1/activations/atv_1?contractId=ctr_1&groupId=grp_1"}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/activations/atv_1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activations": {"items": [{"activationId": "atv_1", "propertyId": "prp_1", "propertyVersion": 3, "network": "STAGING", "status": "ACTIVE"}]}}`)
+
+	Init(config)
+
+	dir, err := ioutil.TempDir("", "scheduler")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2019, 6, 3, 10, 0, 0, 0, time.UTC)
+	scheduler, err := NewActivationScheduler(NewFileActivationJobStore(filepath.Join(dir, "jobs.json")))
+	assert.NoError(t, err)
+	scheduler.now = func() time.Time { return now }
+
+	job, err := scheduler.Schedule(&ScheduledActivation{
+		PropertyID: "prp_1", ContractID: "ctr_1", GroupID: "grp_1", PropertyVersion: 3, Network: NetworkStaging,
+	})
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	ran := make([][]*ScheduledActivation, 2)
+	for i := range ran {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ran[i], _ = scheduler.RunDue()
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, append(ran[0], ran[1]...), 1)
+	assert.Equal(t, ScheduledActivationStatusSucceeded, job.Status)
+	assert.Empty(t, job.Outcome.Error)
+	assert.True(t, gock.IsDone())
+}
+
+func TestActivationScheduler_RunDue_CrossesBlackout(t *testing.T) {
+	defer gock.Off()
+
+	now := time.Date(2019, 6, 3, 10, 0, 0, 0, time.UTC)
+	clock := now
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Post("/papi/v1/properties/prp_1/activations").
+		AddMatcher(func(req *http.Request, ereq *gock.Request) (bool, error) {
+			// The submission is slow, the blackout starts before it completes
+			clock = now.Add(time.Hour)
+			return true, nil
+		}).
+		HeaderPresent("Authorization").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activationLink": "/papi/v1/properties/prp_1/activations/atv_1?contractId=ctr_1&groupId=grp_1"}`)
+
+	for _, status := range []string{"PENDING", "ACTIVE"} {
+		mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+		mock.
+			Get("/papi/v1/properties/prp_1/activations/atv_1").
+			HeaderPresent("Authorization").
+			Reply(200).
+			SetHeader("Content-Type", "application/json").
+			BodyString(`{"activations": {"items": [{"activationId": "atv_1", "propertyId": "prp_1", "propertyVersion": 3, "network": "PRODUCTION", "status": "` + status + `"}]}}`)
+	}
+
+	Init(config)
+
+	dir, err := ioutil.TempDir("", "scheduler")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	scheduler, err := NewActivationScheduler(NewFileActivationJobStore(filepath.Join(dir, "jobs.json")))
+	assert.NoError(t, err)
+	scheduler.now = func() time.Time { return clock }
+	scheduler.Blackouts = []*BlackoutCalendar{{
+		Name: "freeze",
+		Blackouts: []*Blackout{
+			{Name: "release", Start: now.Add(30 * time.Minute), End: now.Add(3 * time.Hour)},
+		},
+	}}
+
+	first, err := scheduler.Schedule(&ScheduledActivation{
+		PropertyID: "prp_1", ContractID: "ctr_1", GroupID: "grp_1", PropertyVersion: 3, Network: NetworkProduction,
+	})
+	assert.NoError(t, err)
+
+	second, err := scheduler.Schedule(&ScheduledActivation{
+		PropertyID: "prp_2", ContractID: "ctr_1", GroupID: "grp_1", PropertyVersion: 1, Network: NetworkProduction,
+	})
+	assert.NoError(t, err)
+
+	ran, err := scheduler.RunDue()
+	assert.NoError(t, err)
+	if assert.Len(t, ran, 1) {
+		assert.Equal(t, first.JobID, ran[0].JobID)
+	}
+	assert.Equal(t, ScheduledActivationStatusRunning, first.Status)
+	assert.Equal(t, StatusPending, first.Outcome.ActivationStatus)
+	assert.Equal(t, ScheduledActivationStatusPending, second.Status)
+	assert.True(t, second.StartedAt.IsZero())
+
+	ran, err = scheduler.RunDue()
+	assert.NoError(t, err)
+	if assert.Len(t, ran, 1) {
+		assert.Equal(t, first.JobID, ran[0].JobID)
+	}
+	assert.Equal(t, ScheduledActivationStatusSucceeded, first.Status)
+	assert.Equal(t, ScheduledActivationStatusPending, second.Status)
+	assert.True(t, gock.IsDone())
+}
+
+func TestActivationScheduler_RunDue_Reconciles(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_1/activations").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activations": {"items": [
+			{"activationId": "atv_2", "activationType": "ACTIVATE", "propertyVersion": 3, "network": "PRODUCTION", "status": "PENDING", "submitDate": "2019-06-03T10:00:02Z"},
+			{"activationId": "atv_1", "activationType": "ACTIVATE", "propertyVersion": 3, "network": "PRODUCTION", "status": "ACTIVE", "submitDate": "2019-06-02T10:00:00Z"}
+		]}}`)
+
+	mock = gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net")
+	mock.
+		Get("/papi/v1/properties/prp_2/activations").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activations": {"items": [
+			{"activationId": "atv_3", "activationType": "ACTIVATE", "propertyVersion": 1, "network": "STAGING", "status": "PENDING", "submitDate": "2019-06-03T10:00:02Z"},
+			{"activationId": "atv_4", "activationType": "ACTIVATE", "propertyVersion": 1, "network": "PRODUCTION", "status": "ACTIVE", "submitDate": "2019-06-02T10:00:00Z"}
+		]}}`)
+
+	Init(config)
+
+	dir, err := ioutil.TempDir("", "scheduler")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2019, 6, 3, 10, 5, 0, 0, time.UTC)
+	startedAt := time.Date(2019, 6, 3, 10, 0, 0, 0, time.UTC)
+
+	store := NewFileActivationJobStore(filepath.Join(dir, "jobs.json"))
+	err = store.Save([]*ScheduledActivation{
+		{
+			JobID: "job_1", PropertyID: "prp_1", ContractID: "ctr_1", GroupID: "grp_1", PropertyVersion: 3,
+			Network: NetworkProduction, Status: ScheduledActivationStatusRunning, StartedAt: startedAt,
+			Outcome: &ScheduledActivationOutcome{},
+		},
+		{
+			JobID: "job_2", PropertyID: "prp_2", ContractID: "ctr_1", GroupID: "grp_1", PropertyVersion: 1,
+			Network: NetworkProduction, Status: ScheduledActivationStatusRunning, StartedAt: startedAt,
+			Outcome: &ScheduledActivationOutcome{},
+		},
+	})
+	assert.NoError(t, err)
+
+	scheduler, err := NewActivationScheduler(store)
+	assert.NoError(t, err)
+	scheduler.now = func() time.Time { return now }
+
+	_, err = scheduler.RunDue()
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	submitted := scheduler.find("job_1")
+	assert.Equal(t, ScheduledActivationStatusRunning, submitted.Status)
+	assert.Equal(t, "atv_2", submitted.Outcome.ActivationID)
+	assert.Equal(t, StatusPending, submitted.Outcome.ActivationStatus)
+
+	interrupted := scheduler.find("job_2")
+	assert.Equal(t, ScheduledActivationStatusFailed, interrupted.Status)
+	assert.Equal(t, "", interrupted.Outcome.ActivationID)
+}
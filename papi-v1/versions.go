@@ -57,6 +57,35 @@ func (versions *Versions) AddVersion(version *Version) {
 	versions.Versions.Items = append(versions.Versions.Items, version)
 }
 
+// FindVersion finds a version by number within the collection
+func (versions *Versions) FindVersion(propertyVersion int) (*Version, error) {
+	for _, version := range versions.Versions.Items {
+		if version.PropertyVersion == propertyVersion {
+			return version, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Unable to find version: %d", propertyVersion)
+}
+
+// GetActiveVersion finds the version currently active on a network within the collection
+//
+// Defaults to NetworkProduction. nil is returned when no version is active.
+func (versions *Versions) GetActiveVersion(network NetworkValue) *Version {
+	for _, version := range versions.Versions.Items {
+		status := version.ProductionStatus
+		if network == NetworkStaging {
+			status = version.StagingStatus
+		}
+
+		if status == StatusActive {
+			return version
+		}
+	}
+
+	return nil
+}
+
 // GetVersions retrieves all versions for a a given property
 //
 // See: Property.GetVersions()
@@ -85,6 +114,10 @@ func (versions *Versions) GetVersions(property *Property) error {
 		return err
 	}
 
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
 	if err = client.BodyJSON(res, versions); err != nil {
 		return err
 	}